@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"userHub/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Config describes how access tokens are signed and how long they live.
+type Config struct {
+	Algorithm string
+	// Secret is the HMAC key used for HS256.
+	Secret []byte
+	// PrivateKeyPEM holds the signing key for RS256 (PKCS#1 or PKCS#8) or EdDSA (PKCS#8).
+	PrivateKeyPEM []byte
+	Issuer        string
+	AccessTTL     time.Duration
+	// RefreshTTL is how long an unused refresh token stays valid.
+	RefreshTTL time.Duration
+	// ChallengeTTL is how long a login may wait for its second factor.
+	ChallengeTTL time.Duration
+}
+
+// purposeMFA marks a token that only answers an MFA challenge.
+const purposeMFA = "mfa"
+
+// Authentication method references (RFC 8176) recorded in the "amr" claim.
+const (
+	amrPassword = "pwd"
+	amrMFA      = "mfa"
+)
+
+type accessClaims struct {
+	jwt.RegisteredClaims
+	TenantID uint        `json:"tid,omitempty"`
+	Role     domain.Role `json:"role,omitempty"`
+	AMR      []string    `json:"amr,omitempty"`
+	// Purpose is empty for access tokens; other tokens are never accepted as one.
+	Purpose string `json:"pur,omitempty"`
+	// Act names the admin impersonating the subject (RFC 8693 section 4.1),
+	// and Impersonation the record that lets the impersonation be ended.
+	Act           *actorClaim `json:"act,omitempty"`
+	Impersonation uint        `json:"imp,omitempty"`
+}
+
+type actorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// jwtManager implements domain.TokenManager
+type jwtManager struct {
+	method       jwt.SigningMethod
+	signKey      any
+	verifyKey    any
+	issuer       string
+	ttl          time.Duration
+	challengeTTL time.Duration
+}
+
+// NewJWTManager creates a TokenManager for the configured algorithm and keys.
+func NewJWTManager(cfg Config) (domain.TokenManager, error) {
+	m := &jwtManager{issuer: cfg.Issuer, ttl: cfg.AccessTTL, challengeTTL: cfg.ChallengeTTL}
+	if m.ttl <= 0 {
+		m.ttl = 15 * time.Minute
+	}
+	if m.challengeTTL <= 0 {
+		m.challengeTTL = 5 * time.Minute
+	}
+
+	switch cfg.Algorithm {
+	case "", AlgHS256:
+		if len(cfg.Secret) < 32 {
+			return nil, errors.New("HS256 secret must be at least 32 bytes")
+		}
+		m.method = jwt.SigningMethodHS256
+		m.signKey = cfg.Secret
+		m.verifyKey = cfg.Secret
+	case AlgRS256:
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(cfg.PrivateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parse RS256 private key: %w", err)
+		}
+		m.method = jwt.SigningMethodRS256
+		m.signKey = key
+		m.verifyKey = &key.PublicKey
+	case AlgEdDSA:
+		key, err := jwt.ParseEdPrivateKeyFromPEM(cfg.PrivateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parse EdDSA private key: %w", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("EdDSA key cannot sign")
+		}
+		m.method = jwt.SigningMethodEdDSA
+		m.signKey = key
+		m.verifyKey = signer.Public()
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	return m, nil
+}
+
+func (m *jwtManager) Issue(p *domain.Principal) (*domain.AuthToken, error) {
+	amr := []string{amrPassword}
+	if p != nil && p.MFA {
+		amr = append(amr, amrMFA)
+	}
+	signed, expiresAt, err := m.sign(p, m.ttl, "", amr)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuthToken{AccessToken: signed, TokenType: "Bearer", ExpiresAt: expiresAt}, nil
+}
+
+func (m *jwtManager) IssueImpersonation(p *domain.Principal, expiresAt time.Time) (*domain.AuthToken, error) {
+	if p == nil || p.ImpersonatorID == 0 || p.ImpersonationID == 0 {
+		return nil, domain.NewInternal("cannot issue impersonation token without an impersonator")
+	}
+	amr := []string{amrPassword}
+	if p.MFA {
+		amr = append(amr, amrMFA)
+	}
+	signed, expiresAt, err := m.sign(p, time.Until(expiresAt), "", amr)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuthToken{AccessToken: signed, TokenType: "Bearer", ExpiresAt: expiresAt}, nil
+}
+
+func (m *jwtManager) Verify(token string) (*domain.Principal, error) {
+	claims, err := m.parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, domain.NewUnauthorized("invalid token")
+	}
+	return principalFromClaims(claims)
+}
+
+func (m *jwtManager) IssueChallenge(p *domain.Principal) (*domain.MFAChallenge, error) {
+	signed, expiresAt, err := m.sign(p, m.challengeTTL, purposeMFA, []string{amrPassword})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.MFAChallenge{Token: signed, ExpiresAt: expiresAt}, nil
+}
+
+func (m *jwtManager) VerifyChallenge(token string) (*domain.Principal, error) {
+	claims, err := m.parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purposeMFA {
+		return nil, domain.NewUnauthorized("invalid MFA token")
+	}
+	return principalFromClaims(claims)
+}
+
+// sign issues a token for p that lives for ttl.
+func (m *jwtManager) sign(p *domain.Principal, ttl time.Duration, purpose string, amr []string) (string, time.Time, error) {
+	if p == nil || p.UserID == 0 {
+		return "", time.Time{}, domain.NewInternal("cannot issue token without a subject")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        newTokenID(),
+			Issuer:    m.issuer,
+			Subject:   strconv.FormatUint(uint64(p.UserID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		TenantID: p.TenantID,
+		Role:     p.Role,
+		AMR:      amr,
+		Purpose:  purpose,
+	}
+	if p.ImpersonatorID != 0 {
+		claims.Act = &actorClaim{Subject: strconv.FormatUint(uint64(p.ImpersonatorID), 10)}
+		claims.Impersonation = p.ImpersonationID
+	}
+
+	signed, err := jwt.NewWithClaims(m.method, claims).SignedString(m.signKey)
+	if err != nil {
+		return "", time.Time{}, domain.NewInternal("failed to sign token")
+	}
+	return signed, expiresAt, nil
+}
+
+// parse checks the signature and registered claims of token.
+func (m *jwtManager) parse(token string) (*accessClaims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{m.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if m.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.issuer))
+	}
+
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return m.verifyKey, nil
+	}, opts...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, domain.NewUnauthorized("token has expired")
+		}
+		return nil, domain.NewUnauthorized("invalid token")
+	}
+	return &claims, nil
+}
+
+func principalFromClaims(claims *accessClaims) (*domain.Principal, error) {
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil || id == 0 {
+		return nil, domain.NewUnauthorized("invalid token subject")
+	}
+
+	tenantID := claims.TenantID
+	if tenantID == 0 {
+		tenantID = domain.DefaultTenantID
+	}
+
+	p := &domain.Principal{UserID: uint(id), TenantID: tenantID, Role: claims.Role}
+	for _, method := range claims.AMR {
+		if method == amrMFA {
+			p.MFA = true
+		}
+	}
+	if claims.Act != nil {
+		actor, err := strconv.ParseUint(claims.Act.Subject, 10, 64)
+		if err != nil || actor == 0 || claims.Impersonation == 0 {
+			return nil, domain.NewUnauthorized("invalid token actor")
+		}
+		p.ImpersonatorID = uint(actor)
+		p.ImpersonationID = claims.Impersonation
+	}
+	return p, nil
+}
+
+func newTokenID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"userHub/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// rotatedKeyBits is the size of the RSA keys Rotate generates.
+const rotatedKeyBits = 2048
+
+// KeySyncInterval is how often a stored KeyRing should be synced. A stored
+// key signs only once it is this old, so every instance publishes it before
+// the first token it signs reaches a client.
+const KeySyncInterval = time.Minute
+
+type ringKey struct {
+	id        string
+	key       *rsa.PrivateKey
+	retiredAt *time.Time
+}
+
+// KeyRing implements domain.KeySet with RS256 keys. The newest key signs;
+// keys it replaced stay published for retain, long enough for every token
+// they signed to expire.
+type KeyRing struct {
+	mu     sync.RWMutex
+	keys   []*ringKey // newest first
+	signer *ringKey
+	retain time.Duration
+
+	store    domain.SigningKeyRepository // nil when the keys live in memory only
+	rotation time.Duration
+}
+
+// NewKeyRing creates a KeyRing from PEM-encoded RSA private keys, the first
+// of which signs. Without keys it starts with a freshly generated one.
+func NewKeyRing(retain time.Duration, pems ...[]byte) (*KeyRing, error) {
+	k := &KeyRing{retain: retain}
+	for i, pem := range pems {
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("parse signing key %d: %w", i+1, err)
+		}
+		k.keys = append(k.keys, &ringKey{id: keyID(&key.PublicKey), key: key})
+	}
+	if len(k.keys) == 0 {
+		if err := k.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	k.signer = k.keys[0]
+	return k, nil
+}
+
+// NewStoredKeyRing creates a KeyRing whose keys are kept in store, so they
+// survive restarts and are shared by every instance. A new key is generated
+// when the newest stored one is older than rotation (0 never rotates); call
+// Sync every KeySyncInterval to rotate and to pick up other instances' keys.
+func NewStoredKeyRing(ctx context.Context, store domain.SigningKeyRepository, retain, rotation time.Duration) (*KeyRing, error) {
+	k := &KeyRing{retain: retain, store: store, rotation: rotation}
+	if err := k.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Sync reloads the keys of a stored KeyRing, first storing a new key if
+// rotation is due, and deletes keys retired more than retain ago. The newest
+// key at least KeySyncInterval old signs, or the oldest one if none is.
+func (k *KeyRing) Sync(ctx context.Context) error {
+	stored, err := k.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("load signing keys: %w", err)
+	}
+	now := time.Now()
+	if len(stored) == 0 || k.rotation > 0 && now.Sub(stored[0].CreatedAt) >= k.rotation {
+		key, err := generateKey()
+		if err != nil {
+			return err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return fmt.Errorf("encode signing key: %w", err)
+		}
+		sk := domain.SigningKey{
+			ID:        keyID(&key.PublicKey),
+			PEM:       pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+			CreatedAt: now.UTC(),
+		}
+		if err := k.store.Create(ctx, &sk); err != nil {
+			return fmt.Errorf("store signing key: %w", err)
+		}
+		stored = append([]domain.SigningKey{sk}, stored...)
+	}
+
+	var keys []*ringKey
+	var stale []string
+	var signer *ringKey
+	for i, sk := range stored {
+		rk := &ringKey{id: sk.ID}
+		if i > 0 {
+			// A key stops signing once its successor may sign.
+			retired := stored[i-1].CreatedAt.Add(KeySyncInterval)
+			if now.Sub(retired) > k.retain {
+				stale = append(stale, sk.ID)
+				continue
+			}
+			rk.retiredAt = &retired
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(sk.PEM)
+		if err != nil {
+			return fmt.Errorf("parse signing key %s: %w", sk.ID, err)
+		}
+		rk.key = key
+		keys = append(keys, rk)
+		if signer == nil && now.Sub(sk.CreatedAt) >= KeySyncInterval {
+			signer = rk
+		}
+	}
+	if signer == nil {
+		signer = keys[len(keys)-1]
+	}
+	if err := k.store.Delete(ctx, stale...); err != nil {
+		return fmt.Errorf("delete retired signing keys: %w", err)
+	}
+
+	k.mu.Lock()
+	k.keys, k.signer = keys, signer
+	k.mu.Unlock()
+	return nil
+}
+
+// Rotate makes a newly generated key the signing key, and drops keys that
+// were retired more than retain ago. Stored KeyRings rotate in Sync instead.
+func (k *KeyRing) Rotate() error {
+	key, err := generateKey()
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	kept := []*ringKey{{id: keyID(&key.PublicKey), key: key}}
+	for _, old := range k.keys {
+		if old.retiredAt == nil {
+			old.retiredAt = &now
+		}
+		if now.Sub(*old.retiredAt) <= k.retain {
+			kept = append(kept, old)
+		}
+	}
+	k.keys = kept
+	k.signer = kept[0]
+	return nil
+}
+
+func generateKey() (*rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rotatedKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("generate signing key: %w", err)
+	}
+	return key, nil
+}
+
+// Sign signs claims with the current key, naming it in the "kid" header.
+func (k *KeyRing) Sign(claims map[string]any) (string, error) {
+	k.mu.RLock()
+	current := k.signer
+	k.mu.RUnlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims(claims))
+	token.Header["kid"] = current.id
+	signed, err := token.SignedString(current.key)
+	if err != nil {
+		return "", domain.NewInternal("failed to sign ID token")
+	}
+	return signed, nil
+}
+
+// PublicKeys returns the current and retained keys, newest first.
+func (k *KeyRing) PublicKeys() []domain.JSONWebKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]domain.JSONWebKey, 0, len(k.keys))
+	for _, rk := range k.keys {
+		n, e := rsaComponents(&rk.key.PublicKey)
+		keys = append(keys, domain.JSONWebKey{Kty: "RSA", Use: "sig", Alg: AlgRS256, Kid: rk.id, N: n, E: e})
+	}
+	return keys
+}
+
+// keyID is the RFC 7638 thumbprint of the public key, so the same key always
+// gets the same ID.
+func keyID(pub *rsa.PublicKey) string {
+	n, e := rsaComponents(pub)
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func rsaComponents(pub *rsa.PublicKey) (n, e string) {
+	return base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+}
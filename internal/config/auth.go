@@ -0,0 +1,65 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"userHub/internal/auth"
+
+	"github.com/joho/godotenv"
+)
+
+// LoadAuthConfig reads the JWT settings from the environment.
+//
+//	JWT_ALGORITHM         HS256 (default), RS256 or EdDSA
+//	JWT_SECRET_KEY        HMAC secret for HS256
+//	JWT_PRIVATE_KEY_FILE  PEM-encoded signing key for RS256/EdDSA
+//	JWT_ISSUER            "iss" claim (default "userhub")
+//	JWT_ACCESS_TTL        access token lifetime (default 15m)
+//	JWT_REFRESH_TTL       refresh token lifetime (default 720h)
+//	MFA_CHALLENGE_TTL     time to enter the second factor at login (default 5m)
+func LoadAuthConfig() auth.Config {
+	godotenv.Load()
+
+	cfg := auth.Config{
+		Algorithm:  getEnv("JWT_ALGORITHM", auth.AlgHS256),
+		Secret:     []byte(os.Getenv("JWT_SECRET_KEY")),
+		Issuer:     getEnv("JWT_ISSUER", "userhub"),
+		AccessTTL:  getDuration("JWT_ACCESS_TTL", 15*time.Minute),
+		RefreshTTL: getDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+
+		ChallengeTTL: getDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+	}
+
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal("Failed to read JWT private key:", err)
+		}
+		cfg.PrivateKeyPEM = pem
+	}
+
+	return cfg
+}
+
+// LoadBootstrapAdmin returns the ADMIN_EMAIL / ADMIN_PASSWORD pair used to
+// seed the first administrator. An empty email disables seeding.
+func LoadBootstrapAdmin() (email, password string) {
+	godotenv.Load()
+	return os.Getenv("ADMIN_EMAIL"), os.Getenv("ADMIN_PASSWORD")
+}
+
+// LoadMFAIssuer reads MFA_ISSUER, the account issuer authenticator apps
+// display next to TOTP codes (default "UserHub").
+func LoadMFAIssuer() string {
+	godotenv.Load()
+	return getEnv("MFA_ISSUER", "UserHub")
+}
+
+// LoadAPIKeyMaxTTL reads API_KEY_MAX_TTL, the longest lifetime an API key
+// may be given and the one used when none is requested (default 8760h).
+func LoadAPIKeyMaxTTL() time.Duration {
+	godotenv.Load()
+	return getDuration("API_KEY_MAX_TTL", 365*24*time.Hour)
+}
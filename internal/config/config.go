@@ -22,7 +22,7 @@ func InitDB() *gorm.DB {
 	dbname := os.Getenv("DB_NAME")
 	var err error
 	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", user, password, host, dbname)
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -31,5 +31,5 @@ func InitDB() *gorm.DB {
 }
 
 func InitMigrations(db *gorm.DB) {
-	db.AutoMigrate(&domain.User{})
+	db.AutoMigrate(&domain.Organization{}, &domain.User{}, &domain.RefreshToken{}, &domain.AuditEvent{}, &domain.EmailChange{}, &domain.EmailVerification{}, &domain.PasswordReset{}, &domain.RecoveryCode{}, &domain.APIKey{}, &domain.OAuthClient{}, &domain.OAuthCode{}, &domain.OAuthToken{}, &domain.Group{}, &domain.GroupMember{}, &domain.GroupSubgroup{}, &domain.Invitation{}, &domain.Impersonation{}, &domain.SigningKey{})
 }
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+
+	"userHub/internal/mail"
+
+	"github.com/joho/godotenv"
+)
+
+// LoadAppBaseURL reads APP_BASE_URL, the front-end address that links in
+// outgoing email point to (default http://localhost:8080).
+func LoadAppBaseURL() string {
+	godotenv.Load()
+	return getEnv("APP_BASE_URL", "http://localhost:8080")
+}
+
+// LoadMailConfig reads the outgoing mail settings from the environment.
+//
+//	MAIL_DRIVER    log (default, tokens redacted), smtp or file
+//	MAIL_FROM      sender address
+//	SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD
+//	MAIL_FILE      file the file driver appends to
+func LoadMailConfig() mail.Config {
+	godotenv.Load()
+	return mail.Config{
+		Driver:       getEnv("MAIL_DRIVER", mail.DriverLog),
+		From:         os.Getenv("MAIL_FROM"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getInt("SMTP_PORT", 587),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		File:         getEnv("MAIL_FILE", "mail.log"),
+	}
+}
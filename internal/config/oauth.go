@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// LoadOAuthTTLs reads the lifetimes of what the OAuth server issues.
+//
+//	OAUTH_CODE_TTL     authorization code lifetime (default 5m)
+//	OAUTH_ACCESS_TTL   access token lifetime (default 1h)
+//	OAUTH_REFRESH_TTL  refresh token lifetime (default 720h)
+func LoadOAuthTTLs() (code, access, refresh time.Duration) {
+	godotenv.Load()
+	return getDuration("OAUTH_CODE_TTL", 5*time.Minute),
+		getDuration("OAUTH_ACCESS_TTL", time.Hour),
+		getDuration("OAUTH_REFRESH_TTL", 30*24*time.Hour)
+}
+
+// OIDCConfig holds the OpenID Connect provider settings.
+type OIDCConfig struct {
+	Issuer        string
+	SigningKeyPEM []byte
+	KeyRotation   time.Duration
+}
+
+// LoadOIDC reads the OpenID Connect provider settings.
+//
+//	OIDC_ISSUER            public base URL, used as "iss" (default http://localhost:8080)
+//	OIDC_SIGNING_KEY_FILE  PEM-encoded RSA key to sign ID tokens (default: keys stored in the database)
+//	OIDC_KEY_ROTATION      how often a new stored key is generated (default 720h, 0 disables)
+func LoadOIDC() OIDCConfig {
+	godotenv.Load()
+
+	cfg := OIDCConfig{
+		Issuer:      getEnv("OIDC_ISSUER", "http://localhost:8080"),
+		KeyRotation: getDuration("OIDC_KEY_ROTATION", 30*24*time.Hour),
+	}
+
+	if path := os.Getenv("OIDC_SIGNING_KEY_FILE"); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal("Failed to read OIDC signing key:", err)
+		}
+		cfg.SigningKeyPEM = pem
+	}
+
+	return cfg
+}
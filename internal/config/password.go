@@ -0,0 +1,53 @@
+package config
+
+import (
+	"time"
+
+	"userHub/pkg/password"
+	"userHub/pkg/validator"
+
+	"github.com/joho/godotenv"
+)
+
+// LoadPasswordParams reads the Argon2id cost parameters from the environment.
+// Raising them makes existing hashes get upgraded on the user's next login.
+//
+//	ARGON2_MEMORY_KIB   memory cost (default 65536)
+//	ARGON2_ITERATIONS   time cost (default 3)
+//	ARGON2_PARALLELISM  lanes (default 2)
+func LoadPasswordParams() password.Params {
+	godotenv.Load()
+
+	p := password.DefaultParams
+	p.Memory = uint32(getInt("ARGON2_MEMORY_KIB", int(p.Memory)))
+	p.Iterations = uint32(getInt("ARGON2_ITERATIONS", int(p.Iterations)))
+	p.Parallelism = uint8(getInt("ARGON2_PARALLELISM", int(p.Parallelism)))
+	return p
+}
+
+// LoadPasswordPolicy reads the password policy from the environment.
+//
+//	PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH
+//	PASSWORD_REQUIRE_UPPER, PASSWORD_REQUIRE_LOWER,
+//	PASSWORD_REQUIRE_DIGIT, PASSWORD_REQUIRE_SYMBOL
+func LoadPasswordPolicy() validator.PasswordPolicy {
+	godotenv.Load()
+
+	p := validator.DefaultPasswordPolicy
+	p.MinLength = getInt("PASSWORD_MIN_LENGTH", p.MinLength)
+	p.MaxLength = getInt("PASSWORD_MAX_LENGTH", p.MaxLength)
+	p.RequireUpper = getBool("PASSWORD_REQUIRE_UPPER", p.RequireUpper)
+	p.RequireLower = getBool("PASSWORD_REQUIRE_LOWER", p.RequireLower)
+	p.RequireDigit = getBool("PASSWORD_REQUIRE_DIGIT", p.RequireDigit)
+	p.RequireSymbol = getBool("PASSWORD_REQUIRE_SYMBOL", p.RequireSymbol)
+	return p
+}
+
+// LoadPasswordReset reads how password reset links behave.
+//
+//	PASSWORD_RESET_TTL    link lifetime (default 30m)
+//	PASSWORD_RESET_LIMIT  links mailed to one user per hour (default 3)
+func LoadPasswordReset() (ttl time.Duration, limit int) {
+	godotenv.Load()
+	return getDuration("PASSWORD_RESET_TTL", 30*time.Minute), getInt("PASSWORD_RESET_LIMIT", 3)
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"crypto/rand"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// LoadUserRetention reads USER_RETENTION, how long soft-deleted users are kept
+// before an admin purge may erase them (default 720h).
+func LoadUserRetention() time.Duration {
+	godotenv.Load()
+	return getDuration("USER_RETENTION", 30*24*time.Hour)
+}
+
+// LoadRequireIfMatch reads REQUIRE_IF_MATCH, whether user updates and deletes
+// must carry an If-Match header (default true).
+func LoadRequireIfMatch() bool {
+	godotenv.Load()
+	return getBool("REQUIRE_IF_MATCH", true)
+}
+
+// LoadEmailChangeTTL reads EMAIL_CHANGE_TTL, how long an email change
+// confirmation link stays valid (default 24h).
+func LoadEmailChangeTTL() time.Duration {
+	godotenv.Load()
+	return getDuration("EMAIL_CHANGE_TTL", 24*time.Hour)
+}
+
+// LoadEmailVerificationTTL reads EMAIL_VERIFICATION_TTL, how long a sign-up
+// verification link stays valid (default 48h).
+func LoadEmailVerificationTTL() time.Duration {
+	godotenv.Load()
+	return getDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+}
+
+// LoadInvitations reads the invitation settings from the environment.
+//
+//	INVITATION_SECRET  HMAC key that signs invitation tokens (default: derived from JWT_SECRET_KEY)
+//	INVITATION_TTL     how long an invitation link stays valid (default 168h)
+//
+// Without either secret a random key is used, so links stop working on restart.
+func LoadInvitations() (secret []byte, ttl time.Duration) {
+	godotenv.Load()
+	secret = getSecret("INVITATION_SECRET", "invitation")
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal("Failed to generate invitation secret:", err)
+		}
+		log.Println("INVITATION_SECRET is not set; invitation links will not survive a restart")
+	}
+	return secret, getDuration("INVITATION_TTL", 7*24*time.Hour)
+}
+
+// LoadImpersonationTTL reads IMPERSONATION_TTL, how long an admin's
+// impersonation token stays valid (default 15m).
+func LoadImpersonationTTL() time.Duration {
+	godotenv.Load()
+	return getDuration("IMPERSONATION_TTL", 15*time.Minute)
+}
+
+// LoadCursorSecret reads CURSOR_SECRET, the HMAC key that signs list
+// pagination cursors (default: derived from JWT_SECRET_KEY). Without either a
+// random key is used, so cursors stop working on restart.
+func LoadCursorSecret() []byte {
+	godotenv.Load()
+	secret := getSecret("CURSOR_SECRET", "cursor")
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal("Failed to generate cursor secret:", err)
+		}
+	}
+	return secret
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// Principal identifies the authenticated caller of a request.
+type Principal struct {
+    UserID   uint
+    TenantID uint
+    Role     Role
+    // MFA reports whether the session was established with a second factor.
+    MFA bool
+    // APIKeyID is set when the request was made with an API key, which then
+    // limits it to Scopes.
+    APIKeyID uint
+    Scopes   []Scope
+    // ImpersonatorID is set when an admin acts as UserID with an
+    // impersonation token; ImpersonationID names that impersonation.
+    ImpersonatorID  uint
+    ImpersonationID uint
+}
+
+// Impersonated reports whether an admin is acting as the principal.
+func (p *Principal) Impersonated() bool {
+    return p.ImpersonatorID != 0
+}
+
+// HasScope reports whether the principal may act within s. Only API keys
+// are scoped; sessions may do whatever their role allows.
+func (p *Principal) HasScope(s Scope) bool {
+    if p.APIKeyID == 0 {
+        return true
+    }
+    for _, granted := range p.Scopes {
+        if granted == s {
+            return true
+        }
+    }
+    return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+    return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal attached by the auth middleware, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+    p, ok := ctx.Value(principalKey{}).(*Principal)
+    return p, ok && p != nil
+}
+
+// ActorFromContext returns the ID of the user responsible for the request
+// in ctx: the admin when one is impersonating, the signed-in user otherwise,
+// or 0 if nobody is signed in.
+func ActorFromContext(ctx context.Context) uint {
+    if p, ok := PrincipalFromContext(ctx); ok {
+        if p.Impersonated() {
+            return p.ImpersonatorID
+        }
+        return p.UserID
+    }
+    return 0
+}
+
+// AuthToken is a signed access token handed out after a successful login,
+// optionally paired with a refresh token.
+type AuthToken struct {
+    AccessToken  string
+    TokenType    string
+    ExpiresAt    time.Time
+    RefreshToken string
+}
+
+// MFAChallenge is handed out instead of tokens when the password was right
+// but the account also needs a second factor. Token is sent back with the code.
+type MFAChallenge struct {
+    Token     string
+    ExpiresAt time.Time
+}
+
+// LoginResult is the outcome of a password login: either Token or Challenge is set.
+type LoginResult struct {
+    Token     *AuthToken
+    Challenge *MFAChallenge
+}
+
+// TokenManager issues and verifies signed access tokens.
+type TokenManager interface {
+    Issue(p *Principal) (*AuthToken, error)
+    Verify(token string) (*Principal, error)
+    // IssueChallenge signs a short-lived token proving only that p passed the
+    // password step of a login. Verify rejects it as an access token.
+    IssueChallenge(p *Principal) (*MFAChallenge, error)
+    VerifyChallenge(token string) (*Principal, error)
+    // IssueImpersonation signs an access token for a principal with an
+    // ImpersonatorID and ImpersonationID that expires at expiresAt.
+    IssueImpersonation(p *Principal, expiresAt time.Time) (*AuthToken, error)
+}
+
+// CredentialVerifier checks login credentials and returns the matching user.
+type CredentialVerifier interface {
+    VerifyCredentials(ctx context.Context, email, password string) (*User, error)
+}
+
+// AuthService is the authentication contract.
+type AuthService interface {
+    Login(ctx context.Context, email, password string) (*LoginResult, error)
+    // VerifyMFA completes a challenged login with a TOTP or recovery code.
+    VerifyMFA(ctx context.Context, challenge, code string) (*AuthToken, error)
+    Authenticate(ctx context.Context, token string) (*Principal, error)
+    Refresh(ctx context.Context, refreshToken string) (*AuthToken, error)
+    Logout(ctx context.Context, refreshToken string) error
+    RevokeAllSessions(ctx context.Context, userID uint) error
+}
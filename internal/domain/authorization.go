@@ -0,0 +1,90 @@
+package domain
+
+import "context"
+
+// Role is a named set of permissions held by a user.
+type Role string
+
+const (
+    RoleAdmin   Role = "admin"
+    RoleManager Role = "manager"
+    RoleMember  Role = "member"
+)
+
+// Permission is an action that can be granted to a role.
+type Permission string
+
+const (
+    PermUsersRead        Permission = "users:read"
+    PermUsersList        Permission = "users:list"
+    PermUsersUpdate      Permission = "users:update"
+    PermUsersDelete      Permission = "users:delete"
+    PermUsersRestore     Permission = "users:restore"
+    PermUsersPurge       Permission = "users:purge"
+    PermUsersAssignRole  Permission = "users:assign_role"
+    PermSessionsRevoke   Permission = "sessions:revoke"
+    PermOrgsManage       Permission = "orgs:manage"
+    PermAuditRead        Permission = "audit:read"
+    PermMFAPolicy        Permission = "mfa:policy"
+    PermAPIKeysManage    Permission = "api_keys:manage"
+    PermOAuthClients     Permission = "oauth_clients:manage"
+    PermGroupsRead       Permission = "groups:read"
+    PermGroupsManage     Permission = "groups:manage"
+    PermUsersInvite      Permission = "users:invite"
+    PermUsersImpersonate Permission = "users:impersonate"
+)
+
+// rolePermissions grants permissions over any user record.
+var rolePermissions = map[Role][]Permission{
+    RoleAdmin: {
+        PermUsersRead, PermUsersList, PermUsersUpdate, PermUsersDelete,
+        PermUsersRestore, PermUsersPurge, PermUsersAssignRole, PermSessionsRevoke, PermOrgsManage, PermAuditRead,
+        PermMFAPolicy, PermAPIKeysManage, PermOAuthClients, PermGroupsRead, PermGroupsManage, PermUsersInvite,
+        PermUsersImpersonate,
+    },
+    RoleManager: {PermUsersRead, PermUsersList, PermUsersUpdate, PermGroupsRead},
+    RoleMember:  {},
+}
+
+// selfPermissions are granted to every authenticated user over their own record.
+var selfPermissions = []Permission{PermUsersRead, PermUsersUpdate, PermSessionsRevoke, PermAPIKeysManage}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+    _, ok := rolePermissions[r]
+    return ok
+}
+
+// roleRank orders roles by how much they grant.
+var roleRank = map[Role]int{RoleMember: 1, RoleManager: 2, RoleAdmin: 3}
+
+// Outranks reports whether r grants more than other.
+func (r Role) Outranks(other Role) bool {
+    return roleRank[r] > roleRank[other]
+}
+
+// Can reports whether r grants p over any user.
+func (r Role) Can(p Permission) bool {
+    return hasPermission(rolePermissions[r], p)
+}
+
+// CanOnSelf reports whether p is granted to every user over their own record.
+func CanOnSelf(p Permission) bool {
+    return hasPermission(selfPermissions, p)
+}
+
+func hasPermission(perms []Permission, p Permission) bool {
+    for _, candidate := range perms {
+        if candidate == p {
+            return true
+        }
+    }
+    return false
+}
+
+// Authorizer decides whether the principal in ctx may perform an action.
+type Authorizer interface {
+    // Authorize returns a forbidden error unless the caller may perform action
+    // on the user targetID. Use targetID 0 for collection-level actions.
+    Authorize(ctx context.Context, action Permission, targetID uint) error
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// Credential is a user's password login secret.
+// PasswordHash is a PHC string, so the algorithm and its parameters
+// travel with the hash (e.g. "$argon2id$v=19$m=65536,t=3,p=2$...").
+type Credential struct {
+    PasswordHash      string
+    PasswordChangedAt *time.Time
+
+    // TOTPSecret is the RFC 6238 secret of a confirmed enrollment;
+    // TOTPPendingSecret one that still awaits its first valid code.
+    // The server must know the secret to check codes, so it cannot be hashed.
+    TOTPSecret        string `gorm:"size:64"`
+    TOTPPendingSecret string `gorm:"size:64"`
+    // TOTPLastStep is the time step of the last accepted code; a code is
+    // never accepted twice.
+    TOTPLastStep int64
+    MFAEnabledAt *time.Time
+    // MFAFailedAttempts counts wrong codes since the last correct one.
+    // Too many lock the second factor until MFALockedUntil.
+    MFAFailedAttempts int
+    MFALockedUntil    *time.Time
+}
+
+// HasPassword reports whether the user can log in with a password.
+func (c Credential) HasPassword() bool {
+    return c.PasswordHash != ""
+}
+
+// MFAEnabled reports whether logging in requires a second factor.
+func (c Credential) MFAEnabled() bool {
+    return c.MFAEnabledAt != nil && c.TOTPSecret != ""
+}
+
+// WithPassword returns c with the password of next, keeping the second factor.
+func (c Credential) WithPassword(next Credential) Credential {
+    c.PasswordHash = next.PasswordHash
+    c.PasswordChangedAt = next.PasswordChangedAt
+    return c
+}
+
+// PasswordHasher hashes passwords and checks them against stored hashes.
+type PasswordHasher interface {
+    Hash(password string) (string, error)
+    // Verify reports needsRehash when the stored hash uses outdated parameters.
+    Verify(password, encoded string) (match bool, needsRehash bool, err error)
+}
+
+// PasswordService manages password credentials.
+type PasswordService interface {
+    CredentialVerifier
+    NewCredential(password string) (Credential, error)
+    ChangePassword(ctx context.Context, userID uint, current, next string) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// EmailChange is a pending request to move a user to a new email address.
+// The confirmation token is mailed to NewEmail and only its SHA-256 hash is
+// stored. OldEmail pins the request to the address it was made from, so a
+// token goes stale once the email has changed by other means.
+type EmailChange struct {
+    ID         uint
+    UserID     uint `gorm:"index"`
+    TenantID   uint
+    OldEmail   string `gorm:"size:255"`
+    NewEmail   string `gorm:"size:255"`
+    TokenHash  string `gorm:"size:64;uniqueIndex"`
+    ExpiresAt  time.Time
+    CreatedAt  time.Time
+    ConsumedAt *time.Time
+}
+
+// EmailChangeRepository is the persistence contract for email change requests.
+type EmailChangeRepository interface {
+    Create(ctx context.Context, change *EmailChange) (*EmailChange, error)
+    GetByHash(ctx context.Context, hash string) (*EmailChange, error)
+    // MarkConsumed flags a pending request as used. It returns a conflict
+    // error if the request was already consumed, so a token works only once.
+    MarkConsumed(ctx context.Context, id uint, at time.Time) error
+    // ConsumeAllForUser discards every pending request of the user.
+    ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error
+}
+
+// EmailChangeService moves users to a new email address once they prove they own it.
+type EmailChangeService interface {
+    // Request mails a confirmation token to newEmail. Earlier pending requests are discarded.
+    Request(ctx context.Context, userID uint, newEmail string) error
+    // Confirm swaps the address and notifies the old one.
+    Confirm(ctx context.Context, token string) (*User, error)
+}
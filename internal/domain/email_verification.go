@@ -0,0 +1,45 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// EmailVerification is a pending proof that a user owns their email address.
+// Only the SHA-256 hash of the mailed token is stored. Email pins the token to
+// the address it was sent to, so it goes stale if the address changes.
+type EmailVerification struct {
+    ID         uint
+    UserID     uint `gorm:"index"`
+    TenantID   uint
+    Email      string `gorm:"size:255"`
+    TokenHash  string `gorm:"size:64;uniqueIndex"`
+    ExpiresAt  time.Time
+    CreatedAt  time.Time
+    ConsumedAt *time.Time
+}
+
+// EmailVerificationRepository is the persistence contract for verification tokens.
+type EmailVerificationRepository interface {
+    Create(ctx context.Context, v *EmailVerification) (*EmailVerification, error)
+    GetByHash(ctx context.Context, hash string) (*EmailVerification, error)
+    // MarkConsumed flags an unused token as used. It returns a conflict error
+    // if the token was already consumed, so a token works only once.
+    MarkConsumed(ctx context.Context, id uint, at time.Time) error
+    // ConsumeAllForUser discards every outstanding token of the user.
+    ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error
+}
+
+// EmailVerifier mails a verification link to a newly created user.
+type EmailVerifier interface {
+    SendVerification(ctx context.Context, user *User) error
+}
+
+// EmailVerificationService is the business logic contract for email verification.
+type EmailVerificationService interface {
+    EmailVerifier
+    // Resend mails a fresh link and invalidates earlier ones.
+    Resend(ctx context.Context, userID uint) error
+    // Confirm marks the address the token was sent to as verified.
+    Confirm(ctx context.Context, token string) (*User, error)
+}
@@ -5,10 +5,14 @@ import "fmt"
 type ErrorCode string
 
 const (
-    CodeValidation ErrorCode = "VALIDATION_ERROR"
-    CodeNotFound   ErrorCode = "NOT_FOUND"
-    CodeConflict   ErrorCode = "CONFLICT"
-    CodeInternal   ErrorCode = "INTERNAL"
+    CodeValidation           ErrorCode = "VALIDATION_ERROR"
+    CodeNotFound             ErrorCode = "NOT_FOUND"
+    CodeConflict             ErrorCode = "CONFLICT"
+    CodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+    CodeForbidden            ErrorCode = "FORBIDDEN"
+    CodePreconditionFailed   ErrorCode = "PRECONDITION_FAILED"
+    CodePreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+    CodeInternal             ErrorCode = "INTERNAL"
 )
 
 // AppError is a typed error that can be safely returned to HTTP clients.
@@ -41,6 +45,22 @@ func NewConflict(message string) *AppError {
     return &AppError{Code: CodeConflict, Message: message}
 }
 
+func NewUnauthorized(message string) *AppError {
+    return &AppError{Code: CodeUnauthorized, Message: message}
+}
+
+func NewForbidden(message string) *AppError {
+    return &AppError{Code: CodeForbidden, Message: message}
+}
+
+func NewPreconditionFailed(message string) *AppError {
+    return &AppError{Code: CodePreconditionFailed, Message: message}
+}
+
+func NewPreconditionRequired(message string) *AppError {
+    return &AppError{Code: CodePreconditionRequired, Message: message}
+}
+
 func NewInternal(message string) *AppError {
     return &AppError{Code: CodeInternal, Message: message}
 }
@@ -0,0 +1,89 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// Group is a named set of users within an organization. Groups can contain
+// other groups: the members of a subgroup are members of every group that
+// contains it. Identity providers keep groups in sync through SCIM.
+type Group struct {
+    ID       uint
+    TenantID uint   `gorm:"not null;uniqueIndex:idx_groups_tenant_name"`
+    Name     string `gorm:"size:255;uniqueIndex:idx_groups_tenant_name"`
+
+    // Role, when set, is granted to every member of the group, direct or
+    // through a subgroup, on top of their own role.
+    Role Role `gorm:"size:20"`
+
+    // MemberIDs are the users directly in the group and SubgroupIDs the
+    // groups it contains, both in ascending order. Stores keep them in
+    // GroupMember and GroupSubgroup rows.
+    MemberIDs   []uint `gorm:"-"`
+    SubgroupIDs []uint `gorm:"-"`
+
+    CreatedAt time.Time
+    UpdatedAt time.Time
+}
+
+// GroupMember puts a user in a group.
+type GroupMember struct {
+    GroupID uint `gorm:"primaryKey"`
+    UserID  uint `gorm:"primaryKey;index"`
+}
+
+// GroupSubgroup nests one group in another.
+type GroupSubgroup struct {
+    GroupID    uint `gorm:"primaryKey"`
+    SubgroupID uint `gorm:"primaryKey;index"`
+}
+
+// GroupMembership is a group a user belongs to. Direct is false when the
+// user is only a member through one of its subgroups.
+type GroupMembership struct {
+    Group  *Group
+    Direct bool
+}
+
+// GroupRepository is the persistence contract for groups.
+// Implementations scope every call to the tenant in ctx (see WithTenant).
+type GroupRepository interface {
+    Create(ctx context.Context, group *Group) (*Group, error)
+    GetByID(ctx context.Context, id uint) (*Group, error)
+    // List pages through the groups in ID order.
+    List(ctx context.Context, page, limit int) (groups []*Group, total int64, err error)
+    // Update renames the group, sets its role and replaces its members and
+    // subgroups.
+    Update(ctx context.Context, group *Group) (*Group, error)
+    // Delete removes the group and takes it out of the groups containing it.
+    Delete(ctx context.Context, id uint) error
+
+    // AddMembers adds users and subgroups to a group, skipping those
+    // already in it. RemoveMembers takes them out, ignoring those that
+    // are not.
+    AddMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*Group, error)
+    RemoveMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*Group, error)
+
+    // ListByMember returns the groups userID is directly in, and
+    // ListBySubgroup the groups directly containing any of subgroupIDs,
+    // both in ID order.
+    ListByMember(ctx context.Context, userID uint) ([]*Group, error)
+    ListBySubgroup(ctx context.Context, subgroupIDs []uint) ([]*Group, error)
+}
+
+// GroupService is the business logic contract for groups.
+type GroupService interface {
+    Create(ctx context.Context, group *Group) (*Group, error)
+    GetByID(ctx context.Context, id uint) (*Group, error)
+    List(ctx context.Context, page, limit int) (groups []*Group, total int64, err error)
+    Update(ctx context.Context, group *Group) (*Group, error)
+    Delete(ctx context.Context, id uint) error
+    AddMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*Group, error)
+    RemoveMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*Group, error)
+
+    // ListForUser returns every group userID belongs to, directly or
+    // through subgroups, in ID order, and the role the user effectively
+    // holds: the highest of their own role and those of these groups.
+    ListForUser(ctx context.Context, userID uint) ([]GroupMembership, Role, error)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// Impersonation records an admin acting as another user, so support can see
+// the API exactly as that user does. Tokens minted for it name its ID and
+// stop working once it has ended or expired.
+type Impersonation struct {
+    ID             uint
+    TenantID       uint   `gorm:"index"`
+    ImpersonatorID uint   `gorm:"index"`
+    UserID         uint   `gorm:"index"`
+    Reason         string `gorm:"size:255"`
+    ExpiresAt      time.Time
+    CreatedAt      time.Time
+    EndedAt        *time.Time
+}
+
+// ActiveAt reports whether tokens for the impersonation are valid at t.
+func (i *Impersonation) ActiveAt(t time.Time) bool {
+    return i.EndedAt == nil && t.Before(i.ExpiresAt)
+}
+
+// ImpersonationRepository is the persistence contract for impersonations.
+// Implementations scope every call to the tenant in ctx (see WithTenant).
+type ImpersonationRepository interface {
+    Create(ctx context.Context, imp *Impersonation) (*Impersonation, error)
+    GetByID(ctx context.Context, id uint) (*Impersonation, error)
+    // MarkEnded returns a conflict error if the impersonation already ended,
+    // so it ends only once.
+    MarkEnded(ctx context.Context, id uint, at time.Time) error
+}
+
+// ImpersonationService lets admins act as another user for a short while.
+type ImpersonationService interface {
+    // Start mints a short-lived access token that acts as userID on behalf
+    // of the caller. reason is kept in the audit log.
+    Start(ctx context.Context, userID uint, reason string) (*Impersonation, *AuthToken, error)
+    // End ends the impersonation the caller's token belongs to.
+    End(ctx context.Context) (*Impersonation, error)
+}
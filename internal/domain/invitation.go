@@ -0,0 +1,80 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// InvitationStatus is where an invitation is in its life cycle.
+type InvitationStatus string
+
+const (
+    InvitationPending  InvitationStatus = "pending"
+    InvitationAccepted InvitationStatus = "accepted"
+    InvitationRevoked  InvitationStatus = "revoked"
+    // InvitationExpired is never stored: a pending invitation is expired
+    // once ExpiresAt has passed.
+    InvitationExpired InvitationStatus = "expired"
+)
+
+// Invitation asks someone to join an organization with a given role. The
+// mailed token is signed and carries its own expiry; only its SHA-256 hash
+// is stored, so resending an invitation invalidates the earlier link.
+type Invitation struct {
+    ID        uint
+    TenantID  uint   `gorm:"index:idx_invitations_tenant_email"`
+    Email     string `gorm:"size:255;index:idx_invitations_tenant_email"`
+    Role      Role   `gorm:"size:20"`
+    InviterID uint
+    TokenHash string           `gorm:"size:64;uniqueIndex"`
+    Status    InvitationStatus `gorm:"size:20"`
+    ExpiresAt time.Time
+    CreatedAt time.Time
+    UpdatedAt time.Time
+
+    // UserID is the user created by accepting the invitation.
+    UserID     uint
+    AcceptedAt *time.Time
+    RevokedAt  *time.Time
+}
+
+// StatusAt reports the invitation's status at t.
+func (i *Invitation) StatusAt(t time.Time) InvitationStatus {
+    if i.Status == InvitationPending && !t.Before(i.ExpiresAt) {
+        return InvitationExpired
+    }
+    return i.Status
+}
+
+// InvitationRepository is the persistence contract for invitations.
+// Implementations scope every call but GetByHash to the tenant in ctx (see
+// WithTenant); the token decides the tenant of the invitation it names.
+type InvitationRepository interface {
+    Create(ctx context.Context, inv *Invitation) (*Invitation, error)
+    GetByID(ctx context.Context, id uint) (*Invitation, error)
+    GetByHash(ctx context.Context, hash string) (*Invitation, error)
+    // GetPendingByEmail returns the newest pending invitation for email,
+    // expired or not.
+    GetPendingByEmail(ctx context.Context, email string) (*Invitation, error)
+    // List pages through invitations, newest first.
+    List(ctx context.Context, page, limit int) (invitations []*Invitation, total int64, err error)
+    // Reissue replaces the token of a pending invitation and extends it.
+    Reissue(ctx context.Context, id uint, tokenHash string, expiresAt time.Time) (*Invitation, error)
+    // MarkAccepted and MarkRevoked end a pending invitation. They return a
+    // conflict error if it is no longer pending, so it ends only once.
+    MarkAccepted(ctx context.Context, id, userID uint, at time.Time) error
+    MarkRevoked(ctx context.Context, id uint, at time.Time) error
+}
+
+// InvitationService lets admins invite people by email.
+type InvitationService interface {
+    // Create mails an invitation to join the caller's organization as role.
+    Create(ctx context.Context, email string, role Role) (*Invitation, error)
+    List(ctx context.Context, page, limit int) (invitations []*Invitation, total int64, err error)
+    Revoke(ctx context.Context, id uint) (*Invitation, error)
+    // Resend mails a fresh link, which also revives an expired invitation.
+    Resend(ctx context.Context, id uint) (*Invitation, error)
+    // Accept creates the invited user from user, which supplies the name,
+    // gender and credential; the email address counts as verified.
+    Accept(ctx context.Context, token string, user *User) (*User, error)
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+// Message is a plain-text email.
+type Message struct {
+    To      string
+    Subject string
+    Body    string
+}
+
+// Mailer delivers email. Implementations live in internal/mail.
+type Mailer interface {
+    Send(ctx context.Context, msg Message) error
+}
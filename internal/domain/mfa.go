@@ -0,0 +1,56 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// RecoveryCode is a one-time code that stands in for a TOTP code when the
+// authenticator is lost. Only its SHA-256 hash is stored.
+type RecoveryCode struct {
+    ID       uint
+    UserID   uint   `gorm:"index"`
+    CodeHash string `gorm:"size:64;index"`
+    UsedAt   *time.Time
+}
+
+// RecoveryCodeRepository is the persistence contract for recovery codes.
+type RecoveryCodeRepository interface {
+    // Replace discards the user's codes and stores the given hashes instead.
+    Replace(ctx context.Context, userID uint, hashes []string) error
+    // Consume marks an unused code as used, or returns a not-found error.
+    Consume(ctx context.Context, userID uint, hash string, at time.Time) error
+}
+
+// TOTPEnrollment is what an authenticator app needs to start producing codes.
+type TOTPEnrollment struct {
+    Secret string
+    URI    string
+}
+
+// SecondFactor checks the one-time codes of users who enabled MFA.
+type SecondFactor interface {
+    // VerifyCode accepts a current TOTP code or an unused recovery code and
+    // returns the user as updated by the attempt.
+    VerifyCode(ctx context.Context, user *User, code string) (*User, error)
+}
+
+// MFAService manages multi-factor authentication. Enrollment methods act on
+// the caller in ctx.
+type MFAService interface {
+    SecondFactor
+    // Enroll creates a new pending TOTP secret for the caller.
+    Enroll(ctx context.Context) (*TOTPEnrollment, error)
+    // Confirm enables MFA once code proves the authenticator works, and
+    // returns a fresh set of recovery codes.
+    Confirm(ctx context.Context, code string) ([]string, error)
+    Disable(ctx context.Context, code string) error
+    RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error)
+
+    // Policy returns the roles of the caller's organization that require MFA.
+    Policy(ctx context.Context) ([]Role, error)
+    SetPolicy(ctx context.Context, roles []Role) ([]Role, error)
+    // CheckPolicy returns a forbidden error if the caller's role requires MFA
+    // but their session was established without it.
+    CheckPolicy(ctx context.Context) error
+}
@@ -0,0 +1,243 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// Grant types accepted by the OAuth token endpoint.
+const (
+    GrantAuthorizationCode = "authorization_code"
+    GrantRefreshToken      = "refresh_token"
+    GrantClientCredentials = "client_credentials"
+)
+
+// OAuth error codes (RFC 6749 sections 5.2 and 4.1.2.1, RFC 6750 section 3.1).
+const (
+    OAuthInvalidRequest          = "invalid_request"
+    OAuthInvalidClient           = "invalid_client"
+    OAuthInvalidGrant            = "invalid_grant"
+    OAuthUnauthorizedClient      = "unauthorized_client"
+    OAuthUnsupportedGrantType    = "unsupported_grant_type"
+    OAuthUnsupportedResponseType = "unsupported_response_type"
+    OAuthInvalidScope            = "invalid_scope"
+    OAuthAccessDenied            = "access_denied"
+    OAuthServerError             = "server_error"
+    OAuthInvalidToken            = "invalid_token"
+    OAuthInsufficientScope       = "insufficient_scope"
+)
+
+// OAuthError is an error reported to OAuth clients in the RFC 6749 format
+// rather than the API's own envelope.
+type OAuthError struct {
+    Code        string
+    Description string
+}
+
+func (e *OAuthError) Error() string {
+    return e.Code + ": " + e.Description
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+    return &OAuthError{Code: code, Description: description}
+}
+
+// OAuthClient is an application registered to obtain tokens from userHub.
+// Public clients (single-page and native apps) have no secret and must use PKCE.
+type OAuthClient struct {
+    ID           uint
+    TenantID     uint     `gorm:"index"`
+    ClientID     string   `gorm:"size:64;uniqueIndex"`
+    SecretHash   string   `gorm:"size:64"`
+    Name         string   `gorm:"size:100"`
+    RedirectURIs []string `gorm:"serializer:json"`
+    GrantTypes   []string `gorm:"serializer:json"`
+    Scopes       []string `gorm:"serializer:json"`
+    CreatedAt    time.Time
+}
+
+func (OAuthClient) TableName() string { return "oauth_clients" }
+
+// Confidential reports whether the client authenticates with a secret.
+func (c *OAuthClient) Confidential() bool {
+    return c.SecretHash != ""
+}
+
+// AllowsGrant reports whether the client was registered for grant.
+func (c *OAuthClient) AllowsGrant(grant string) bool {
+    return containsString(c.GrantTypes, grant)
+}
+
+// AllowsRedirect reports whether uri exactly matches a registered redirect URI.
+func (c *OAuthClient) AllowsRedirect(uri string) bool {
+    return containsString(c.RedirectURIs, uri)
+}
+
+// OAuthCode is a single-use authorization code bound to the client, redirect
+// URI and PKCE challenge of the request that produced it.
+type OAuthCode struct {
+    ID            uint
+    CodeHash      string `gorm:"size:64;uniqueIndex"`
+    ClientID      string `gorm:"size:64"`
+    UserID        uint
+    TenantID      uint
+    RedirectURI   string `gorm:"size:2048"`
+    Scope         string `gorm:"size:1024"`
+    CodeChallenge string `gorm:"size:128"`
+    Nonce         string `gorm:"size:255"`
+    ExpiresAt     time.Time
+    CreatedAt     time.Time
+    ConsumedAt    *time.Time
+}
+
+func (OAuthCode) TableName() string { return "oauth_codes" }
+
+// OAuthTokenKind tells access tokens from refresh tokens.
+type OAuthTokenKind string
+
+const (
+    OAuthAccessToken  OAuthTokenKind = "access"
+    OAuthRefreshToken OAuthTokenKind = "refresh"
+)
+
+// OAuthToken is an opaque token issued to a client. Only its SHA-256 hash is
+// stored. Tokens from the same authorization share a FamilyID so revoking or
+// replaying a refresh token can end all of them. UserID is 0 for tokens a
+// client obtained for itself.
+type OAuthToken struct {
+    ID        uint
+    TokenHash string         `gorm:"size:64;uniqueIndex"`
+    Kind      OAuthTokenKind `gorm:"size:16"`
+    ClientID  string         `gorm:"size:64;index"`
+    UserID    uint           `gorm:"index"`
+    TenantID  uint
+    FamilyID  string `gorm:"size:64;index"`
+    Scope     string `gorm:"size:1024"`
+    ExpiresAt time.Time
+    CreatedAt time.Time
+    RevokedAt *time.Time
+}
+
+func (OAuthToken) TableName() string { return "oauth_tokens" }
+
+// Active reports whether the token can still be used at t.
+func (t *OAuthToken) Active(at time.Time) bool {
+    return t.RevokedAt == nil && at.Before(t.ExpiresAt)
+}
+
+// OAuthClientRepository is the persistence contract for OAuth clients.
+type OAuthClientRepository interface {
+    Create(ctx context.Context, client *OAuthClient) (*OAuthClient, error)
+    GetByClientID(ctx context.Context, clientID string) (*OAuthClient, error)
+    ListByTenant(ctx context.Context, tenantID uint) ([]OAuthClient, error)
+    Delete(ctx context.Context, id uint) error
+}
+
+// OAuthCodeRepository is the persistence contract for authorization codes.
+type OAuthCodeRepository interface {
+    Create(ctx context.Context, code *OAuthCode) (*OAuthCode, error)
+    GetByHash(ctx context.Context, hash string) (*OAuthCode, error)
+    // MarkConsumed returns a conflict error if the code was already used.
+    MarkConsumed(ctx context.Context, id uint, at time.Time) error
+}
+
+// OAuthTokenRepository is the persistence contract for OAuth tokens.
+type OAuthTokenRepository interface {
+    Create(ctx context.Context, token *OAuthToken) (*OAuthToken, error)
+    GetByHash(ctx context.Context, hash string) (*OAuthToken, error)
+    // Revoke returns a conflict error if the token was already revoked,
+    // which makes refresh token rotation race-free.
+    Revoke(ctx context.Context, id uint, at time.Time) error
+    RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+    RevokeAllForClient(ctx context.Context, clientID string, at time.Time) error
+    RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error
+}
+
+// ClientCredentials authenticate a client at the token, introspection and
+// revocation endpoints. ClientSecret is empty for public clients.
+type ClientCredentials struct {
+    ClientID     string
+    ClientSecret string
+}
+
+// AuthorizeRequest holds the parameters of an authorization request.
+type AuthorizeRequest struct {
+    ClientID            string
+    RedirectURI         string
+    ResponseType        string
+    Scope               string
+    CodeChallenge       string
+    CodeChallengeMethod string
+    // Nonce is copied into the ID token of OpenID Connect requests.
+    Nonce string
+    // State is handed back to the client unchanged.
+    State string
+}
+
+// TokenRequest holds the parameters of a token request.
+type TokenRequest struct {
+    Client       ClientCredentials
+    GrantType    string
+    Code         string
+    RedirectURI  string
+    CodeVerifier string
+    RefreshToken string
+    Scope        string
+}
+
+// OAuthTokenSet is what the token endpoint returns.
+type OAuthTokenSet struct {
+    AccessToken  string
+    RefreshToken string
+    // IDToken is set when the grant includes the openid scope.
+    IDToken   string
+    Scope     string
+    ExpiresAt time.Time
+}
+
+// TokenIntrospection describes a token to a resource server (RFC 7662).
+type TokenIntrospection struct {
+    Active    bool
+    Scope     string
+    ClientID  string
+    Kind      OAuthTokenKind
+    UserID    uint
+    TenantID  uint
+    Username  string
+    ExpiresAt time.Time
+    IssuedAt  time.Time
+}
+
+// OAuthService is the OAuth 2.0 authorization server contract.
+//
+// Failures a client can act on are returned as *OAuthError. Authorize
+// returns other errors only when the request cannot be trusted to redirect
+// back to the client, such as an unknown client or redirect URI.
+type OAuthService interface {
+    // RegisterClient stores a client in the caller's tenant and returns it with
+    // its plaintext secret, which is shown only once. public clients get none.
+    RegisterClient(ctx context.Context, client *OAuthClient, public bool) (*OAuthClient, string, error)
+    ListClients(ctx context.Context) ([]OAuthClient, error)
+    // DeleteClient removes the client and revokes every token issued to it.
+    DeleteClient(ctx context.Context, clientID string) error
+
+    // CheckAuthorize validates req as Authorize would, without issuing a
+    // code, so that the user can be asked to sign in and consent first. It
+    // returns the client and the scope the code would grant.
+    CheckAuthorize(ctx context.Context, req AuthorizeRequest) (*OAuthClient, string, error)
+    // Authorize issues an authorization code to the user in ctx.
+    Authorize(ctx context.Context, req AuthorizeRequest) (string, error)
+    Token(ctx context.Context, req TokenRequest) (*OAuthTokenSet, error)
+    Introspect(ctx context.Context, client ClientCredentials, token string) (*TokenIntrospection, error)
+    // Revoke revokes a token issued to the client. Unknown tokens are ignored.
+    Revoke(ctx context.Context, client ClientCredentials, token string) error
+}
+
+func containsString(values []string, s string) bool {
+    for _, v := range values {
+        if v == s {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+    "context"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// OpenID Connect scopes. ScopeOpenID makes an OAuth request an OIDC one.
+const (
+    ScopeOpenID  = "openid"
+    ScopeProfile = "profile"
+    ScopeEmail   = "email"
+)
+
+// JSONWebKey is a public key published in the JWKS (RFC 7517).
+type JSONWebKey struct {
+    Kty string
+    Use string
+    Alg string
+    Kid string
+    N   string
+    E   string
+}
+
+// KeySet signs ID tokens with its current key and publishes every key that
+// may still have unexpired tokens.
+type KeySet interface {
+    Sign(claims map[string]any) (string, error)
+    PublicKeys() []JSONWebKey
+}
+
+// SigningKey is a stored ID token signing key, so every instance of userHub
+// signs with and publishes the same keys. ID is the key's "kid".
+type SigningKey struct {
+    ID        string `gorm:"primaryKey;size:64"`
+    PEM       []byte
+    CreatedAt time.Time `gorm:"index"`
+}
+
+// SigningKeyRepository is the persistence contract for signing keys.
+type SigningKeyRepository interface {
+    // List returns every stored key, newest first.
+    List(ctx context.Context) ([]SigningKey, error)
+    Create(ctx context.Context, key *SigningKey) error
+    Delete(ctx context.Context, ids ...string) error
+}
+
+// IDTokenIssuer mints OpenID Connect ID tokens.
+type IDTokenIssuer interface {
+    // IssueIDToken returns a signed ID token for user, addressed to clientID,
+    // with the claims scope grants. nonce is echoed when not empty.
+    IssueIDToken(ctx context.Context, user *User, clientID, scope, nonce string) (string, error)
+}
+
+// OIDCService is the OpenID Connect layer on top of the OAuth server.
+type OIDCService interface {
+    IDTokenIssuer
+    Issuer() string
+    JWKS() []JSONWebKey
+    // UserInfo returns the claims of the user behind an OAuth access token,
+    // limited to the token's scopes.
+    UserInfo(ctx context.Context, accessToken string) (map[string]any, error)
+}
+
+// HasScope reports whether a space-separated scope string contains s.
+func HasScope(scope, s string) bool {
+    for _, granted := range strings.Fields(scope) {
+        if granted == s {
+            return true
+        }
+    }
+    return false
+}
+
+// UserClaims returns the standard claims of u that scope grants.
+func UserClaims(u *User, scope string) map[string]any {
+    claims := map[string]any{"sub": strconv.FormatUint(uint64(u.ID), 10)}
+    if HasScope(scope, ScopeProfile) {
+        claims["name"] = u.Name
+        claims["gender"] = u.Gender
+    }
+    if HasScope(scope, ScopeEmail) {
+        claims["email"] = u.Email
+        claims["email_verified"] = u.EmailVerified()
+    }
+    return claims
+}
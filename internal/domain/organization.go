@@ -0,0 +1,69 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// Organization is a tenant: a customer company whose users are isolated from
+// every other organization. A user is a member of exactly one organization.
+type Organization struct {
+    ID        uint
+    Name      string
+    Slug      string `gorm:"size:64;uniqueIndex"`
+    CreatedAt time.Time
+
+    // MFARequiredRoles lists the roles that may not change users
+    // without a session established with a second factor.
+    MFARequiredRoles []Role `gorm:"serializer:json"`
+}
+
+// RequiresMFA reports whether the organization's policy demands MFA of role.
+func (o *Organization) RequiresMFA(role Role) bool {
+    for _, r := range o.MFARequiredRoles {
+        if r == role {
+            return true
+        }
+    }
+    return false
+}
+
+// DefaultTenantID is the organization used when a request names no tenant.
+// It is also the platform operator: its admins manage the other organizations.
+const DefaultTenantID uint = 1
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx scoped to the given organization.
+// Repositories only see and modify records of that organization.
+func WithTenant(ctx context.Context, tenantID uint) context.Context {
+    return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the organization a request is scoped to, if any.
+func TenantFromContext(ctx context.Context) (uint, bool) {
+    id, ok := ctx.Value(tenantKey{}).(uint)
+    return id, ok && id != 0
+}
+
+// OrganizationRepository is the persistence contract for organizations.
+type OrganizationRepository interface {
+    Create(ctx context.Context, org *Organization) (*Organization, error)
+    GetByID(ctx context.Context, id uint) (*Organization, error)
+    GetBySlug(ctx context.Context, slug string) (*Organization, error)
+    List(ctx context.Context) ([]*Organization, error)
+    Update(ctx context.Context, org *Organization) (*Organization, error)
+    // Delete removes an organization, such as one whose first admin could
+    // not be created.
+    Delete(ctx context.Context, id uint) error
+}
+
+// OrganizationService is the business logic contract for organizations.
+type OrganizationService interface {
+    // Create registers a new organization together with its first admin.
+    Create(ctx context.Context, org *Organization, owner *User) (*Organization, *User, error)
+    GetByID(ctx context.Context, id uint) (*Organization, error)
+    List(ctx context.Context) ([]*Organization, error)
+    // Exists reports whether id names an organization. It performs no authorization.
+    Exists(ctx context.Context, id uint) (bool, error)
+}
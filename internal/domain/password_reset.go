@@ -0,0 +1,42 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// PasswordReset is a single-use token that lets a user set a new password
+// without knowing the old one. Only the SHA-256 hash of the mailed token is
+// stored. Email pins the token to the address it was sent to.
+type PasswordReset struct {
+    ID         uint
+    UserID     uint `gorm:"index:idx_password_resets_user_created"`
+    TenantID   uint
+    Email      string `gorm:"size:255"`
+    TokenHash  string `gorm:"size:64;uniqueIndex"`
+    ExpiresAt  time.Time
+    CreatedAt  time.Time `gorm:"index:idx_password_resets_user_created"`
+    ConsumedAt *time.Time
+}
+
+// PasswordResetRepository is the persistence contract for password reset tokens.
+type PasswordResetRepository interface {
+    Create(ctx context.Context, reset *PasswordReset) (*PasswordReset, error)
+    GetByHash(ctx context.Context, hash string) (*PasswordReset, error)
+    // MarkConsumed flags an unused token as used. It returns a conflict error
+    // if the token was already consumed, so a token works only once.
+    MarkConsumed(ctx context.Context, id uint, at time.Time) error
+    // ConsumeAllForUser discards every outstanding token of the user.
+    ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error
+    // CountSince counts the tokens issued to the user since the given time.
+    CountSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+}
+
+// PasswordResetService lets users who forgot their password set a new one.
+type PasswordResetService interface {
+    // Forgot mails a reset link if email belongs to a user. It never reveals
+    // whether it did, so callers must treat every outcome alike.
+    Forgot(ctx context.Context, email string) error
+    // Reset sets a new password and ends every session of the user.
+    Reset(ctx context.Context, token, newPassword string) error
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+    "context"
+    "time"
+)
+
+// RefreshToken is a long-lived, single-use credential exchanged for new access tokens.
+// Only the SHA-256 hash of the token is stored. Every token issued from the same
+// login shares a FamilyID, so a replayed token can revoke the whole session.
+type RefreshToken struct {
+    ID        uint
+    UserID    uint   `gorm:"index"`
+    TenantID  uint
+    FamilyID  string `gorm:"size:64;index"`
+    // MFA carries over to the access tokens of the session.
+    MFA       bool
+    TokenHash string `gorm:"size:64;uniqueIndex"`
+    ExpiresAt time.Time
+    CreatedAt time.Time
+    RotatedAt *time.Time
+    RevokedAt *time.Time
+}
+
+// RefreshTokenRepository is the persistence contract for refresh tokens.
+type RefreshTokenRepository interface {
+    Create(ctx context.Context, token *RefreshToken) (*RefreshToken, error)
+    GetByHash(ctx context.Context, hash string) (*RefreshToken, error)
+    // MarkRotated flags an active token as used. It returns a conflict error if
+    // the token was already rotated or revoked, which makes rotation race-free.
+    MarkRotated(ctx context.Context, id uint, at time.Time) error
+    RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+    RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error
+}
@@ -1,24 +1,73 @@
 package domain
 
-import "context"
+import (
+    "context"
+    "time"
+
+    "gorm.io/gorm"
+)
 
 // User is the core domain entity.
 // Keep domain types free from transport concerns (no JSON/validation tags).
 type User struct {
-    ID     uint
-    Name   string
-    Email  string
-    Gender string
+    ID       uint
+    TenantID uint   `gorm:"not null;default:1;uniqueIndex:idx_users_tenant_email"`
+    Name     string `gorm:"index:idx_users_search,class:FULLTEXT"`
+    Email    string `gorm:"size:255;uniqueIndex:idx_users_tenant_email;index:idx_users_search,class:FULLTEXT"`
+    Gender   string
+    Role     Role `gorm:"size:20;not null;default:member"`
+
+    // EmailVerifiedAt is set once the user proves they own Email.
+    // Unverified users exist but may be filtered out of listings.
+    EmailVerifiedAt *time.Time
+
+    // Version increases on every update and guards against lost updates:
+    // Update only succeeds if the stored version still matches.
+    Version uint `gorm:"not null;default:1"`
+
+    // CreatedAt and UpdatedAt are set by the repository on Create and on
+    // every Update, Delete or Restore; CreatedBy and UpdatedBy hold the acting user
+    // (see ActorFromContext), 0 for sign-ups and background tasks.
+    CreatedAt time.Time `gorm:"index;autoCreateTime:false"`
+    UpdatedAt time.Time `gorm:"autoUpdateTime:false"`
+    CreatedBy uint
+    UpdatedBy uint
+
+    // Credential is never mapped to a response DTO.
+    Credential Credential `gorm:"embedded"`
+
+    // DeletedAt marks a soft-deleted user. Deleted users are invisible to
+    // every repository method except Restore and Purge.
+    DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 // UserRepository is the persistence contract.
+// Implementations scope every call to the tenant in ctx (see WithTenant).
 type UserRepository interface {
     Create(ctx context.Context, user *User) (*User, error)
     GetByID(ctx context.Context, id uint) (*User, error)
     GetByEmail(ctx context.Context, email string) (*User, error)
+    // Update is a compare-and-swap on user.Version; a stale version yields
+    // a precondition-failed error. On success the returned user has the new version.
     Update(ctx context.Context, user *User) (*User, error)
-    Delete(ctx context.Context, id uint) error
-    List(ctx context.Context, page, limit int, q string) (users []*User, total int64, err error)
+    // UpdateCredential replaces the stored credential prev with next,
+    // leaving Version and the update stamps alone: login bookkeeping such as
+    // rehashes and MFA counters is not an edit of the user. It is a
+    // compare-and-swap on prev's TOTPLastStep and MFAFailedAttempts, so a
+    // TOTP code is accepted once and no failed attempt is lost.
+    UpdateCredential(ctx context.Context, id uint, prev, next Credential) error
+    // Delete soft-deletes the user. A non-zero expectedVersion must match the stored one.
+    Delete(ctx context.Context, id uint, expectedVersion uint) error
+    // List pages through the users matching query in its order.
+    List(ctx context.Context, query UserQuery) (users []*User, total int64, err error)
+    // ListByCursor returns up to query.Limit users matching query that come
+    // after cursor in its direction, in query order either way; more
+    // reports whether further users lie beyond them in that direction.
+    ListByCursor(ctx context.Context, query UserQuery, cursor UserCursor) (users []*User, more bool, err error)
+    // Restore undoes a soft delete.
+    Restore(ctx context.Context, id uint) (*User, error)
+    // Purge permanently erases users soft-deleted before the given time.
+    Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 // UserService is the business logic contract.
@@ -26,6 +75,36 @@ type UserService interface {
     Create(ctx context.Context, user *User) (*User, error)
     GetByID(ctx context.Context, id uint) (*User, error)
     Update(ctx context.Context, user *User) (*User, error)
-    Delete(ctx context.Context, id uint) error
-    List(ctx context.Context, page, limit int, q string) (users []*User, total int64, err error)
+    Delete(ctx context.Context, id uint, expectedVersion uint) error
+    List(ctx context.Context, query UserQuery) (users []*User, total int64, err error)
+    // ListByCursor pages through users by an opaque cursor from an earlier
+    // page, ignoring query.Page; an empty cursor starts at the first user.
+    // The cursor only works with the filters and sort it was issued for.
+    ListByCursor(ctx context.Context, query UserQuery, cursor string) (*UserPage, error)
+    Restore(ctx context.Context, id uint) (*User, error)
+    // Purge erases users whose soft delete is older than the retention window.
+    Purge(ctx context.Context) (int64, error)
+}
+
+// MarkCreated stamps a user about to be stored for the first time as
+// created and updated now by the actor in ctx.
+func (u *User) MarkCreated(ctx context.Context) {
+    u.CreatedAt, u.CreatedBy = changeTime(), ActorFromContext(ctx)
+    u.UpdatedAt, u.UpdatedBy = u.CreatedAt, u.CreatedBy
+}
+
+// MarkUpdated stamps a stored user as updated now by the actor in ctx.
+func (u *User) MarkUpdated(ctx context.Context) {
+    u.UpdatedAt, u.UpdatedBy = changeTime(), ActorFromContext(ctx)
+}
+
+// changeTime is the current time to the millisecond, which is as precise as
+// MySQL keeps it, so every store hands out the same values.
+func changeTime() time.Time {
+    return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// EmailVerified reports whether the user has confirmed their email address.
+func (u *User) EmailVerified() bool {
+    return u.EmailVerifiedAt != nil
 }
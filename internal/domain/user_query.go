@@ -0,0 +1,260 @@
+package domain
+
+import (
+    "cmp"
+    "slices"
+    "strings"
+    "time"
+
+    "userHub/pkg/filter"
+    "userHub/pkg/search"
+)
+
+// UserSortField is a user attribute listings can be ordered by. Its value
+// is also the column it is stored in.
+type UserSortField string
+
+const (
+    UserSortID        UserSortField = "id"
+    UserSortName      UserSortField = "name"
+    UserSortEmail     UserSortField = "email"
+    UserSortGender    UserSortField = "gender"
+    UserSortRole      UserSortField = "role"
+    UserSortCreatedAt UserSortField = "created_at"
+    UserSortUpdatedAt UserSortField = "updated_at"
+)
+
+// sortTimeLayout renders timestamps as sort keys: fixed-width UTC, so that
+// keys order like the times they stand for.
+const sortTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// userSortKeys are the sortable fields and how to read them off a user.
+var userSortKeys = map[UserSortField]func(u *User) string{
+    UserSortName:      func(u *User) string { return u.Name },
+    UserSortEmail:     func(u *User) string { return u.Email },
+    UserSortGender:    func(u *User) string { return u.Gender },
+    UserSortRole:      func(u *User) string { return string(u.Role) },
+    UserSortCreatedAt: func(u *User) string { return u.CreatedAt.UTC().Format(sortTimeLayout) },
+    UserSortUpdatedAt: func(u *User) string { return u.UpdatedAt.UTC().Format(sortTimeLayout) },
+}
+
+// UserSortFields lists the fields users can be sorted by.
+var UserSortFields = []UserSortField{UserSortID, UserSortName, UserSortEmail, UserSortGender, UserSortRole, UserSortCreatedAt, UserSortUpdatedAt}
+
+// Valid reports whether users can be sorted by f.
+func (f UserSortField) Valid() bool {
+    return slices.Contains(UserSortFields, f)
+}
+
+// Key returns u's value of f, except for ID, which cursors carry separately.
+func (f UserSortField) Key(u *User) string {
+    if key, ok := userSortKeys[f]; ok {
+        return key(u)
+    }
+    return ""
+}
+
+// Value turns a key of f back into the value it was read from, for stores
+// that compare keys in SQL: timestamps become a time.Time, and other keys
+// are returned as they are.
+func (f UserSortField) Value(key string) any {
+    if f == UserSortCreatedAt || f == UserSortUpdatedAt {
+        if t, err := time.Parse(sortTimeLayout, key); err == nil {
+            return t
+        }
+    }
+    return key
+}
+
+// UserSort orders a listing by one field.
+type UserSort struct {
+    Field UserSortField
+    Desc  bool
+}
+
+// UserQuery selects, orders and pages users for a listing. Zero-valued
+// filters match every user.
+type UserQuery struct {
+    // Search matches a substring of the name or email.
+    Search string
+    // Verified keeps only users whose email is (or is not) verified.
+    Verified *bool
+    Gender   string
+    // EmailDomain matches the part of the email after the @.
+    EmailDomain string
+    IDs         []uint
+    // CreatedAfter and CreatedBefore, if set, bound CreatedAt exclusively.
+    CreatedAfter  time.Time
+    CreatedBefore time.Time
+    // Filter is a parsed expression over UserFilterFields.
+    Filter filter.Expr
+
+    // Sort orders the users; ties fall back to ascending ID.
+    Sort []UserSort
+
+    // Page is only used by offset listings; Limit by both kinds.
+    Page  int
+    Limit int
+}
+
+// Ordering returns the sort a listing applies, which always ends in ID so
+// that every user has a unique position.
+func (q UserQuery) Ordering() []UserSort {
+    for _, s := range q.Sort {
+        if s.Field == UserSortID {
+            return q.Sort
+        }
+    }
+    return append(append([]UserSort(nil), q.Sort...), UserSort{Field: UserSortID})
+}
+
+// Matches reports whether u passes the query's filters, for stores that
+// filter in memory.
+func (q UserQuery) Matches(u *User) bool {
+    if q.Verified != nil && u.EmailVerified() != *q.Verified {
+        return false
+    }
+    if q.Gender != "" && !strings.EqualFold(u.Gender, q.Gender) {
+        return false
+    }
+    if q.EmailDomain != "" && !strings.HasSuffix(strings.ToLower(u.Email), "@"+strings.ToLower(q.EmailDomain)) {
+        return false
+    }
+    if len(q.IDs) > 0 && !slices.Contains(q.IDs, u.ID) {
+        return false
+    }
+    if !q.CreatedAfter.IsZero() && !u.CreatedAt.After(q.CreatedAfter) {
+        return false
+    }
+    if !q.CreatedBefore.IsZero() && !u.CreatedAt.Before(q.CreatedBefore) {
+        return false
+    }
+    if q.Filter != nil && !filter.Eval(q.Filter, func(field string) any { return userFilterValue(u, field) }) {
+        return false
+    }
+    if search := strings.ToLower(strings.TrimSpace(q.Search)); search != "" {
+        return strings.Contains(strings.ToLower(u.Name), search) || strings.Contains(strings.ToLower(u.Email), search)
+    }
+    return true
+}
+
+// UserFilterFields whitelists the fields filter expressions over users may
+// name.
+var UserFilterFields = filter.Schema{
+    "id":         filter.Number,
+    "name":       filter.String,
+    "email":      filter.String,
+    "gender":     filter.String,
+    "role":       filter.String,
+    "verified":   filter.Bool,
+    "created_at": filter.Time,
+    "updated_at": filter.Time,
+    "created_by": filter.Number,
+    "updated_by": filter.Number,
+}
+
+// userFilterValue returns u's value of a field of UserFilterFields.
+func userFilterValue(u *User, field string) any {
+    switch field {
+    case "id":
+        return float64(u.ID)
+    case "name":
+        return u.Name
+    case "email":
+        return u.Email
+    case "gender":
+        return u.Gender
+    case "role":
+        return string(u.Role)
+    case "verified":
+        return u.EmailVerified()
+    case "created_at":
+        return u.CreatedAt
+    case "updated_at":
+        return u.UpdatedAt
+    case "created_by":
+        return float64(u.CreatedBy)
+    case "updated_by":
+        return float64(u.UpdatedBy)
+    }
+    return nil
+}
+
+// CompareUsers orders a against b by ordering, for stores that sort in
+// memory: negative if a comes first, positive if it comes after.
+func CompareUsers(a, b *User, ordering []UserSort) int {
+    for _, s := range ordering {
+        var order int
+        if s.Field == UserSortID {
+            order = cmp.Compare(a.ID, b.ID)
+        } else {
+            order = collate(s.Field.Key(a), s.Field.Key(b))
+        }
+        if s.Desc {
+            order = -order
+        }
+        if order != 0 {
+            return order
+        }
+    }
+    return 0
+}
+
+// collate compares sort keys the way the database's case- and
+// accent-insensitive collation does; keys it deems equal fall through to the
+// next sort field and finally to the ID.
+func collate(a, b string) int {
+    return strings.Compare(search.Normalize(a), search.Normalize(b))
+}
+
+// UserCursor positions a keyset page of users: those after the user it
+// names in the listing's order, or before it when Backward is set. Keys
+// holds that user's values of the non-ID sort fields. A zero ID starts at
+// the first user (or, going backward, the last). Unlike an offset, it
+// neither skips nor repeats users when others are created or deleted
+// between pages.
+type UserCursor struct {
+    ID       uint
+    Keys     map[UserSortField]string
+    Backward bool
+}
+
+// CursorAt returns the cursor naming u's position in the ordering.
+func CursorAt(u *User, ordering []UserSort, backward bool) UserCursor {
+    c := UserCursor{ID: u.ID, Keys: make(map[UserSortField]string), Backward: backward}
+    for _, s := range ordering {
+        if s.Field != UserSortID {
+            c.Keys[s.Field] = s.Field.Key(u)
+        }
+    }
+    return c
+}
+
+// Compare orders u against the user the cursor names, by ordering:
+// negative if u comes first, positive if it comes after.
+func (c UserCursor) Compare(u *User, ordering []UserSort) int {
+    for _, s := range ordering {
+        var order int
+        if s.Field == UserSortID {
+            order = cmp.Compare(u.ID, c.ID)
+        } else {
+            order = collate(s.Field.Key(u), c.Keys[s.Field])
+        }
+        if s.Desc {
+            order = -order
+        }
+        if order != 0 {
+            return order
+        }
+    }
+    return 0
+}
+
+// UserPage is one page of a cursor listing. Limit is the page size used;
+// Next and Prev are empty at either end of the list.
+type UserPage struct {
+    Users []*User
+    Limit int
+    Next  string
+    Prev  string
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "context"
+
+// UserSearchHit is a user matching a search, with its relevance score.
+// Scores rank the hits of one search against each other; their scale
+// depends on the searcher and means nothing across searches.
+type UserSearchHit struct {
+    User  *User
+    Score float64
+}
+
+// UserSearcher finds users by free text in their name and email, best match
+// first. Implementations scope every call to the tenant in ctx and skip
+// soft-deleted users.
+type UserSearcher interface {
+    Search(ctx context.Context, text string, limit int) ([]UserSearchHit, error)
+}
+
+// UserSearchResult is a search hit as the service returns it.
+type UserSearchResult struct {
+    UserSearchHit
+    // Highlights maps each field that matched ("name", "email") to its
+    // HTML-escaped value with the matching words in <em> tags.
+    Highlights map[string]string
+}
+
+// UserSearchService is the business logic contract for user search.
+type UserSearchService interface {
+    Search(ctx context.Context, text string, limit int) ([]UserSearchResult, error)
+}
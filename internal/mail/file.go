@@ -0,0 +1,51 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+
+	"userHub/internal/domain"
+)
+
+// fileMailer appends every message to a file instead of delivering it.
+type fileMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+// NewFileMailer creates a Mailer that appends rendered messages to cfg.File,
+// separated by blank lines. Useful for local development and end-to-end tests.
+func NewFileMailer(cfg Config) (domain.Mailer, error) {
+	if cfg.File == "" {
+		return nil, errors.New("mail file path is required")
+	}
+	from := cfg.From
+	if from == "" {
+		from = "userhub@localhost"
+	}
+	return &fileMailer{path: cfg.File, from: from}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg domain.Message) error {
+	data, err := render(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, "\r\n\r\n"...)); err != nil {
+		return err
+	}
+	return nil
+}
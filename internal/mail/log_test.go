@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func TestLogMailerRedactsTokens(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	err := NewLogMailer().Send(context.Background(), domain.Message{
+		To:      "ada@example.com",
+		Subject: "Reset your password",
+		Body:    "Open https://app.test/password/reset?token=s3cr3t%2Bvalue to continue.\nOr https://app.test/accept?lang=en&token=other\n",
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.NotContains(t, out, "s3cr3t")
+	assert.NotContains(t, out, "other")
+	assert.Contains(t, out, "https://app.test/password/reset?token=REDACTED to continue.")
+	assert.Contains(t, out, "?lang=en&token=REDACTED\n")
+	assert.Contains(t, out, "to=ada@example.com")
+}
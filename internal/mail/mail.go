@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"fmt"
+
+	"userHub/internal/domain"
+)
+
+// Supported delivery drivers.
+const (
+	DriverLog  = "log"
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+)
+
+// Config selects and configures how outgoing email is delivered.
+type Config struct {
+	Driver string
+	// From is the sender address of every message.
+	From string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// File is where the file driver appends messages.
+	File string
+}
+
+// New creates the Mailer for the configured driver.
+func New(cfg Config) (domain.Mailer, error) {
+	switch cfg.Driver {
+	case "", DriverLog:
+		return NewLogMailer(), nil
+	case DriverSMTP:
+		return NewSMTPMailer(cfg)
+	case DriverFile:
+		return NewFileMailer(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported mail driver %q", cfg.Driver)
+	}
+}
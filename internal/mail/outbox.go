@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"userHub/internal/domain"
+)
+
+// Outbox is an in-memory Mailer that keeps every message it is given.
+// Tests use it to read back the tokens a flow has mailed out.
+type Outbox struct {
+	mu       sync.Mutex
+	messages []domain.Message
+}
+
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+func (o *Outbox) Send(ctx context.Context, msg domain.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, msg)
+	return nil
+}
+
+// Messages returns every message sent so far, oldest first.
+func (o *Outbox) Messages() []domain.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]domain.Message(nil), o.messages...)
+}
+
+// Last returns the most recent message sent to the given address.
+func (o *Outbox) Last(to string) (domain.Message, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		if strings.EqualFold(o.messages[i].To, to) {
+			return o.messages[i], true
+		}
+	}
+	return domain.Message{}, false
+}
@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"userHub/internal/domain"
+)
+
+// smtpMailer delivers messages through an SMTP relay.
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer creates a Mailer that relays through cfg.SMTPHost.
+// PLAIN authentication is used when a username is set; net/smtp only
+// sends credentials over TLS or to localhost.
+func NewSMTPMailer(cfg Config) (domain.Mailer, error) {
+	if cfg.SMTPHost == "" {
+		return nil, errors.New("SMTP host is required")
+	}
+	if cfg.From == "" {
+		return nil, errors.New("mail sender address is required")
+	}
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+
+	m := &smtpMailer{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)),
+		from: cfg.From,
+	}
+	if cfg.SMTPUsername != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return m, nil
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg domain.Message) error {
+	data, err := render(m.from, msg)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, data)
+}
+
+// render formats msg as an RFC 5322 plain-text message.
+func render(from string, msg domain.Message) ([]byte, error) {
+	// Header values must not smuggle in extra headers.
+	for _, v := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, errors.New("mail: header contains a line break")
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return b.Bytes(), nil
+}
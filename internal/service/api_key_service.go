@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// lastUsedGranularity bounds how often a busy key's LastUsedAt is written.
+const lastUsedGranularity = time.Minute
+
+// apiKeyService implements domain.APIKeyService
+type apiKeyService struct {
+	keys   domain.APIKeyRepository
+	users  domain.UserRepository
+	authz  domain.Authorizer
+	audit  domain.AuditRepository
+	maxTTL time.Duration
+}
+
+// NewAPIKeyService creates a new APIKeyService. Keys live at most maxTTL.
+func NewAPIKeyService(keys domain.APIKeyRepository, users domain.UserRepository, authz domain.Authorizer, audit domain.AuditRepository, maxTTL time.Duration) domain.APIKeyService {
+	if maxTTL <= 0 {
+		maxTTL = 365 * 24 * time.Hour
+	}
+	return &apiKeyService{keys: keys, users: users, authz: authz, audit: audit, maxTTL: maxTTL}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, userID uint, name string, scopes []domain.Scope, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	if err := s.authz.Authorize(ctx, domain.PermAPIKeysManage, userID); err != nil {
+		return nil, "", err
+	}
+	p, _ := domain.PrincipalFromContext(ctx)
+	// A leaked key must not be able to mint more keys.
+	if p.APIKeyID != 0 {
+		return nil, "", domain.NewForbidden("API keys cannot manage API keys")
+	}
+	// A key acts as its owner without recording who made it, so admins may
+	// list and revoke the keys of others but not mint them.
+	if p.UserID != userID {
+		return nil, "", domain.NewForbidden("API keys can only be created for yourself")
+	}
+	// Nor may an impersonation outlive itself as a key.
+	if err := forbidWhileImpersonating(ctx, "creating API keys"); err != nil {
+		return nil, "", err
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC()
+	expires := now.Add(s.maxTTL)
+	if expiresAt != nil {
+		if !expiresAt.After(now) || expiresAt.After(expires) {
+			return nil, "", domain.NewValidationError("validation failed", map[string]string{"ExpiresAt": "must be in the future and within " + s.maxTTL.String()})
+		}
+		expires = expiresAt.UTC()
+	}
+
+	granted := make([]domain.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, "", domain.NewValidationError("validation failed", map[string]string{"Scopes": "unknown scope " + string(scope)})
+		}
+		if !containsScope(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	if len(granted) == 0 {
+		return nil, "", domain.NewValidationError("validation failed", map[string]string{"Scopes": "at least one scope is required"})
+	}
+
+	token, _, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, "", domain.NewInternal("failed to generate API key")
+	}
+	raw := domain.APIKeyPrefix + token
+
+	key, err := s.keys.Create(ctx, &domain.APIKey{
+		UserID:    user.ID,
+		TenantID:  user.TenantID,
+		Name:      name,
+		Prefix:    raw[:len(domain.APIKeyPrefix)+8],
+		KeyHash:   auth.HashOpaqueToken(raw),
+		Scopes:    granted,
+		MFA:       p.MFA,
+		ExpiresAt: expires,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditAPIKeyCreated, user.ID, map[string]domain.FieldChange{
+		"name":   {To: key.Name},
+		"scopes": {To: joinScopes(key.Scopes)},
+	})
+	return key, raw, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, userID uint) ([]domain.APIKey, error) {
+	if err := s.authz.Authorize(ctx, domain.PermAPIKeysManage, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.keys.ListForUser(ctx, userID)
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, userID, keyID uint) error {
+	if err := s.authz.Authorize(ctx, domain.PermAPIKeysManage, userID); err != nil {
+		return err
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	key, err := s.keys.GetByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if key.UserID != userID {
+		return domain.NewNotFound("API key not found")
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.keys.Revoke(ctx, key.ID, time.Now().UTC()); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.audit, domain.AuditAPIKeyRevoked, userID, map[string]domain.FieldChange{
+		"name": {From: key.Name},
+	})
+	return nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, raw string) (*domain.Principal, error) {
+	if !strings.HasPrefix(raw, domain.APIKeyPrefix) {
+		return nil, domain.NewUnauthorized("invalid API key")
+	}
+	key, err := s.keys.GetByHash(ctx, auth.HashOpaqueToken(raw))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewUnauthorized("invalid API key")
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case key.RevokedAt != nil:
+		return nil, domain.NewUnauthorized("API key has been revoked")
+	case !now.Before(key.ExpiresAt):
+		return nil, domain.NewUnauthorized("API key has expired")
+	}
+
+	// The key acts with its owner's current role, and dies with its owner.
+	user, err := s.users.GetByID(domain.WithTenant(ctx, key.TenantID), key.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewUnauthorized("invalid API key")
+		}
+		return nil, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedGranularity {
+		if err := s.keys.TouchLastUsed(ctx, key.ID, now); err != nil {
+			log.Printf("auth: failed to record use of API key %d: %v", key.ID, err)
+		}
+	}
+
+	return &domain.Principal{
+		UserID:   user.ID,
+		TenantID: user.TenantID,
+		Role:     user.Role,
+		MFA:      key.MFA,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func containsScope(scopes []domain.Scope, s domain.Scope) bool {
+	for _, candidate := range scopes {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinScopes(scopes []domain.Scope) string {
+	names := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		names = append(names, string(s))
+	}
+	return strings.Join(names, ",")
+}
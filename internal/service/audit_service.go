@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"userHub/internal/domain"
+)
+
+// auditService implements domain.AuditService
+type auditService struct {
+	repo  domain.AuditRepository
+	authz domain.Authorizer
+}
+
+// NewAuditService creates a new AuditService
+func NewAuditService(repo domain.AuditRepository, authz domain.Authorizer) domain.AuditService {
+	return &auditService{repo: repo, authz: authz}
+}
+
+func (s *auditService) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEvent, int64, error) {
+	if err := s.authz.Authorize(ctx, domain.PermAuditRead, 0); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// recordAudit appends an audit event attributed to the caller in ctx.
+// The mutation has already happened, so a failure is logged rather than returned.
+func recordAudit(ctx context.Context, repo domain.AuditRepository, action domain.AuditAction, targetID uint, changes map[string]domain.FieldChange) {
+	event := &domain.AuditEvent{
+		TargetID: targetID,
+		Action:   action,
+		Changes:  changes,
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		event.ActorID = p.UserID
+		event.ImpersonatorID = p.ImpersonatorID
+	}
+	meta := domain.RequestMetaFromContext(ctx)
+	event.RequestID = meta.RequestID
+	event.ClientIP = meta.ClientIP
+
+	if err := repo.Append(ctx, event); err != nil {
+		log.Printf("audit: failed to record %s on %d (request %s): %v", action, targetID, meta.RequestID, err)
+	}
+}
+
+// userChanges returns the audited fields that differ between before and after.
+// A nil before or after stands for a user that did not exist.
+func userChanges(before, after *domain.User) map[string]domain.FieldChange {
+	var zero domain.User
+	if before == nil {
+		before = &zero
+	}
+	if after == nil {
+		after = &zero
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	diff := func(field, from, to string) {
+		if from != to {
+			changes[field] = domain.FieldChange{From: from, To: to}
+		}
+	}
+	diff("name", before.Name, after.Name)
+	diff("email", before.Email, after.Email)
+	diff("gender", before.Gender, after.Gender)
+	diff("role", string(before.Role), string(after.Role))
+	diff("email_verified", strconv.FormatBool(before.EmailVerified()), strconv.FormatBool(after.EmailVerified()))
+	return changes
+}
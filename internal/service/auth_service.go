@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// authService implements domain.AuthService
+type authService struct {
+	users      domain.UserRepository
+	authz      domain.Authorizer
+	tokens     domain.TokenManager
+	creds      domain.CredentialVerifier
+	refresh    domain.RefreshTokenRepository
+	mfa        domain.SecondFactor
+	refreshTTL time.Duration
+
+	impersonations domain.ImpersonationRepository
+}
+
+// NewAuthService creates a new AuthService.
+// A nil CredentialVerifier disables password login; tokens can still be verified.
+// A nil SecondFactor disables the MFA step of a login.
+// A nil ImpersonationRepository rejects every impersonation token.
+func NewAuthService(users domain.UserRepository, authz domain.Authorizer, tokens domain.TokenManager, creds domain.CredentialVerifier, refresh domain.RefreshTokenRepository, mfa domain.SecondFactor, impersonations domain.ImpersonationRepository, refreshTTL time.Duration) domain.AuthService {
+	if refreshTTL <= 0 {
+		refreshTTL = 30 * 24 * time.Hour
+	}
+	return &authService{users: users, authz: authz, tokens: tokens, creds: creds, refresh: refresh, mfa: mfa, refreshTTL: refreshTTL, impersonations: impersonations}
+}
+
+func (s *authService) Login(ctx context.Context, email, password string) (*domain.LoginResult, error) {
+	if s.creds == nil {
+		return nil, domain.NewUnauthorized("invalid credentials")
+	}
+
+	user, err := s.creds.VerifyCredentials(ctx, email, password)
+	if err != nil {
+		// Never reveal whether the email or the password was wrong.
+		if ae, ok := err.(*domain.AppError); ok && ae.Code != domain.CodeInternal {
+			return nil, domain.NewUnauthorized("invalid credentials")
+		}
+		return nil, err
+	}
+
+	if s.mfa != nil && user.Credential.MFAEnabled() {
+		challenge, err := s.tokens.IssueChallenge(&domain.Principal{UserID: user.ID, TenantID: user.TenantID, Role: user.Role})
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{Challenge: challenge}, nil
+	}
+
+	token, err := s.startSession(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Token: token}, nil
+}
+
+func (s *authService) VerifyMFA(ctx context.Context, challenge, code string) (*domain.AuthToken, error) {
+	if s.mfa == nil {
+		return nil, domain.NewUnauthorized("invalid MFA token")
+	}
+
+	p, err := s.tokens.VerifyChallenge(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	// As with refresh tokens, the challenge decides the tenant.
+	ctx = domain.WithTenant(ctx, p.TenantID)
+	user, err := s.users.GetByID(ctx, p.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewUnauthorized("invalid MFA token")
+		}
+		return nil, err
+	}
+
+	user, err = s.mfa.VerifyCode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	return s.startSession(ctx, user, true)
+}
+
+func (s *authService) Authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	p, err := s.tokens.Verify(token)
+	if err != nil || !p.Impersonated() {
+		return p, err
+	}
+
+	// Impersonation tokens are only as good as their record, so ending the
+	// impersonation revokes them before they expire.
+	if s.impersonations == nil {
+		return nil, domain.NewUnauthorized("invalid token")
+	}
+	imp, err := s.impersonations.GetByID(domain.WithTenant(ctx, p.TenantID), p.ImpersonationID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewUnauthorized("invalid token")
+		}
+		return nil, err
+	}
+	if imp.UserID != p.UserID || imp.ImpersonatorID != p.ImpersonatorID {
+		return nil, domain.NewUnauthorized("invalid token")
+	}
+	if !imp.ActiveAt(time.Now().UTC()) {
+		return nil, domain.NewUnauthorized("impersonation has ended")
+	}
+	return p, nil
+}
+
+// Refresh exchanges a refresh token for a new access/refresh pair.
+// Presenting a token that was already exchanged is treated as theft:
+// every token in its family is revoked and the caller must log in again.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*domain.AuthToken, error) {
+	current, err := s.refresh.GetByHash(ctx, auth.HashOpaqueToken(refreshToken))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewUnauthorized("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case current.RevokedAt != nil:
+		return nil, domain.NewUnauthorized("refresh token has been revoked")
+	case current.RotatedAt != nil:
+		s.revokeFamily(ctx, current)
+		return nil, domain.NewUnauthorized("refresh token reuse detected")
+	case now.After(current.ExpiresAt):
+		return nil, domain.NewUnauthorized("refresh token has expired")
+	}
+
+	if err := s.refresh.MarkRotated(ctx, current.ID, now); err != nil {
+		// Another request rotated the token first: same as reuse.
+		if ae, ok := err.(*domain.AppError); ok && ae.Code == domain.CodeConflict {
+			s.revokeFamily(ctx, current)
+			return nil, domain.NewUnauthorized("refresh token reuse detected")
+		}
+		return nil, err
+	}
+
+	// The refresh token, not the request, decides which tenant the session belongs to.
+	user, err := s.users.GetByID(domain.WithTenant(ctx, current.TenantID), current.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewUnauthorized("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	return s.issue(ctx, user, current.FamilyID, current.MFA)
+}
+
+// Logout revokes the session the refresh token belongs to.
+// Unknown tokens are ignored so the call is idempotent.
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	current, err := s.refresh.GetByHash(ctx, auth.HashOpaqueToken(refreshToken))
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return s.refresh.RevokeFamily(ctx, current.FamilyID, time.Now().UTC())
+}
+
+func (s *authService) RevokeAllSessions(ctx context.Context, userID uint) error {
+	if err := s.authz.Authorize(ctx, domain.PermSessionsRevoke, userID); err != nil {
+		return err
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return err
+	}
+	return s.refresh.RevokeAllForUser(ctx, userID, time.Now().UTC())
+}
+
+// startSession issues the first token pair of a new refresh token family.
+func (s *authService) startSession(ctx context.Context, user *domain.User, mfa bool) (*domain.AuthToken, error) {
+	familyID, _, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, domain.NewInternal("failed to start session")
+	}
+	return s.issue(ctx, user, familyID, mfa)
+}
+
+// issue mints an access token and a new refresh token in the given family.
+// mfa records whether the session passed a second factor.
+func (s *authService) issue(ctx context.Context, user *domain.User, familyID string, mfa bool) (*domain.AuthToken, error) {
+	token, err := s.tokens.Issue(&domain.Principal{UserID: user.ID, TenantID: user.TenantID, Role: user.Role, MFA: mfa})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, domain.NewInternal("failed to generate refresh token")
+	}
+	if _, err := s.refresh.Create(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		TenantID:  user.TenantID,
+		FamilyID:  familyID,
+		MFA:       mfa,
+		TokenHash: hash,
+		ExpiresAt: time.Now().UTC().Add(s.refreshTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	token.RefreshToken = raw
+	return token, nil
+}
+
+func (s *authService) revokeFamily(ctx context.Context, t *domain.RefreshToken) {
+	if err := s.refresh.RevokeFamily(ctx, t.FamilyID, time.Now().UTC()); err != nil {
+		log.Printf("auth: failed to revoke token family for user %d: %v", t.UserID, err)
+	}
+}
+
+func isNotFound(err error) bool {
+	ae, ok := err.(*domain.AppError)
+	return ok && ae.Code == domain.CodeNotFound
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"userHub/internal/domain"
+)
+
+// rbacAuthorizer implements domain.Authorizer using role permissions
+// plus the self-service rules every user has over their own record.
+type rbacAuthorizer struct {
+	groups domain.GroupRepository
+}
+
+// NewAuthorizer creates a new role-based Authorizer. When groups is not nil,
+// users also hold the roles of the groups they belong to.
+func NewAuthorizer(groups domain.GroupRepository) domain.Authorizer {
+	return rbacAuthorizer{groups: groups}
+}
+
+func (a rbacAuthorizer) Authorize(ctx context.Context, action domain.Permission, targetID uint) error {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.NewUnauthorized("authentication required")
+	}
+
+	if p.Role.Can(action) {
+		return nil
+	}
+	if targetID != 0 && targetID == p.UserID && domain.CanOnSelf(action) {
+		return nil
+	}
+
+	// Group roles are looked up only when the user's own role falls short,
+	// so membership changes apply to tokens already issued.
+	if a.groups != nil {
+		memberships, err := groupsOf(ctx, a.groups, p.UserID)
+		if err != nil {
+			return err
+		}
+		for _, m := range memberships {
+			if m.Group.Role.Can(action) {
+				return nil
+			}
+		}
+	}
+
+	return domain.NewForbidden("you do not have permission to perform this action")
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+)
+
+// BootstrapOrganization makes sure the default organization exists.
+func BootstrapOrganization(ctx context.Context, orgs domain.OrganizationRepository) error {
+	if _, err := orgs.GetByID(ctx, domain.DefaultTenantID); err == nil {
+		return nil
+	} else if !isNotFound(err) {
+		return err
+	}
+
+	_, err := orgs.Create(ctx, &domain.Organization{ID: domain.DefaultTenantID, Name: "Default", Slug: "default"})
+	return err
+}
+
+// BootstrapAdmin makes sure the given email belongs to an admin, creating the
+// account if needed, so a fresh installation can be managed at all.
+func BootstrapAdmin(ctx context.Context, repo domain.UserRepository, passwords domain.PasswordService, email, password string) error {
+	if email == "" {
+		return nil
+	}
+	ctx = domain.WithTenant(ctx, domain.DefaultTenantID)
+
+	if existing, err := repo.GetByEmail(ctx, email); err == nil {
+		if existing.Role == domain.RoleAdmin {
+			return nil
+		}
+		existing.Role = domain.RoleAdmin
+		_, err = repo.Update(ctx, existing)
+		return err
+	} else if !isNotFound(err) {
+		return err
+	}
+
+	cred, err := passwords.NewCredential(password)
+	if err != nil {
+		return err
+	}
+	// The operator supplied the address, so it is trusted as verified.
+	now := time.Now().UTC()
+	_, err = repo.Create(ctx, &domain.User{
+		Name:            "Administrator",
+		Email:           email,
+		Role:            domain.RoleAdmin,
+		EmailVerifiedAt: &now,
+		Credential:      cred,
+	})
+	return err
+}
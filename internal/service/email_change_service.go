@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// emailChangeService implements domain.EmailChangeService
+type emailChangeService struct {
+	users   domain.UserRepository
+	changes domain.EmailChangeRepository
+	audit   domain.AuditRepository
+	mailer  domain.Mailer
+	ttl     time.Duration
+	baseURL string
+}
+
+// NewEmailChangeService creates a new EmailChangeService.
+// Confirmation tokens expire after ttl; the mailed link points at baseURL.
+func NewEmailChangeService(users domain.UserRepository, changes domain.EmailChangeRepository, audit domain.AuditRepository, mailer domain.Mailer, ttl time.Duration, baseURL string) domain.EmailChangeService {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &emailChangeService{
+		users:   users,
+		changes: changes,
+		audit:   audit,
+		mailer:  mailer,
+		ttl:     ttl,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (s *emailChangeService) Request(ctx context.Context, userID uint, newEmail string) error {
+	// The link goes to the new address, so whoever requests the change can
+	// confirm it; nobody but the owner may point an account at a new mailbox.
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.NewUnauthorized("authentication required")
+	}
+	if p.UserID != userID {
+		return domain.NewForbidden("users can only change their own email address")
+	}
+	if err := forbidWhileImpersonating(ctx, "changing the email address"); err != nil {
+		return err
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return domain.NewValidationError("validation failed", map[string]string{"Email": "is already the current email"})
+	}
+	if _, err := s.users.GetByEmail(ctx, newEmail); err == nil {
+		return domain.NewConflict("email already exists")
+	} else if !isNotFound(err) {
+		return err
+	}
+
+	// Only the newest request can be confirmed.
+	now := time.Now().UTC()
+	if err := s.changes.ConsumeAllForUser(ctx, user.ID, now); err != nil {
+		return err
+	}
+
+	raw, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return domain.NewInternal("failed to generate email change token")
+	}
+	if _, err := s.changes.Create(ctx, &domain.EmailChange{
+		UserID:    user.ID,
+		TenantID:  user.TenantID,
+		OldEmail:  user.Email,
+		NewEmail:  newEmail,
+		TokenHash: hash,
+		ExpiresAt: now.Add(s.ttl),
+	}); err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/email/confirm?token=" + url.QueryEscape(raw)
+	err = s.mailer.Send(ctx, domain.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hello %s,\n\nConfirm that you want to use this address for your account:\n\n%s\n\nThe link expires in %s. If you did not ask for this, ignore this email.\n",
+			user.Name, link, s.ttl),
+	})
+	if err != nil {
+		return domain.NewInternal("failed to send confirmation email")
+	}
+	return nil
+}
+
+func (s *emailChangeService) Confirm(ctx context.Context, token string) (*domain.User, error) {
+	invalid := domain.NewValidationError("invalid or expired email change token", nil)
+
+	change, err := s.changes.GetByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if change.ConsumedAt != nil || now.After(change.ExpiresAt) {
+		return nil, invalid
+	}
+	if err := s.changes.MarkConsumed(ctx, change.ID, now); err != nil {
+		if ae, ok := err.(*domain.AppError); ok && ae.Code == domain.CodeConflict {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	// The token, not the request, decides which tenant the user belongs to.
+	ctx = domain.WithTenant(ctx, change.TenantID)
+	current, err := s.users.GetByID(ctx, change.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if !strings.EqualFold(current.Email, change.OldEmail) {
+		return nil, invalid
+	}
+
+	// Someone may have claimed the address since the request was made.
+	if _, err := s.users.GetByEmail(ctx, change.NewEmail); err == nil {
+		return nil, domain.NewConflict("email already exists")
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	// Clicking the link proved ownership of the new address.
+	next := *current
+	next.Email = change.NewEmail
+	next.EmailVerifiedAt = &now
+	updated, err := s.users.Update(ctx, &next)
+	if err != nil {
+		return nil, err
+	}
+
+	// Holding the token proves the user's consent, so the change is theirs.
+	actor := domain.WithPrincipal(ctx, &domain.Principal{UserID: updated.ID, TenantID: updated.TenantID, Role: updated.Role})
+	recordAudit(actor, s.audit, domain.AuditUserEmailChanged, updated.ID, userChanges(current, updated))
+
+	// Tell the previous address, in case the change was not wanted.
+	notice := domain.Message{
+		To:      change.OldEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("Hello %s,\n\nThe email address of your account was changed to %s.\nIf you did not make this change, contact your administrator immediately.\n",
+			updated.Name, updated.Email),
+	}
+	if err := s.mailer.Send(ctx, notice); err != nil {
+		log.Printf("email: failed to notify old address of user %d: %v", updated.ID, err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// emailVerificationService implements domain.EmailVerificationService
+type emailVerificationService struct {
+	users         domain.UserRepository
+	verifications domain.EmailVerificationRepository
+	authz         domain.Authorizer
+	audit         domain.AuditRepository
+	mailer        domain.Mailer
+	ttl           time.Duration
+	baseURL       string
+}
+
+// NewEmailVerificationService creates a new EmailVerificationService.
+// Verification links expire after ttl and point at baseURL.
+func NewEmailVerificationService(users domain.UserRepository, verifications domain.EmailVerificationRepository, authz domain.Authorizer, audit domain.AuditRepository, mailer domain.Mailer, ttl time.Duration, baseURL string) domain.EmailVerificationService {
+	if ttl <= 0 {
+		ttl = 48 * time.Hour
+	}
+	return &emailVerificationService{
+		users:         users,
+		verifications: verifications,
+		authz:         authz,
+		audit:         audit,
+		mailer:        mailer,
+		ttl:           ttl,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SendVerification mails a verification link without authorization checks;
+// it is meant for the user service right after sign-up.
+func (s *emailVerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	// Only the newest link can be used.
+	now := time.Now().UTC()
+	if err := s.verifications.ConsumeAllForUser(ctx, user.ID, now); err != nil {
+		return err
+	}
+
+	raw, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return domain.NewInternal("failed to generate verification token")
+	}
+	if _, err := s.verifications.Create(ctx, &domain.EmailVerification{
+		UserID:    user.ID,
+		TenantID:  user.TenantID,
+		Email:     user.Email,
+		TokenHash: hash,
+		ExpiresAt: now.Add(s.ttl),
+	}); err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/email/verify?token=" + url.QueryEscape(raw)
+	err = s.mailer.Send(ctx, domain.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening this link:\n\n%s\n\nThe link expires in %s. If you did not create an account, ignore this email.\n",
+			user.Name, link, s.ttl),
+	})
+	if err != nil {
+		return domain.NewInternal("failed to send verification email")
+	}
+	return nil
+}
+
+func (s *emailVerificationService) Resend(ctx context.Context, userID uint) error {
+	if err := s.authz.Authorize(ctx, domain.PermUsersUpdate, userID); err != nil {
+		return err
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified() {
+		return domain.NewConflict("email is already verified")
+	}
+	return s.SendVerification(ctx, user)
+}
+
+func (s *emailVerificationService) Confirm(ctx context.Context, token string) (*domain.User, error) {
+	invalid := domain.NewValidationError("invalid or expired verification token", nil)
+
+	v, err := s.verifications.GetByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if v.ConsumedAt != nil || now.After(v.ExpiresAt) {
+		return nil, invalid
+	}
+	if err := s.verifications.MarkConsumed(ctx, v.ID, now); err != nil {
+		if ae, ok := err.(*domain.AppError); ok && ae.Code == domain.CodeConflict {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	// The token, not the request, decides which tenant the user belongs to.
+	ctx = domain.WithTenant(ctx, v.TenantID)
+	current, err := s.users.GetByID(ctx, v.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if !strings.EqualFold(current.Email, v.Email) {
+		return nil, invalid
+	}
+	if current.EmailVerified() {
+		return current, nil
+	}
+
+	next := *current
+	next.EmailVerifiedAt = &now
+	updated, err := s.users.Update(ctx, &next)
+	if err != nil {
+		return nil, err
+	}
+
+	// Holding the token proves the user's consent, so the change is theirs.
+	actor := domain.WithPrincipal(ctx, &domain.Principal{UserID: updated.ID, TenantID: updated.TenantID, Role: updated.Role})
+	recordAudit(actor, s.audit, domain.AuditUserEmailVerified, updated.ID, userChanges(current, updated))
+	return updated, nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"userHub/internal/domain"
+)
+
+// groupService implements domain.GroupService
+type groupService struct {
+	groups domain.GroupRepository
+	users  domain.UserRepository
+	authz  domain.Authorizer
+	audit  domain.AuditRepository
+}
+
+// NewGroupService creates a new GroupService. Every member must be a user of
+// the caller's organization, and groups cannot contain themselves, however
+// deeply nested.
+func NewGroupService(groups domain.GroupRepository, users domain.UserRepository, authz domain.Authorizer, audit domain.AuditRepository) domain.GroupService {
+	return &groupService{groups: groups, users: users, authz: authz, audit: audit}
+}
+
+func (s *groupService) Create(ctx context.Context, group *domain.Group) (*domain.Group, error) {
+	if err := s.authz.Authorize(ctx, domain.PermGroupsManage, 0); err != nil {
+		return nil, err
+	}
+	if group.Role != "" {
+		if err := s.authz.Authorize(ctx, domain.PermUsersAssignRole, 0); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.normalize(ctx, group); err != nil {
+		return nil, err
+	}
+
+	created, err := s.groups.Create(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditGroupCreated, 0, groupChanges(nil, created))
+	return created, nil
+}
+
+func (s *groupService) GetByID(ctx context.Context, id uint) (*domain.Group, error) {
+	if err := s.authz.Authorize(ctx, domain.PermGroupsRead, 0); err != nil {
+		return nil, err
+	}
+	return s.groups.GetByID(ctx, id)
+}
+
+func (s *groupService) List(ctx context.Context, page, limit int) ([]*domain.Group, int64, error) {
+	if err := s.authz.Authorize(ctx, domain.PermGroupsRead, 0); err != nil {
+		return nil, 0, err
+	}
+	return s.groups.List(ctx, page, limit)
+}
+
+func (s *groupService) Update(ctx context.Context, group *domain.Group) (*domain.Group, error) {
+	if err := s.authz.Authorize(ctx, domain.PermGroupsManage, 0); err != nil {
+		return nil, err
+	}
+	current, err := s.groups.GetByID(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if group.Role != current.Role {
+		if err := s.authz.Authorize(ctx, domain.PermUsersAssignRole, 0); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.normalize(ctx, group); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.groups.Update(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditGroupUpdated, 0, groupChanges(current, updated))
+	return updated, nil
+}
+
+func (s *groupService) Delete(ctx context.Context, id uint) error {
+	if err := s.authz.Authorize(ctx, domain.PermGroupsManage, 0); err != nil {
+		return err
+	}
+	current, err := s.groups.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.groups.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditGroupDeleted, 0, groupChanges(current, nil))
+	return nil
+}
+
+func (s *groupService) AddMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*domain.Group, error) {
+	if err := s.authz.Authorize(ctx, domain.PermGroupsManage, 0); err != nil {
+		return nil, err
+	}
+	current, err := s.groups.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if userIDs, err = s.checkUsers(ctx, userIDs); err != nil {
+		return nil, err
+	}
+	if subgroupIDs, err = s.checkSubgroups(ctx, id, subgroupIDs); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.groups.AddMembers(ctx, id, userIDs, subgroupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditGroupUpdated, 0, groupChanges(current, updated))
+	return updated, nil
+}
+
+func (s *groupService) RemoveMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*domain.Group, error) {
+	if err := s.authz.Authorize(ctx, domain.PermGroupsManage, 0); err != nil {
+		return nil, err
+	}
+	current, err := s.groups.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.groups.RemoveMembers(ctx, id, uniqueIDs(userIDs), uniqueIDs(subgroupIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditGroupUpdated, 0, groupChanges(current, updated))
+	return updated, nil
+}
+
+func (s *groupService) ListForUser(ctx context.Context, userID uint) ([]domain.GroupMembership, domain.Role, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersRead, userID); err != nil {
+		return nil, "", err
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	memberships, err := groupsOf(ctx, s.groups, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	role := user.Role
+	for _, m := range memberships {
+		if m.Group.Role.Outranks(role) {
+			role = m.Group.Role
+		}
+	}
+	return memberships, role, nil
+}
+
+// groupsOf returns the groups userID is in, directly or through subgroups,
+// in ID order. It walks up one level of nesting per query.
+func groupsOf(ctx context.Context, groups domain.GroupRepository, userID uint) ([]domain.GroupMembership, error) {
+	direct, err := groups.ListByMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(direct))
+	memberships := make([]domain.GroupMembership, 0, len(direct))
+	frontier := make([]uint, 0, len(direct))
+	for _, g := range direct {
+		seen[g.ID] = true
+		memberships = append(memberships, domain.GroupMembership{Group: g, Direct: true})
+		frontier = append(frontier, g.ID)
+	}
+	for len(frontier) > 0 {
+		parents, err := groups.ListBySubgroup(ctx, frontier)
+		if err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, g := range parents {
+			if seen[g.ID] {
+				continue
+			}
+			seen[g.ID] = true
+			memberships = append(memberships, domain.GroupMembership{Group: g})
+			frontier = append(frontier, g.ID)
+		}
+	}
+
+	sort.Slice(memberships, func(i, j int) bool { return memberships[i].Group.ID < memberships[j].Group.ID })
+	return memberships, nil
+}
+
+// normalize trims the name and checks the role, then sorts and
+// deduplicates members and subgroups after checking them.
+func (s *groupService) normalize(ctx context.Context, group *domain.Group) error {
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" || len(group.Name) > 255 {
+		return domain.NewValidationError("validation failed", map[string]string{"name": "must be 1-255 characters"})
+	}
+	if group.Role != "" && !group.Role.Valid() {
+		return domain.NewValidationError("validation failed", map[string]string{"role": "must be admin, manager or member"})
+	}
+
+	var err error
+	if group.MemberIDs, err = s.checkUsers(ctx, group.MemberIDs); err != nil {
+		return err
+	}
+	group.SubgroupIDs, err = s.checkSubgroups(ctx, group.ID, group.SubgroupIDs)
+	return err
+}
+
+// checkUsers returns ids sorted and deduplicated if each is a user.
+func (s *groupService) checkUsers(ctx context.Context, ids []uint) ([]uint, error) {
+	ids = uniqueIDs(ids)
+	for _, id := range ids {
+		if _, err := s.users.GetByID(ctx, id); err != nil {
+			if isNotFound(err) {
+				return nil, domain.NewValidationError("validation failed", map[string]string{"members": fmt.Sprintf("user %d does not exist", id)})
+			}
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// checkSubgroups returns ids sorted and deduplicated if each is a group
+// that can be nested in groupID (0 for a new group) without making a cycle.
+func (s *groupService) checkSubgroups(ctx context.Context, groupID uint, ids []uint) ([]uint, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	// groupID and the groups containing it, at any depth, cannot be nested in it.
+	ancestors := map[uint]bool{}
+	if groupID != 0 {
+		ancestors[groupID] = true
+		for frontier := []uint{groupID}; len(frontier) > 0; {
+			parents, err := s.groups.ListBySubgroup(ctx, frontier)
+			if err != nil {
+				return nil, err
+			}
+			frontier = nil
+			for _, g := range parents {
+				if !ancestors[g.ID] {
+					ancestors[g.ID] = true
+					frontier = append(frontier, g.ID)
+				}
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if _, err := s.groups.GetByID(ctx, id); err != nil {
+			if isNotFound(err) {
+				return nil, domain.NewValidationError("validation failed", map[string]string{"subgroups": fmt.Sprintf("group %d does not exist", id)})
+			}
+			return nil, err
+		}
+		if ancestors[id] {
+			return nil, domain.NewValidationError("validation failed", map[string]string{"subgroups": fmt.Sprintf("group %d would contain itself", id)})
+		}
+	}
+	return ids, nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// groupChanges describes a group mutation for the audit log, in the manner
+// of userChanges. The group ID is always recorded, since audit events only
+// target users.
+func groupChanges(before, after *domain.Group) map[string]domain.FieldChange {
+	var zero domain.Group
+	if before == nil {
+		before = &zero
+	}
+	if after == nil {
+		after = &zero
+	}
+
+	id := before.ID
+	if id == 0 {
+		id = after.ID
+	}
+	changes := map[string]domain.FieldChange{"group_id": {To: strconv.FormatUint(uint64(id), 10)}}
+	diff := func(field, from, to string) {
+		if from != to {
+			changes[field] = domain.FieldChange{From: from, To: to}
+		}
+	}
+	diff("name", before.Name, after.Name)
+	diff("role", string(before.Role), string(after.Role))
+	diff("members", joinIDs(before.MemberIDs), joinIDs(after.MemberIDs))
+	diff("subgroups", joinIDs(before.SubgroupIDs), joinIDs(after.SubgroupIDs))
+	return changes
+}
+
+func joinIDs(ids []uint) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatUint(uint64(id), 10))
+	}
+	return strings.Join(parts, ",")
+}
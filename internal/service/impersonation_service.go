@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"userHub/internal/domain"
+)
+
+// impersonationService implements domain.ImpersonationService
+type impersonationService struct {
+	impersonations domain.ImpersonationRepository
+	users          domain.UserRepository
+	authz          domain.Authorizer
+	audit          domain.AuditRepository
+	tokens         domain.TokenManager
+	ttl            time.Duration
+}
+
+// NewImpersonationService creates a new ImpersonationService whose tokens
+// expire after ttl and cannot be refreshed.
+func NewImpersonationService(impersonations domain.ImpersonationRepository, users domain.UserRepository, authz domain.Authorizer, audit domain.AuditRepository, tokens domain.TokenManager, ttl time.Duration) domain.ImpersonationService {
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	return &impersonationService{impersonations: impersonations, users: users, authz: authz, audit: audit, tokens: tokens, ttl: ttl}
+}
+
+func (s *impersonationService) Start(ctx context.Context, userID uint, reason string) (*domain.Impersonation, *domain.AuthToken, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersImpersonate, 0); err != nil {
+		return nil, nil, err
+	}
+	p, _ := domain.PrincipalFromContext(ctx)
+	switch {
+	case p.APIKeyID != 0:
+		return nil, nil, domain.NewForbidden("impersonation requires a session")
+	case p.Impersonated():
+		return nil, nil, domain.NewForbidden("cannot impersonate while impersonating")
+	case userID == p.UserID:
+		return nil, nil, domain.NewValidationError("cannot impersonate yourself", nil)
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imp, err := s.impersonations.Create(ctx, &domain.Impersonation{
+		ImpersonatorID: p.UserID,
+		UserID:         user.ID,
+		Reason:         strings.TrimSpace(reason),
+		ExpiresAt:      time.Now().UTC().Add(s.ttl),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The token sees exactly what the user sees, but keeps the admin's MFA
+	// state so the organization's policy still applies to the admin.
+	token, err := s.tokens.IssueImpersonation(&domain.Principal{
+		UserID:          user.ID,
+		TenantID:        user.TenantID,
+		Role:            user.Role,
+		MFA:             p.MFA,
+		ImpersonatorID:  p.UserID,
+		ImpersonationID: imp.ID,
+	}, imp.ExpiresAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditImpersonationStarted, user.ID, impersonationChanges(imp))
+	return imp, token, nil
+}
+
+func (s *impersonationService) End(ctx context.Context) (*domain.Impersonation, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.NewUnauthorized("authentication required")
+	}
+	if !p.Impersonated() {
+		return nil, domain.NewValidationError("not impersonating anyone", nil)
+	}
+	if err := s.impersonations.MarkEnded(ctx, p.ImpersonationID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	imp, err := s.impersonations.GetByID(ctx, p.ImpersonationID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditImpersonationEnded, imp.UserID, impersonationChanges(imp))
+	return imp, nil
+}
+
+// forbidWhileImpersonating rejects changes to a user's credentials and
+// account that only the user may make, even when an admin acts as them.
+func forbidWhileImpersonating(ctx context.Context, what string) error {
+	if p, ok := domain.PrincipalFromContext(ctx); ok && p.Impersonated() {
+		return domain.NewForbidden(what + " is not allowed while impersonating")
+	}
+	return nil
+}
+
+// impersonationChanges describes an impersonation for the audit log.
+func impersonationChanges(imp *domain.Impersonation) map[string]domain.FieldChange {
+	changes := map[string]domain.FieldChange{
+		"impersonation_id": {To: strconv.FormatUint(uint64(imp.ID), 10)},
+		"impersonator_id":  {To: strconv.FormatUint(uint64(imp.ImpersonatorID), 10)},
+	}
+	if imp.Reason != "" {
+		changes["reason"] = domain.FieldChange{To: imp.Reason}
+	}
+	return changes
+}
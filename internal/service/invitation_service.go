@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// invitationService implements domain.InvitationService
+type invitationService struct {
+	invitations domain.InvitationRepository
+	users       domain.UserRepository
+	userService domain.UserService
+	authz       domain.Authorizer
+	audit       domain.AuditRepository
+	mailer      domain.Mailer
+	secret      []byte
+	ttl         time.Duration
+	baseURL     string
+}
+
+// NewInvitationService creates a new InvitationService. Invitation tokens are
+// signed with secret and expire after ttl; links point at baseURL. Accepted
+// invitations create users through userService, on behalf of the inviter.
+func NewInvitationService(invitations domain.InvitationRepository, users domain.UserRepository, userService domain.UserService, authz domain.Authorizer, audit domain.AuditRepository, mailer domain.Mailer, secret []byte, ttl time.Duration, baseURL string) domain.InvitationService {
+	if ttl <= 0 {
+		ttl = 7 * 24 * time.Hour
+	}
+	return &invitationService{
+		invitations: invitations,
+		users:       users,
+		userService: userService,
+		authz:       authz,
+		audit:       audit,
+		mailer:      mailer,
+		secret:      secret,
+		ttl:         ttl,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// invitationClaims is the signed payload of an invitation token. The nonce
+// makes every token distinct; the stored hash ties it to its invitation.
+type invitationClaims struct {
+	ExpiresAt int64  `json:"exp"`
+	Nonce     string `json:"n"`
+}
+
+func (s *invitationService) Create(ctx context.Context, email string, role domain.Role) (*domain.Invitation, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersInvite, 0); err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = domain.RoleMember
+	}
+	if !role.Valid() {
+		return nil, domain.NewValidationError("validation failed", map[string]string{"role": "must be admin, manager or member"})
+	}
+	if role != domain.RoleMember {
+		if err := s.authz.Authorize(ctx, domain.PermUsersAssignRole, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	email = strings.TrimSpace(email)
+	if existing, err := s.users.GetByEmail(ctx, email); err == nil && existing != nil {
+		return nil, domain.NewConflict("email already exists")
+	}
+	now := time.Now().UTC()
+	pending, err := s.invitations.GetPendingByEmail(ctx, email)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if err == nil && pending.StatusAt(now) == domain.InvitationPending {
+		return nil, domain.NewConflict("a pending invitation for this email already exists")
+	}
+
+	token, hash, expiresAt, err := s.newToken(now)
+	if err != nil {
+		return nil, err
+	}
+	inv := &domain.Invitation{
+		Email:     email,
+		Role:      role,
+		Status:    domain.InvitationPending,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		inv.InviterID = p.UserID
+	}
+	created, err := s.invitations.Create(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditInvitationCreated, 0, invitationChanges(created, "", domain.InvitationPending))
+	if err := s.send(ctx, created, token); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *invitationService) List(ctx context.Context, page, limit int) ([]*domain.Invitation, int64, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersInvite, 0); err != nil {
+		return nil, 0, err
+	}
+	return s.invitations.List(ctx, page, limit)
+}
+
+func (s *invitationService) Revoke(ctx context.Context, id uint) (*domain.Invitation, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersInvite, 0); err != nil {
+		return nil, err
+	}
+	if err := s.invitations.MarkRevoked(ctx, id, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	revoked, err := s.invitations.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditInvitationRevoked, 0, invitationChanges(revoked, domain.InvitationPending, domain.InvitationRevoked))
+	return revoked, nil
+}
+
+func (s *invitationService) Resend(ctx context.Context, id uint) (*domain.Invitation, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersInvite, 0); err != nil {
+		return nil, err
+	}
+	current, err := s.invitations.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := s.users.GetByEmail(ctx, current.Email); err == nil && existing != nil {
+		return nil, domain.NewConflict("email already exists")
+	}
+
+	now := time.Now().UTC()
+	token, hash, expiresAt, err := s.newToken(now)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.invitations.Reissue(ctx, id, hash, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditInvitationResent, 0, invitationChanges(updated, current.StatusAt(now), domain.InvitationPending))
+	if err := s.send(ctx, updated, token); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *invitationService) Accept(ctx context.Context, token string, user *domain.User) (*domain.User, error) {
+	invalid := domain.NewValidationError("invalid or expired invitation token", nil)
+
+	// The signature and expiry are checked before touching the store.
+	payload, err := auth.VerifySignedToken(s.secret, token)
+	if err != nil {
+		return nil, invalid
+	}
+	var claims invitationClaims
+	now := time.Now().UTC()
+	if err := json.Unmarshal(payload, &claims); err != nil || now.Unix() >= claims.ExpiresAt {
+		return nil, invalid
+	}
+
+	inv, err := s.invitations.GetByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if inv.StatusAt(now) != domain.InvitationPending {
+		return nil, invalid
+	}
+
+	// The token, not the request, decides the tenant, and the user is created
+	// on behalf of the inviter, whose current role must still allow it.
+	ctx = domain.WithTenant(ctx, inv.TenantID)
+	inviter := &domain.Principal{UserID: inv.InviterID, TenantID: inv.TenantID}
+	if u, err := s.users.GetByID(ctx, inv.InviterID); err == nil {
+		inviter.Role = u.Role
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	user.TenantID = inv.TenantID
+	user.Email = inv.Email
+	user.Role = inv.Role
+	user.EmailVerifiedAt = &now
+	created, err := s.userService.Create(domain.WithPrincipal(ctx, inviter), user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invitations.MarkAccepted(ctx, inv.ID, created.ID, now); err != nil {
+		return nil, err
+	}
+
+	actor := domain.WithPrincipal(ctx, &domain.Principal{UserID: created.ID, TenantID: created.TenantID, Role: created.Role})
+	recordAudit(actor, s.audit, domain.AuditInvitationAccepted, created.ID, invitationChanges(inv, domain.InvitationPending, domain.InvitationAccepted))
+	return created, nil
+}
+
+// newToken signs a fresh invitation token expiring ttl after now.
+func (s *invitationService) newToken(now time.Time) (token, hash string, expiresAt time.Time, err error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", time.Time{}, domain.NewInternal("failed to generate invitation token")
+	}
+	expiresAt = now.Add(s.ttl)
+	payload, err := json.Marshal(invitationClaims{ExpiresAt: expiresAt.Unix(), Nonce: base64.RawURLEncoding.EncodeToString(nonce)})
+	if err != nil {
+		return "", "", time.Time{}, domain.NewInternal("failed to generate invitation token")
+	}
+	token = auth.SignToken(s.secret, payload)
+	return token, auth.HashOpaqueToken(token), expiresAt, nil
+}
+
+func (s *invitationService) send(ctx context.Context, inv *domain.Invitation, token string) error {
+	link := s.baseURL + "/invitations/accept?token=" + url.QueryEscape(token)
+	err := s.mailer.Send(ctx, domain.Message{
+		To:      inv.Email,
+		Subject: "You have been invited to userHub",
+		Body: fmt.Sprintf("Hello,\n\nYou have been invited to join userHub as %s. Open this link to create your account:\n\n%s\n\nThe link expires in %s. If you were not expecting this invitation, ignore this email.\n",
+			inv.Role, link, s.ttl),
+	})
+	if err != nil {
+		return domain.NewInternal("failed to send invitation email")
+	}
+	return nil
+}
+
+// invitationChanges describes an invitation for the audit log. Like
+// groupChanges, it always records the invitation ID.
+func invitationChanges(inv *domain.Invitation, from, to domain.InvitationStatus) map[string]domain.FieldChange {
+	changes := map[string]domain.FieldChange{
+		"invitation_id": {To: strconv.FormatUint(uint64(inv.ID), 10)},
+		"email":         {To: inv.Email},
+		"role":          {To: string(inv.Role)},
+	}
+	if from != to {
+		changes["status"] = domain.FieldChange{From: string(from), To: string(to)}
+	}
+	return changes
+}
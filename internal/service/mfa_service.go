@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+	"userHub/pkg/totp"
+)
+
+const (
+	// recoveryCodeCount codes are issued at a time.
+	recoveryCodeCount = 10
+	// maxMFAFailures wrong codes in a row lock the second factor for mfaLockout.
+	maxMFAFailures = 5
+	mfaLockout     = 15 * time.Minute
+	// totpSkew accepts codes one period early or late to allow for clock drift.
+	totpSkew = 1
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// mfaService implements domain.MFAService
+type mfaService struct {
+	users    domain.UserRepository
+	recovery domain.RecoveryCodeRepository
+	orgs     domain.OrganizationRepository
+	authz    domain.Authorizer
+	audit    domain.AuditRepository
+	issuer   string
+}
+
+// NewMFAService creates a new MFAService. issuer names the service in
+// authenticator apps.
+func NewMFAService(users domain.UserRepository, recovery domain.RecoveryCodeRepository, orgs domain.OrganizationRepository, authz domain.Authorizer, audit domain.AuditRepository, issuer string) domain.MFAService {
+	return &mfaService{users: users, recovery: recovery, orgs: orgs, authz: authz, audit: audit, issuer: issuer}
+}
+
+func (s *mfaService) VerifyCode(ctx context.Context, user *domain.User, code string) (*domain.User, error) {
+	if !user.Credential.MFAEnabled() {
+		return nil, domain.NewValidationError("multi-factor authentication is not enabled", nil)
+	}
+
+	now := time.Now().UTC()
+	if until := user.Credential.MFALockedUntil; until != nil && now.Before(*until) {
+		return nil, domain.NewUnauthorized("too many invalid codes, try again later")
+	}
+
+	code = strings.TrimSpace(code)
+	next := *user
+	accepted := false
+	if step, ok := totp.Validate(user.Credential.TOTPSecret, code, now, totpSkew); ok {
+		// A code seen before may have been observed by someone else.
+		if step > user.Credential.TOTPLastStep {
+			next.Credential.TOTPLastStep = step
+			accepted = true
+		}
+	} else if len(code) != totp.Digits {
+		err := s.recovery.Consume(ctx, user.ID, hashRecoveryCode(code), now)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		accepted = err == nil
+	}
+
+	if accepted {
+		next.Credential.MFAFailedAttempts = 0
+		next.Credential.MFALockedUntil = nil
+	} else {
+		next.Credential.MFAFailedAttempts++
+		if next.Credential.MFAFailedAttempts >= maxMFAFailures {
+			until := now.Add(mfaLockout)
+			next.Credential.MFALockedUntil = &until
+			next.Credential.MFAFailedAttempts = 0
+		}
+	}
+
+	if err := s.users.UpdateCredential(ctx, user.ID, user.Credential, next.Credential); err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, domain.NewUnauthorized("invalid verification code")
+	}
+	return &next, nil
+}
+
+func (s *mfaService) Enroll(ctx context.Context) (*domain.TOTPEnrollment, error) {
+	if err := forbidWhileImpersonating(ctx, "changing multi-factor authentication"); err != nil {
+		return nil, err
+	}
+	user, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.Credential.MFAEnabled() {
+		return nil, domain.NewConflict("multi-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, domain.NewInternal("failed to generate TOTP secret")
+	}
+	next := user.Credential
+	next.TOTPPendingSecret = secret
+	if err := s.users.UpdateCredential(ctx, user.ID, user.Credential, next); err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrollment{Secret: secret, URI: totp.URI(s.issuer, user.Email, secret)}, nil
+}
+
+func (s *mfaService) Confirm(ctx context.Context, code string) ([]string, error) {
+	if err := forbidWhileImpersonating(ctx, "changing multi-factor authentication"); err != nil {
+		return nil, err
+	}
+	user, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.Credential.MFAEnabled() {
+		return nil, domain.NewConflict("multi-factor authentication is already enabled")
+	}
+	if user.Credential.TOTPPendingSecret == "" {
+		return nil, domain.NewValidationError("no TOTP enrollment is pending", nil)
+	}
+
+	now := time.Now().UTC()
+	step, ok := totp.Validate(user.Credential.TOTPPendingSecret, strings.TrimSpace(code), now, totpSkew)
+	if !ok {
+		return nil, invalidCode()
+	}
+
+	next := user.Credential
+	next.TOTPSecret = next.TOTPPendingSecret
+	next.TOTPPendingSecret = ""
+	next.TOTPLastStep = step
+	next.MFAEnabledAt = &now
+	next.MFAFailedAttempts = 0
+	next.MFALockedUntil = nil
+	if err := s.users.UpdateCredential(ctx, user.ID, user.Credential, next); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.audit, domain.AuditMFAEnabled, user.ID, nil)
+	return codes, nil
+}
+
+func (s *mfaService) Disable(ctx context.Context, code string) error {
+	if err := forbidWhileImpersonating(ctx, "changing multi-factor authentication"); err != nil {
+		return err
+	}
+	user, err := s.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if user, err = s.reauthenticate(ctx, user, code); err != nil {
+		return err
+	}
+
+	next := user.Credential
+	next.TOTPSecret = ""
+	next.TOTPPendingSecret = ""
+	next.TOTPLastStep = 0
+	next.MFAEnabledAt = nil
+	if err := s.users.UpdateCredential(ctx, user.ID, user.Credential, next); err != nil {
+		return err
+	}
+	if err := s.recovery.Replace(ctx, user.ID, nil); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditMFADisabled, user.ID, nil)
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	if err := forbidWhileImpersonating(ctx, "changing multi-factor authentication"); err != nil {
+		return nil, err
+	}
+	user, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user, err = s.reauthenticate(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.audit, domain.AuditMFARecoveryReissued, user.ID, nil)
+	return codes, nil
+}
+
+func (s *mfaService) Policy(ctx context.Context) ([]domain.Role, error) {
+	if err := s.authz.Authorize(ctx, domain.PermMFAPolicy, 0); err != nil {
+		return nil, err
+	}
+	org, err := s.callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return org.MFARequiredRoles, nil
+}
+
+func (s *mfaService) SetPolicy(ctx context.Context, roles []domain.Role) ([]domain.Role, error) {
+	if err := s.authz.Authorize(ctx, domain.PermMFAPolicy, 0); err != nil {
+		return nil, err
+	}
+	if err := forbidWhileImpersonating(ctx, "changing the MFA policy"); err != nil {
+		return nil, err
+	}
+	org, err := s.callerOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := make([]domain.Role, 0, len(roles))
+	for _, r := range roles {
+		if !r.Valid() {
+			return nil, domain.NewValidationError("validation failed", map[string]string{"Roles": "must be admin, manager or member"})
+		}
+		if !containsRole(unique, r) {
+			unique = append(unique, r)
+		}
+	}
+
+	before := org.MFARequiredRoles
+	org.MFARequiredRoles = unique
+	updated, err := s.orgs.Update(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditMFAPolicyUpdated, 0, map[string]domain.FieldChange{
+		"roles": {From: joinRoles(before), To: joinRoles(updated.MFARequiredRoles)},
+	})
+	return updated.MFARequiredRoles, nil
+}
+
+func (s *mfaService) CheckPolicy(ctx context.Context) error {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok || p.MFA {
+		return nil
+	}
+	org, err := s.orgs.GetByID(ctx, p.TenantID)
+	if err != nil {
+		return err
+	}
+	if org.RequiresMFA(p.Role) {
+		return domain.NewForbidden("your role requires signing in with multi-factor authentication")
+	}
+	return nil
+}
+
+// caller returns the authenticated user in ctx.
+func (s *mfaService) caller(ctx context.Context) (*domain.User, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.NewUnauthorized("authentication required")
+	}
+	return s.users.GetByID(ctx, p.UserID)
+}
+
+func (s *mfaService) callerOrg(ctx context.Context) (*domain.Organization, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, domain.NewUnauthorized("authentication required")
+	}
+	return s.orgs.GetByID(ctx, p.TenantID)
+}
+
+// reauthenticate checks a fresh code before a change to the second factor
+// itself, so a stolen session alone cannot turn MFA off.
+func (s *mfaService) reauthenticate(ctx context.Context, user *domain.User, code string) (*domain.User, error) {
+	updated, err := s.VerifyCode(ctx, user, code)
+	if ae, ok := err.(*domain.AppError); ok && ae.Code == domain.CodeUnauthorized {
+		// The session itself is fine; only the code was wrong.
+		return nil, domain.NewValidationError(ae.Message, map[string]string{"Code": "is invalid"})
+	}
+	return updated, err
+}
+
+// issueRecoveryCodes replaces the user's recovery codes and returns the new
+// plaintext codes; they are shown once and only their hashes are kept.
+func (s *mfaService) issueRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, domain.NewInternal("failed to generate recovery codes")
+		}
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(b))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	if err := s.recovery.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode hashes a code the way it is stored, ignoring case,
+// spaces and the separator users may or may not type.
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	return auth.HashOpaqueToken(normalized)
+}
+
+func invalidCode() error {
+	return domain.NewValidationError("invalid verification code", map[string]string{"Code": "is invalid"})
+}
+
+func containsRole(roles []domain.Role, r domain.Role) bool {
+	for _, candidate := range roles {
+		if candidate == r {
+			return true
+		}
+	}
+	return false
+}
+
+func joinRoles(roles []domain.Role) string {
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, string(r))
+	}
+	return strings.Join(names, ",")
+}
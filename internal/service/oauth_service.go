@@ -0,0 +1,610 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// pkceS256 is the only PKCE method accepted; "plain" offers no protection
+// against an intercepted authorization request.
+const pkceS256 = "S256"
+
+var (
+	// scopeToken is the scope-token grammar of RFC 6749 section 3.3.
+	scopeToken = regexp.MustCompile(`^[\x21\x23-\x5B\x5D-\x7E]+$`)
+	// pkceChallenge is a base64url SHA-256 digest (RFC 7636 section 4.2).
+	pkceChallenge = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+)
+
+// oauthService implements domain.OAuthService
+type oauthService struct {
+	clients    domain.OAuthClientRepository
+	codes      domain.OAuthCodeRepository
+	tokens     domain.OAuthTokenRepository
+	users      domain.UserRepository
+	authz      domain.Authorizer
+	audit      domain.AuditRepository
+	ids        domain.IDTokenIssuer
+	codeTTL    time.Duration
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewOAuthService creates a new OAuthService. A nil IDTokenIssuer turns off
+// ID tokens for the openid scope.
+func NewOAuthService(clients domain.OAuthClientRepository, codes domain.OAuthCodeRepository, tokens domain.OAuthTokenRepository, users domain.UserRepository, authz domain.Authorizer, audit domain.AuditRepository, ids domain.IDTokenIssuer, codeTTL, accessTTL, refreshTTL time.Duration) domain.OAuthService {
+	if codeTTL <= 0 {
+		codeTTL = 5 * time.Minute
+	}
+	if accessTTL <= 0 {
+		accessTTL = time.Hour
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = 30 * 24 * time.Hour
+	}
+	return &oauthService{
+		clients: clients, codes: codes, tokens: tokens, users: users, authz: authz, audit: audit, ids: ids,
+		codeTTL: codeTTL, accessTTL: accessTTL, refreshTTL: refreshTTL,
+	}
+}
+
+func (s *oauthService) RegisterClient(ctx context.Context, client *domain.OAuthClient, public bool) (*domain.OAuthClient, string, error) {
+	if err := s.authz.Authorize(ctx, domain.PermOAuthClients, 0); err != nil {
+		return nil, "", err
+	}
+	if err := validateClient(client, public); err != nil {
+		return nil, "", err
+	}
+
+	next := *client
+	next.TenantID = tenantOf(ctx)
+	id, _, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, "", domain.NewInternal("failed to generate client ID")
+	}
+	next.ClientID = id[:24]
+
+	var secret string
+	if !public {
+		raw, hash, err := auth.NewOpaqueToken()
+		if err != nil {
+			return nil, "", domain.NewInternal("failed to generate client secret")
+		}
+		secret = raw
+		next.SecretHash = hash
+	}
+
+	created, err := s.clients.Create(ctx, &next)
+	if err != nil {
+		return nil, "", err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditOAuthClientCreated, 0, map[string]domain.FieldChange{
+		"client_id": {To: created.ClientID},
+		"name":      {To: created.Name},
+	})
+	return created, secret, nil
+}
+
+func (s *oauthService) ListClients(ctx context.Context) ([]domain.OAuthClient, error) {
+	if err := s.authz.Authorize(ctx, domain.PermOAuthClients, 0); err != nil {
+		return nil, err
+	}
+	return s.clients.ListByTenant(ctx, tenantOf(ctx))
+}
+
+func (s *oauthService) DeleteClient(ctx context.Context, clientID string) error {
+	if err := s.authz.Authorize(ctx, domain.PermOAuthClients, 0); err != nil {
+		return err
+	}
+	client, err := s.clients.GetByClientID(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if client.TenantID != tenantOf(ctx) {
+		return domain.NewNotFound("OAuth client not found")
+	}
+
+	if err := s.clients.Delete(ctx, client.ID); err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeAllForClient(ctx, client.ClientID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditOAuthClientDeleted, 0, map[string]domain.FieldChange{
+		"client_id": {From: client.ClientID},
+		"name":      {From: client.Name},
+	})
+	return nil
+}
+
+func (s *oauthService) CheckAuthorize(ctx context.Context, req domain.AuthorizeRequest) (*domain.OAuthClient, string, error) {
+	// Until client and redirect URI check out, errors go to the user, not the client.
+	client, err := s.clients.GetByClientID(ctx, req.ClientID)
+	if err == nil {
+		if p, ok := domain.PrincipalFromContext(ctx); ok && client.TenantID != p.TenantID {
+			err = domain.NewNotFound("client not found")
+		}
+	}
+	if err != nil {
+		if isNotFound(err) {
+			return nil, "", domain.NewValidationError("unknown client", map[string]string{"client_id": "is not registered"})
+		}
+		return nil, "", err
+	}
+	if !client.AllowsRedirect(req.RedirectURI) {
+		return nil, "", domain.NewValidationError("invalid redirect URI", map[string]string{"redirect_uri": "is not registered for this client"})
+	}
+
+	if req.ResponseType != "code" {
+		return nil, "", domain.NewOAuthError(domain.OAuthUnsupportedResponseType, "response_type must be code")
+	}
+	if !client.AllowsGrant(domain.GrantAuthorizationCode) {
+		return nil, "", domain.NewOAuthError(domain.OAuthUnauthorizedClient, "client may not use the authorization code grant")
+	}
+	if req.CodeChallenge == "" {
+		return nil, "", domain.NewOAuthError(domain.OAuthInvalidRequest, "code_challenge is required")
+	}
+	if req.CodeChallengeMethod != pkceS256 {
+		return nil, "", domain.NewOAuthError(domain.OAuthInvalidRequest, "code_challenge_method must be S256")
+	}
+	if !pkceChallenge.MatchString(req.CodeChallenge) {
+		return nil, "", domain.NewOAuthError(domain.OAuthInvalidRequest, "code_challenge is malformed")
+	}
+	scope, err := resolveScope(req.Scope, client.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, scope, nil
+}
+
+func (s *oauthService) Authorize(ctx context.Context, req domain.AuthorizeRequest) (string, error) {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return "", domain.NewUnauthorized("authentication required")
+	}
+	if err := forbidWhileImpersonating(ctx, "authorizing applications"); err != nil {
+		return "", err
+	}
+	client, scope, err := s.CheckAuthorize(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	raw, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return "", domain.NewInternal("failed to generate authorization code")
+	}
+	if _, err := s.codes.Create(ctx, &domain.OAuthCode{
+		CodeHash:      hash,
+		ClientID:      client.ClientID,
+		UserID:        p.UserID,
+		TenantID:      p.TenantID,
+		RedirectURI:   req.RedirectURI,
+		Scope:         scope,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+		ExpiresAt:     time.Now().UTC().Add(s.codeTTL),
+	}); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+func (s *oauthService) Token(ctx context.Context, req domain.TokenRequest) (*domain.OAuthTokenSet, error) {
+	client, err := s.authenticateClient(ctx, req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case "":
+		return nil, domain.NewOAuthError(domain.OAuthInvalidRequest, "grant_type is required")
+	case domain.GrantAuthorizationCode, domain.GrantRefreshToken, domain.GrantClientCredentials:
+	default:
+		return nil, domain.NewOAuthError(domain.OAuthUnsupportedGrantType, "unsupported grant_type")
+	}
+	if !client.AllowsGrant(req.GrantType) {
+		return nil, domain.NewOAuthError(domain.OAuthUnauthorizedClient, "client may not use this grant type")
+	}
+
+	switch req.GrantType {
+	case domain.GrantAuthorizationCode:
+		return s.exchangeCode(ctx, client, req)
+	case domain.GrantRefreshToken:
+		return s.refresh(ctx, client, req)
+	default:
+		return s.clientCredentials(ctx, client, req)
+	}
+}
+
+func (s *oauthService) Introspect(ctx context.Context, creds domain.ClientCredentials, token string) (*domain.TokenIntrospection, error) {
+	client, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Confidential() {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidClient, "introspection requires a confidential client")
+	}
+
+	inactive := &domain.TokenIntrospection{}
+	t, err := s.tokens.GetByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return inactive, nil
+		}
+		return nil, err
+	}
+	if !t.Active(time.Now().UTC()) || t.TenantID != client.TenantID {
+		return inactive, nil
+	}
+
+	result := &domain.TokenIntrospection{
+		Active:    true,
+		Scope:     t.Scope,
+		ClientID:  t.ClientID,
+		Kind:      t.Kind,
+		UserID:    t.UserID,
+		TenantID:  t.TenantID,
+		ExpiresAt: t.ExpiresAt,
+		IssuedAt:  t.CreatedAt,
+	}
+	if t.UserID != 0 {
+		user, err := s.users.GetByID(domain.WithTenant(ctx, t.TenantID), t.UserID)
+		if err != nil {
+			if isNotFound(err) {
+				return inactive, nil
+			}
+			return nil, err
+		}
+		result.Username = user.Email
+	}
+	return result, nil
+}
+
+func (s *oauthService) Revoke(ctx context.Context, creds domain.ClientCredentials, token string) error {
+	client, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return err
+	}
+
+	t, err := s.tokens.GetByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	// Clients may only revoke their own tokens; others are silently left alone.
+	if t.ClientID != client.ClientID {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if t.Kind == domain.OAuthRefreshToken {
+		// Revoking a refresh token ends the whole grant (RFC 7009 section 2.1).
+		return s.tokens.RevokeFamily(ctx, t.FamilyID, now)
+	}
+	if err := s.tokens.Revoke(ctx, t.ID, now); err != nil && !isConflict(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *oauthService) exchangeCode(ctx context.Context, client *domain.OAuthClient, req domain.TokenRequest) (*domain.OAuthTokenSet, error) {
+	if req.Code == "" {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidRequest, "code is required")
+	}
+	if req.CodeVerifier == "" {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidRequest, "code_verifier is required")
+	}
+
+	code, err := s.codes.GetByHash(ctx, auth.HashOpaqueToken(req.Code))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "invalid authorization code")
+		}
+		return nil, err
+	}
+	if code.ClientID != client.ClientID {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "invalid authorization code")
+	}
+
+	now := time.Now().UTC()
+	if code.ConsumedAt != nil {
+		// A replayed code may have been stolen: revoke what it was exchanged for.
+		s.revokeFamily(ctx, code.CodeHash)
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "authorization code already used")
+	}
+	if !now.Before(code.ExpiresAt) {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "authorization code has expired")
+	}
+	if req.RedirectURI != code.RedirectURI {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "redirect_uri does not match the authorization request")
+	}
+	if !verifyPKCE(req.CodeVerifier, code.CodeChallenge) {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "code_verifier does not match the code_challenge")
+	}
+
+	if err := s.codes.MarkConsumed(ctx, code.ID, now); err != nil {
+		if isConflict(err) {
+			s.revokeFamily(ctx, code.CodeHash)
+			return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "authorization code already used")
+		}
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(domain.WithTenant(ctx, code.TenantID), code.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "the user no longer exists")
+		}
+		return nil, err
+	}
+
+	// Tokens from this code form a family named after it, so a replay can find them.
+	set, err := s.issue(ctx, client, code.UserID, code.TenantID, code.Scope, code.CodeHash, client.AllowsGrant(domain.GrantRefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	return s.withIDToken(ctx, set, user, client, code.Nonce)
+}
+
+func (s *oauthService) refresh(ctx context.Context, client *domain.OAuthClient, req domain.TokenRequest) (*domain.OAuthTokenSet, error) {
+	if req.RefreshToken == "" {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidRequest, "refresh_token is required")
+	}
+
+	current, err := s.tokens.GetByHash(ctx, auth.HashOpaqueToken(req.RefreshToken))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "invalid refresh token")
+		}
+		return nil, err
+	}
+	if current.Kind != domain.OAuthRefreshToken || current.ClientID != client.ClientID {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "invalid refresh token")
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case current.RevokedAt != nil:
+		// Refresh tokens are single-use; a second use is treated as theft.
+		s.revokeFamily(ctx, current.FamilyID)
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "refresh token has been revoked")
+	case !now.Before(current.ExpiresAt):
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "refresh token has expired")
+	}
+
+	scope := current.Scope
+	if req.Scope != "" {
+		// A refresh may narrow the grant but never widen it.
+		if scope, err = resolveScope(req.Scope, strings.Fields(current.Scope)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.tokens.Revoke(ctx, current.ID, now); err != nil {
+		if isConflict(err) {
+			s.revokeFamily(ctx, current.FamilyID)
+			return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "refresh token has been revoked")
+		}
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(domain.WithTenant(ctx, current.TenantID), current.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "the user no longer exists")
+		}
+		return nil, err
+	}
+
+	set, err := s.issue(ctx, client, current.UserID, current.TenantID, scope, current.FamilyID, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.withIDToken(ctx, set, user, client, "")
+}
+
+func (s *oauthService) clientCredentials(ctx context.Context, client *domain.OAuthClient, req domain.TokenRequest) (*domain.OAuthTokenSet, error) {
+	if !client.Confidential() {
+		return nil, domain.NewOAuthError(domain.OAuthUnauthorizedClient, "public clients cannot use client credentials")
+	}
+	scope, err := resolveScope(req.Scope, client.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	familyID, _, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, domain.NewInternal("failed to start grant")
+	}
+	return s.issue(ctx, client, 0, client.TenantID, scope, familyID, false)
+}
+
+// issue stores a new access token, and a refresh token if withRefresh, in the
+// given family.
+func (s *oauthService) issue(ctx context.Context, client *domain.OAuthClient, userID, tenantID uint, scope, familyID string, withRefresh bool) (*domain.OAuthTokenSet, error) {
+	now := time.Now().UTC()
+	set := &domain.OAuthTokenSet{Scope: scope, ExpiresAt: now.Add(s.accessTTL)}
+
+	kinds := []domain.OAuthTokenKind{domain.OAuthAccessToken}
+	if withRefresh {
+		kinds = append(kinds, domain.OAuthRefreshToken)
+	}
+	for _, kind := range kinds {
+		raw, hash, err := auth.NewOpaqueToken()
+		if err != nil {
+			return nil, domain.NewInternal("failed to generate token")
+		}
+		expiresAt := set.ExpiresAt
+		if kind == domain.OAuthRefreshToken {
+			expiresAt = now.Add(s.refreshTTL)
+		}
+		if _, err := s.tokens.Create(ctx, &domain.OAuthToken{
+			TokenHash: hash,
+			Kind:      kind,
+			ClientID:  client.ClientID,
+			UserID:    userID,
+			TenantID:  tenantID,
+			FamilyID:  familyID,
+			Scope:     scope,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return nil, err
+		}
+		if kind == domain.OAuthRefreshToken {
+			set.RefreshToken = raw
+		} else {
+			set.AccessToken = raw
+		}
+	}
+	return set, nil
+}
+
+// withIDToken adds an ID token to set when its scope asks for OpenID Connect.
+func (s *oauthService) withIDToken(ctx context.Context, set *domain.OAuthTokenSet, user *domain.User, client *domain.OAuthClient, nonce string) (*domain.OAuthTokenSet, error) {
+	if s.ids == nil || !domain.HasScope(set.Scope, domain.ScopeOpenID) {
+		return set, nil
+	}
+	idToken, err := s.ids.IssueIDToken(ctx, user, client.ClientID, set.Scope, nonce)
+	if err != nil {
+		return nil, err
+	}
+	set.IDToken = idToken
+	return set, nil
+}
+
+// authenticateClient checks the client's credentials. Public clients only
+// identify themselves.
+func (s *oauthService) authenticateClient(ctx context.Context, creds domain.ClientCredentials) (*domain.OAuthClient, error) {
+	failed := domain.NewOAuthError(domain.OAuthInvalidClient, "client authentication failed")
+	if creds.ClientID == "" {
+		return nil, failed
+	}
+	client, err := s.clients.GetByClientID(ctx, creds.ClientID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, failed
+		}
+		return nil, err
+	}
+	if client.Confidential() {
+		hash := auth.HashOpaqueToken(creds.ClientSecret)
+		if creds.ClientSecret == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(client.SecretHash)) != 1 {
+			return nil, failed
+		}
+	}
+	return client, nil
+}
+
+func (s *oauthService) revokeFamily(ctx context.Context, familyID string) {
+	if err := s.tokens.RevokeFamily(ctx, familyID, time.Now().UTC()); err != nil {
+		log.Printf("oauth: failed to revoke token family: %v", err)
+	}
+}
+
+// validateClient checks a client registration.
+func validateClient(c *domain.OAuthClient, public bool) error {
+	fields := map[string]string{}
+	if strings.TrimSpace(c.Name) == "" {
+		fields["name"] = "is required"
+	}
+
+	if len(c.GrantTypes) == 0 {
+		fields["grant_types"] = "at least one grant type is required"
+	}
+	for _, g := range c.GrantTypes {
+		switch g {
+		case domain.GrantAuthorizationCode, domain.GrantRefreshToken:
+		case domain.GrantClientCredentials:
+			if public {
+				fields["grant_types"] = "public clients cannot use client_credentials"
+			}
+		default:
+			fields["grant_types"] = "unsupported grant type " + g
+		}
+	}
+	if c.AllowsGrant(domain.GrantRefreshToken) && !c.AllowsGrant(domain.GrantAuthorizationCode) {
+		fields["grant_types"] = "refresh_token requires authorization_code"
+	}
+
+	if c.AllowsGrant(domain.GrantAuthorizationCode) && len(c.RedirectURIs) == 0 {
+		fields["redirect_uris"] = "at least one redirect URI is required"
+	}
+	for _, raw := range c.RedirectURIs {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Fragment != "" {
+			fields["redirect_uris"] = "must be absolute URLs without a fragment"
+		}
+	}
+
+	for _, scope := range c.Scopes {
+		if !scopeToken.MatchString(scope) {
+			fields["scopes"] = "invalid scope " + scope
+		}
+	}
+
+	if len(fields) > 0 {
+		return domain.NewValidationError("validation failed", fields)
+	}
+	return nil
+}
+
+// resolveScope checks a space-separated scope request against what is
+// allowed. An empty request gets everything allowed.
+func resolveScope(requested string, allowed []string) (string, error) {
+	if strings.TrimSpace(requested) == "" {
+		return strings.Join(allowed, " "), nil
+	}
+	granted := make([]string, 0)
+	for _, scope := range strings.Fields(requested) {
+		if !containsString(allowed, scope) {
+			return "", domain.NewOAuthError(domain.OAuthInvalidScope, "scope "+scope+" is not allowed")
+		}
+		if !containsString(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	return strings.Join(granted, " "), nil
+}
+
+// verifyPKCE checks an RFC 7636 S256 code verifier against its challenge.
+func verifyPKCE(verifier, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// tenantOf returns the tenant in ctx, or the default organization.
+func tenantOf(ctx context.Context) uint {
+	if id, ok := domain.TenantFromContext(ctx); ok {
+		return id
+	}
+	return domain.DefaultTenantID
+}
+
+func isConflict(err error) bool {
+	ae, ok := err.(*domain.AppError)
+	return ok && ae.Code == domain.CodeConflict
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
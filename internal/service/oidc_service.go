@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// oidcService implements domain.OIDCService
+type oidcService struct {
+	keys   domain.KeySet
+	tokens domain.OAuthTokenRepository
+	users  domain.UserRepository
+	issuer string
+	ttl    time.Duration
+}
+
+// NewOIDCService creates a new OIDCService. issuer is the public base URL of
+// userHub, without a trailing slash; ID tokens live for ttl.
+func NewOIDCService(keys domain.KeySet, tokens domain.OAuthTokenRepository, users domain.UserRepository, issuer string, ttl time.Duration) domain.OIDCService {
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	return &oidcService{keys: keys, tokens: tokens, users: users, issuer: strings.TrimSuffix(issuer, "/"), ttl: ttl}
+}
+
+func (s *oidcService) Issuer() string {
+	return s.issuer
+}
+
+func (s *oidcService) JWKS() []domain.JSONWebKey {
+	return s.keys.PublicKeys()
+}
+
+func (s *oidcService) IssueIDToken(ctx context.Context, user *domain.User, clientID, scope, nonce string) (string, error) {
+	now := time.Now()
+	claims := domain.UserClaims(user, scope)
+	claims["iss"] = s.issuer
+	claims["aud"] = clientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(s.ttl).Unix()
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	return s.keys.Sign(claims)
+}
+
+func (s *oidcService) UserInfo(ctx context.Context, accessToken string) (map[string]any, error) {
+	invalid := domain.NewOAuthError(domain.OAuthInvalidToken, "the access token is invalid or has expired")
+
+	t, err := s.tokens.GetByHash(ctx, auth.HashOpaqueToken(accessToken))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if t.Kind != domain.OAuthAccessToken || t.UserID == 0 || !t.Active(time.Now().UTC()) {
+		return nil, invalid
+	}
+	if !domain.HasScope(t.Scope, domain.ScopeOpenID) {
+		return nil, domain.NewOAuthError(domain.OAuthInsufficientScope, "the access token lacks the openid scope")
+	}
+
+	user, err := s.users.GetByID(domain.WithTenant(ctx, t.TenantID), t.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	return domain.UserClaims(user, t.Scope), nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"userHub/internal/domain"
+)
+
+// organizationService implements domain.OrganizationService
+type organizationService struct {
+	orgs  domain.OrganizationRepository
+	users domain.UserService
+	authz domain.Authorizer
+}
+
+// NewOrganizationService creates a new OrganizationService. Owners are
+// created through users, so they are audited and mailed like anyone else.
+func NewOrganizationService(orgs domain.OrganizationRepository, users domain.UserService, authz domain.Authorizer) domain.OrganizationService {
+	return &organizationService{orgs: orgs, users: users, authz: authz}
+}
+
+func (s *organizationService) Create(ctx context.Context, org *domain.Organization, owner *domain.User) (*domain.Organization, *domain.User, error) {
+	if err := s.requirePlatformAdmin(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := s.orgs.GetBySlug(ctx, org.Slug); err == nil {
+		return nil, nil, domain.NewConflict("organization slug already exists")
+	}
+
+	created, err := s.orgs.Create(ctx, org)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owner.Role = domain.RoleAdmin
+	admin, err := s.users.Create(domain.WithTenant(ctx, created.ID), owner)
+	if err != nil {
+		// Without its admin nobody could manage the organization.
+		if derr := s.orgs.Delete(ctx, created.ID); derr != nil {
+			log.Printf("organization: remove %d after failed owner creation: %v", created.ID, derr)
+		}
+		return nil, nil, err
+	}
+
+	return created, admin, nil
+}
+
+func (s *organizationService) GetByID(ctx context.Context, id uint) (*domain.Organization, error) {
+	// Everyone may see their own organization.
+	if p, ok := domain.PrincipalFromContext(ctx); !ok || p.TenantID != id {
+		if err := s.requirePlatformAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return s.orgs.GetByID(ctx, id)
+}
+
+func (s *organizationService) List(ctx context.Context) ([]*domain.Organization, error) {
+	if err := s.requirePlatformAdmin(ctx); err != nil {
+		p, ok := domain.PrincipalFromContext(ctx)
+		if !ok {
+			return nil, err
+		}
+		own, err := s.orgs.GetByID(ctx, p.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.Organization{own}, nil
+	}
+	return s.orgs.List(ctx)
+}
+
+func (s *organizationService) Exists(ctx context.Context, id uint) (bool, error) {
+	if _, err := s.orgs.GetByID(ctx, id); err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// requirePlatformAdmin allows only admins of the default organization,
+// which operates the platform, to manage other organizations.
+func (s *organizationService) requirePlatformAdmin(ctx context.Context) error {
+	if err := s.authz.Authorize(ctx, domain.PermOrgsManage, 0); err != nil {
+		return err
+	}
+	if p, _ := domain.PrincipalFromContext(ctx); p.TenantID != domain.DefaultTenantID {
+		return domain.NewForbidden("only platform administrators can manage organizations")
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// resetWindow is the period over which the per-user reset limit is counted.
+const resetWindow = time.Hour
+
+// forgotQueueSize is how many reset requests may wait to be processed.
+const forgotQueueSize = 256
+
+// passwordResetService implements domain.PasswordResetService
+type passwordResetService struct {
+	users     domain.UserRepository
+	resets    domain.PasswordResetRepository
+	passwords domain.PasswordService
+	refresh   domain.RefreshTokenRepository
+	oauth     domain.OAuthTokenRepository
+	keys      domain.APIKeyRepository
+	audit     domain.AuditRepository
+	mailer    domain.Mailer
+	ttl       time.Duration
+	limit     int
+	baseURL   string
+	forgot    chan forgotRequest
+}
+
+// forgotRequest is a reset request waiting for the background worker.
+type forgotRequest struct {
+	ctx   context.Context
+	email string
+}
+
+// NewPasswordResetService creates a new PasswordResetService.
+// Reset links expire after ttl and point at baseURL; at most limit links are
+// mailed to the same user per hour. A reset ends every session of the user:
+// refresh tokens, OAuth tokens and API keys.
+func NewPasswordResetService(users domain.UserRepository, resets domain.PasswordResetRepository, passwords domain.PasswordService, refresh domain.RefreshTokenRepository, oauth domain.OAuthTokenRepository, keys domain.APIKeyRepository, audit domain.AuditRepository, mailer domain.Mailer, ttl time.Duration, limit int, baseURL string) domain.PasswordResetService {
+	if ttl <= 0 {
+		ttl = 30 * time.Minute
+	}
+	if limit <= 0 {
+		limit = 3
+	}
+	s := &passwordResetService{
+		users:     users,
+		resets:    resets,
+		passwords: passwords,
+		refresh:   refresh,
+		oauth:     oauth,
+		keys:      keys,
+		audit:     audit,
+		mailer:    mailer,
+		ttl:       ttl,
+		limit:     limit,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		forgot:    make(chan forgotRequest, forgotQueueSize),
+	}
+	go s.work()
+	return s
+}
+
+// Forgot only queues the request. Looking the user up and mailing the link
+// happen in the background, so the response takes as long for an unknown
+// address as for a registered one.
+func (s *passwordResetService) Forgot(ctx context.Context, email string) error {
+	select {
+	case s.forgot <- forgotRequest{ctx: context.WithoutCancel(ctx), email: email}:
+	default:
+		log.Printf("password: reset queue is full, dropping a request")
+	}
+	return nil
+}
+
+// work processes queued reset requests one at a time, which also keeps the
+// per-user limit exact.
+func (s *passwordResetService) work() {
+	for req := range s.forgot {
+		if err := s.sendReset(req.ctx, req.email); err != nil {
+			log.Printf("password: failed to process reset request: %v", err)
+		}
+	}
+}
+
+func (s *passwordResetService) sendReset(ctx context.Context, email string) error {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	sent, err := s.resets.CountSince(ctx, user.ID, now.Add(-resetWindow))
+	if err != nil {
+		return err
+	}
+	if sent >= int64(s.limit) {
+		log.Printf("password: reset limit reached for user %d", user.ID)
+		return nil
+	}
+
+	// Only the newest link can be used.
+	if err := s.resets.ConsumeAllForUser(ctx, user.ID, now); err != nil {
+		return err
+	}
+
+	raw, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return domain.NewInternal("failed to generate password reset token")
+	}
+	if _, err := s.resets.Create(ctx, &domain.PasswordReset{
+		UserID:    user.ID,
+		TenantID:  user.TenantID,
+		Email:     user.Email,
+		TokenHash: hash,
+		ExpiresAt: now.Add(s.ttl),
+	}); err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/password/reset?token=" + url.QueryEscape(raw)
+	err = s.mailer.Send(ctx, domain.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nSomeone asked to reset the password of your account. To choose a new password, open this link:\n\n%s\n\nThe link expires in %s. If you did not ask for this, ignore this email; your password stays the same.\n",
+			user.Name, link, s.ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("sending the reset email to user %d: %w", user.ID, err)
+	}
+	return nil
+}
+
+func (s *passwordResetService) Reset(ctx context.Context, token, newPassword string) error {
+	invalid := domain.NewValidationError("invalid or expired password reset token", nil)
+
+	reset, err := s.resets.GetByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		if isNotFound(err) {
+			return invalid
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	if reset.ConsumedAt != nil || now.After(reset.ExpiresAt) {
+		return invalid
+	}
+	if err := s.resets.MarkConsumed(ctx, reset.ID, now); err != nil {
+		if ae, ok := err.(*domain.AppError); ok && ae.Code == domain.CodeConflict {
+			return invalid
+		}
+		return err
+	}
+
+	// The token, not the request, decides which tenant the user belongs to.
+	ctx = domain.WithTenant(ctx, reset.TenantID)
+	current, err := s.users.GetByID(ctx, reset.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return invalid
+		}
+		return err
+	}
+	if !strings.EqualFold(current.Email, reset.Email) {
+		return invalid
+	}
+
+	cred, err := s.passwords.NewCredential(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.users.UpdateCredential(ctx, current.ID, current.Credential, current.Credential.WithPassword(cred)); err != nil {
+		return err
+	}
+	updated := current
+	// The token arrived by email, which proves the address as well.
+	if !current.EmailVerified() {
+		next := *current
+		next.EmailVerifiedAt = &now
+		if updated, err = s.users.Update(ctx, &next); err != nil {
+			return err
+		}
+	}
+
+	// Whoever held the old password must not keep a session.
+	if err := s.resets.ConsumeAllForUser(ctx, updated.ID, now); err != nil {
+		log.Printf("password: failed to discard reset tokens of user %d: %v", updated.ID, err)
+	}
+	if err := s.refresh.RevokeAllForUser(ctx, updated.ID, now); err != nil {
+		return err
+	}
+	if err := s.oauth.RevokeAllForUser(ctx, updated.ID, now); err != nil {
+		return err
+	}
+	if err := s.keys.RevokeAllForUser(ctx, updated.ID, now); err != nil {
+		return err
+	}
+
+	actor := domain.WithPrincipal(ctx, &domain.Principal{UserID: updated.ID, TenantID: updated.TenantID, Role: updated.Role})
+	recordAudit(actor, s.audit, domain.AuditUserPasswordReset, updated.ID, userChanges(current, updated))
+	return nil
+}
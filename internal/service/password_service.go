@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"userHub/internal/domain"
+)
+
+// passwordService implements domain.PasswordService
+type passwordService struct {
+	repo   domain.UserRepository
+	hasher domain.PasswordHasher
+	// dummyHash is verified against when the user does not exist, so that
+	// unknown emails take as long to reject as wrong passwords.
+	dummyHash string
+}
+
+// NewPasswordService creates a new PasswordService
+func NewPasswordService(repo domain.UserRepository, hasher domain.PasswordHasher) domain.PasswordService {
+	dummy, err := hasher.Hash("userhub-dummy-password")
+	if err != nil {
+		log.Println("password: failed to precompute dummy hash:", err)
+	}
+	return &passwordService{repo: repo, hasher: hasher, dummyHash: dummy}
+}
+
+func (s *passwordService) VerifyCredentials(ctx context.Context, email, password string) (*domain.User, error) {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if ae, ok := err.(*domain.AppError); ok && ae.Code == domain.CodeNotFound {
+			s.hasher.Verify(password, s.dummyHash)
+			return nil, domain.NewUnauthorized("invalid credentials")
+		}
+		return nil, err
+	}
+	if !user.Credential.HasPassword() {
+		s.hasher.Verify(password, s.dummyHash)
+		return nil, domain.NewUnauthorized("invalid credentials")
+	}
+
+	match, needsRehash, err := s.hasher.Verify(password, user.Credential.PasswordHash)
+	if err != nil {
+		return nil, domain.NewInternal("stored password hash is invalid")
+	}
+	if !match {
+		return nil, domain.NewUnauthorized("invalid credentials")
+	}
+
+	// Upgrade hashes made with outdated parameters while we have the plaintext.
+	// A failure here must not block the login itself.
+	if needsRehash {
+		if hash, err := s.hasher.Hash(password); err == nil {
+			next := user.Credential
+			next.PasswordHash = hash
+			if err := s.repo.UpdateCredential(ctx, user.ID, user.Credential, next); err == nil {
+				user.Credential = next
+			} else {
+				log.Printf("password: rehash for user %d failed: %v", user.ID, err)
+			}
+		}
+	}
+
+	return user, nil
+}
+
+func (s *passwordService) NewCredential(password string) (domain.Credential, error) {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return domain.Credential{}, domain.NewInternal("failed to hash password")
+	}
+	now := time.Now().UTC()
+	return domain.Credential{PasswordHash: hash, PasswordChangedAt: &now}, nil
+}
+
+func (s *passwordService) ChangePassword(ctx context.Context, userID uint, current, next string) error {
+	if err := forbidWhileImpersonating(ctx, "changing the password"); err != nil {
+		return err
+	}
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Credential.HasPassword() {
+		match, _, err := s.hasher.Verify(current, user.Credential.PasswordHash)
+		if err != nil {
+			return domain.NewInternal("stored password hash is invalid")
+		}
+		if !match {
+			return domain.NewValidationError("validation failed", map[string]string{"CurrentPassword": "is incorrect"})
+		}
+	}
+
+	cred, err := s.NewCredential(next)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateCredential(ctx, user.ID, user.Credential, user.Credential.WithPassword(cred))
+}
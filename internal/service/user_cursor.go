@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+)
+
+// maxCursorLimit caps the page size of cursor listings.
+const maxCursorLimit = 100
+
+// cursorClaims is the signed payload of a list cursor. Query fingerprints
+// the filters, sort and tenant the cursor was issued for, so a cursor cannot
+// be replayed against a different listing.
+type cursorClaims struct {
+	ID       uint                            `json:"id"`
+	Keys     map[domain.UserSortField]string `json:"k,omitempty"`
+	Backward bool                            `json:"b,omitempty"`
+	Query    string                          `json:"q"`
+}
+
+// cursorQuery fingerprints a user listing; the page size may change
+// between pages.
+func cursorQuery(ctx context.Context, query domain.UserQuery) string {
+	tenantID, _ := domain.TenantFromContext(ctx)
+	var expr string
+	if query.Filter != nil {
+		expr = query.Filter.String()
+	}
+	query.Page, query.Limit, query.Filter = 0, 0, nil
+	// Marshalling a struct of plain fields cannot fail.
+	b, _ := json.Marshal(struct {
+		Tenant uint
+		Query  domain.UserQuery
+		Filter string
+	}{tenantID, query, expr})
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func (s *userService) encodeCursor(c domain.UserCursor, query string) string {
+	payload, _ := json.Marshal(cursorClaims{ID: c.ID, Keys: c.Keys, Backward: c.Backward, Query: query})
+	return auth.SignToken(s.cursors, payload)
+}
+
+// decodeCursor verifies a cursor issued for query; an empty one starts the listing.
+func (s *userService) decodeCursor(cursor, query string) (domain.UserCursor, error) {
+	if cursor == "" {
+		return domain.UserCursor{}, nil
+	}
+	invalid := domain.NewValidationError("invalid cursor", map[string]string{"cursor": "is malformed or was issued for a different query"})
+
+	payload, err := auth.VerifySignedToken(s.cursors, cursor)
+	if err != nil {
+		return domain.UserCursor{}, invalid
+	}
+	var claims cursorClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Query != query {
+		return domain.UserCursor{}, invalid
+	}
+	return domain.UserCursor{ID: claims.ID, Keys: claims.Keys, Backward: claims.Backward}, nil
+}
+
+// adjacentCursors positions the pages on either side of users, which were
+// listed from at in ordering; more reports whether users continue in at's
+// direction. A nil cursor means that end of the listing has been reached.
+func adjacentCursors(at domain.UserCursor, users []*domain.User, more bool, ordering []domain.UserSort) (next, prev *domain.UserCursor) {
+	// A page listed from a cursor has users on the side it came from. If it
+	// is empty, they are best reached from the other end of the listing.
+	started := at.ID != 0
+	if len(users) == 0 {
+		if started && at.Backward {
+			return &domain.UserCursor{}, nil
+		}
+		if started {
+			return nil, &domain.UserCursor{Backward: true}
+		}
+		return nil, nil
+	}
+
+	after := domain.CursorAt(users[len(users)-1], ordering, false)
+	before := domain.CursorAt(users[0], ordering, true)
+	// Going backward, users continue before the page and came from after it.
+	if at.Backward {
+		more, started = started, more
+	}
+	if more {
+		next = &after
+	}
+	if started {
+		prev = &before
+	}
+	return next, prev
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"userHub/internal/domain"
+	"userHub/pkg/search"
+)
+
+// maxSearchLimit caps the number of hits a search returns.
+const maxSearchLimit = 50
+
+// userSearchService implements domain.UserSearchService
+type userSearchService struct {
+	searcher domain.UserSearcher
+	authz    domain.Authorizer
+}
+
+// NewUserSearchService creates a new UserSearchService
+func NewUserSearchService(searcher domain.UserSearcher, authz domain.Authorizer) domain.UserSearchService {
+	return &userSearchService{searcher: searcher, authz: authz}
+}
+
+func (s *userSearchService) Search(ctx context.Context, text string, limit int) ([]domain.UserSearchResult, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersList, 0); err != nil {
+		return nil, err
+	}
+	terms := search.Terms(text)
+	if len(terms) == 0 {
+		return nil, domain.NewValidationError("invalid search", map[string]string{"q": "must contain a letter or digit"})
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	hits, err := s.searcher.Search(ctx, text, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Highlighting is done here rather than by the searcher so it looks the
+	// same whichever one found the user.
+	results := make([]domain.UserSearchResult, len(hits))
+	for i, hit := range hits {
+		results[i] = domain.UserSearchResult{UserSearchHit: hit, Highlights: make(map[string]string)}
+		for field, value := range map[string]string{"name": hit.User.Name, "email": hit.User.Email} {
+			if marked, ok := search.Highlight(value, terms); ok {
+				results[i].Highlights[field] = marked
+			}
+		}
+	}
+	return results, nil
+}
@@ -2,18 +2,30 @@ package service
 
 import (
 	"context"
+	"log"
+	"strconv"
+	"time"
 
 	"userHub/internal/domain"
 )
 
 // userService implements domain.UserService
 type userService struct {
-	repo domain.UserRepository
+	repo      domain.UserRepository
+	authz     domain.Authorizer
+	audit     domain.AuditRepository
+	verifier  domain.EmailVerifier
+	retention time.Duration
+	cursors   []byte
 }
 
-// NewUserService creates a new UserService
-func NewUserService(repo domain.UserRepository) domain.UserService {
-	return &userService{repo: repo}
+// NewUserService creates a new UserService.
+// Every mutation is recorded in audit. New users with an unverified email are
+// sent a verification link through verifier, if set. Soft-deleted users become
+// eligible for Purge once retention has passed. List cursors are signed with
+// cursorSecret.
+func NewUserService(repo domain.UserRepository, authz domain.Authorizer, audit domain.AuditRepository, verifier domain.EmailVerifier, retention time.Duration, cursorSecret []byte) domain.UserService {
+	return &userService{repo: repo, authz: authz, audit: audit, verifier: verifier, retention: retention, cursors: cursorSecret}
 }
 
 func (s *userService) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
@@ -22,14 +34,40 @@ func (s *userService) Create(ctx context.Context, user *domain.User) (*domain.Us
         return nil, domain.NewInternal("user is nil")
     }
 
+	// Self-registration always yields a member; only admins hand out other roles.
+	if user.Role == "" {
+		user.Role = domain.RoleMember
+	}
+	if user.Role != domain.RoleMember {
+		if err := s.authz.Authorize(ctx, domain.PermUsersAssignRole, 0); err != nil {
+			return nil, err
+		}
+	}
+
     if existing, err := s.repo.GetByEmail(ctx, user.Email); err == nil && existing != nil {
         return nil, domain.NewConflict("email already exists")
     }
 
-    return s.repo.Create(ctx, user)
+	created, err := s.repo.Create(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditUserCreated, created.ID, userChanges(nil, created))
+
+	// The account exists either way; the user can ask for another link.
+	if s.verifier != nil && !created.EmailVerified() {
+		if err := s.verifier.SendVerification(ctx, created); err != nil {
+			log.Printf("user: failed to send verification email to user %d: %v", created.ID, err)
+		}
+	}
+	return created, nil
 }
 
 func (s *userService) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersRead, id); err != nil {
+		return nil, err
+	}
 	return s.repo.GetByID(ctx, id)
 }
 
@@ -38,13 +76,117 @@ func (s *userService) GetByEmail(ctx context.Context, email string) (*domain.Use
 }
 
 func (s *userService) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
-    return s.repo.Update(ctx, user)
+	if err := s.authz.Authorize(ctx, domain.PermUsersUpdate, user.ID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != current.Role {
+		if err := s.authz.Authorize(ctx, domain.PermUsersAssignRole, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditUserUpdated, updated.ID, userChanges(current, updated))
+	return updated, nil
+}
+
+func (s *userService) Delete(ctx context.Context, id uint, expectedVersion uint) error {
+	if err := s.authz.Authorize(ctx, domain.PermUsersDelete, id); err != nil {
+		return err
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok && p.UserID == id {
+		if err := forbidWhileImpersonating(ctx, "deleting the impersonated user"); err != nil {
+			return err
+		}
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id, expectedVersion); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditUserDeleted, id, userChanges(current, nil))
+	return nil
+}
+
+func (s *userService) List(ctx context.Context, query domain.UserQuery) ([]*domain.User, int64, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersList, 0); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, query)
 }
 
-func (s *userService) Delete(ctx context.Context, id uint) error {
-	return s.repo.Delete(ctx, id)
+func (s *userService) ListByCursor(ctx context.Context, query domain.UserQuery, cursor string) (*domain.UserPage, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersList, 0); err != nil {
+		return nil, err
+	}
+	if query.Limit < 1 {
+		query.Limit = 10
+	}
+	if query.Limit > maxCursorLimit {
+		query.Limit = maxCursorLimit
+	}
+	query.Page = 0
+
+	fingerprint := cursorQuery(ctx, query)
+	at, err := s.decodeCursor(cursor, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	users, more, err := s.repo.ListByCursor(ctx, query, at)
+	if err != nil {
+		return nil, err
+	}
+
+	next, prev := adjacentCursors(at, users, more, query.Ordering())
+	page := &domain.UserPage{Users: users, Limit: query.Limit}
+	if next != nil {
+		page.Next = s.encodeCursor(*next, fingerprint)
+	}
+	if prev != nil {
+		page.Prev = s.encodeCursor(*prev, fingerprint)
+	}
+	return page, nil
 }
 
-func (s *userService) List(ctx context.Context, page, limit int, q string) ([]*domain.User, int64, error) {
-	return s.repo.List(ctx, page, limit, q)
+func (s *userService) Restore(ctx context.Context, id uint) (*domain.User, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersRestore, id); err != nil {
+		return nil, err
+	}
+	restored, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, s.audit, domain.AuditUserRestored, id, nil)
+	return restored, nil
+}
+
+func (s *userService) Purge(ctx context.Context) (int64, error) {
+	if err := s.authz.Authorize(ctx, domain.PermUsersPurge, 0); err != nil {
+		return 0, err
+	}
+	purged, err := s.repo.Purge(ctx, time.Now().UTC().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		recordAudit(ctx, s.audit, domain.AuditUsersPurged, 0, map[string]domain.FieldChange{
+			"count": {To: strconv.FormatInt(purged, 10)},
+		})
+	}
+	return purged, nil
 }
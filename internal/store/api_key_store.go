@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyStore implements domain.APIKeyRepository
+type apiKeyStore struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyStore creates a new APIKeyRepository backed by GORM
+func NewAPIKeyStore(db *gorm.DB) domain.APIKeyRepository {
+	return &apiKeyStore{db: db}
+}
+
+func (s *apiKeyStore) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	if err := s.db.WithContext(ctx).Create(key).Error; err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *apiKeyStore) GetByID(ctx context.Context, id uint) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := s.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("API key not found")
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (s *apiKeyStore) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := s.db.WithContext(ctx).Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("API key not found")
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (s *apiKeyStore) ListForUser(ctx context.Context, userID uint) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *apiKeyStore) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (s *apiKeyStore) RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (s *apiKeyStore) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
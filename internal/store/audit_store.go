@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// auditStore implements domain.AuditRepository
+type auditStore struct {
+	db *gorm.DB
+}
+
+// NewAuditStore creates a new append-only AuditRepository backed by GORM
+func NewAuditStore(db *gorm.DB) domain.AuditRepository {
+	return &auditStore{db: db}
+}
+
+func (s *auditStore) Append(ctx context.Context, event *domain.AuditEvent) error {
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		event.TenantID = tenantID
+	}
+	return s.db.WithContext(ctx).Create(event).Error
+}
+
+func (s *auditStore) List(ctx context.Context, f domain.AuditFilter) ([]*domain.AuditEvent, int64, error) {
+	var events []*domain.AuditEvent
+	var total int64
+
+	query := s.db.WithContext(ctx).Model(&domain.AuditEvent{})
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if f.ActorID != 0 {
+		query = query.Where("actor_id = ?", f.ActorID)
+	}
+	if f.ImpersonatorID != 0 {
+		query = query.Where("impersonator_id = ?", f.ImpersonatorID)
+	}
+	if f.TargetID != 0 {
+		query = query.Where("target_id = ?", f.TargetID)
+	}
+	if f.Action != "" {
+		query = query.Where("action = ?", f.Action)
+	}
+	if !f.From.IsZero() {
+		query = query.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		query = query.Where("created_at < ?", f.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (f.Page - 1) * f.Limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(f.Limit).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// emailChangeStore implements domain.EmailChangeRepository
+type emailChangeStore struct {
+	db *gorm.DB
+}
+
+// NewEmailChangeStore creates a new EmailChangeRepository backed by GORM
+func NewEmailChangeStore(db *gorm.DB) domain.EmailChangeRepository {
+	return &emailChangeStore{db: db}
+}
+
+func (s *emailChangeStore) Create(ctx context.Context, change *domain.EmailChange) (*domain.EmailChange, error) {
+	if err := s.db.WithContext(ctx).Create(change).Error; err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (s *emailChangeStore) GetByHash(ctx context.Context, hash string) (*domain.EmailChange, error) {
+	var change domain.EmailChange
+	if err := s.db.WithContext(ctx).Where("token_hash = ?", hash).First(&change).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("email change not found")
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (s *emailChangeStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+	res := s.db.WithContext(ctx).Model(&domain.EmailChange{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewConflict("email change already used")
+	}
+	return nil
+}
+
+func (s *emailChangeStore) ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.EmailChange{}).
+		Where("user_id = ? AND consumed_at IS NULL", userID).
+		Update("consumed_at", at).Error
+}
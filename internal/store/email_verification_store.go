@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// emailVerificationStore implements domain.EmailVerificationRepository
+type emailVerificationStore struct {
+	db *gorm.DB
+}
+
+// NewEmailVerificationStore creates a new EmailVerificationRepository backed by GORM
+func NewEmailVerificationStore(db *gorm.DB) domain.EmailVerificationRepository {
+	return &emailVerificationStore{db: db}
+}
+
+func (s *emailVerificationStore) Create(ctx context.Context, v *domain.EmailVerification) (*domain.EmailVerification, error) {
+	if err := s.db.WithContext(ctx).Create(v).Error; err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *emailVerificationStore) GetByHash(ctx context.Context, hash string) (*domain.EmailVerification, error) {
+	var v domain.EmailVerification
+	if err := s.db.WithContext(ctx).Where("token_hash = ?", hash).First(&v).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("email verification not found")
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (s *emailVerificationStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+	res := s.db.WithContext(ctx).Model(&domain.EmailVerification{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewConflict("email verification already used")
+	}
+	return nil
+}
+
+func (s *emailVerificationStore) ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.EmailVerification{}).
+		Where("user_id = ? AND consumed_at IS NULL", userID).
+		Update("consumed_at", at).Error
+}
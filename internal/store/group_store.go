@@ -0,0 +1,266 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// groupStore implements domain.GroupRepository
+type groupStore struct {
+	db *gorm.DB
+}
+
+// NewGroupStore creates a new GroupRepository backed by GORM
+func NewGroupStore(db *gorm.DB) domain.GroupRepository {
+	return &groupStore{db: db}
+}
+
+// scoped restricts queries to the tenant carried by ctx, if any.
+func (s *groupStore) scoped(ctx context.Context) *gorm.DB {
+	db := s.db.WithContext(ctx)
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		db = db.Where("tenant_id = ?", tenantID)
+	}
+	return db
+}
+
+func (s *groupStore) Create(ctx context.Context, group *domain.Group) (*domain.Group, error) {
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		group.TenantID = tenantID
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+		return replaceMembers(tx, group.ID, group.MemberIDs, group.SubgroupIDs)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, domain.NewConflict("group name already exists")
+		}
+		return nil, err
+	}
+	return s.GetByID(ctx, group.ID)
+}
+
+func (s *groupStore) GetByID(ctx context.Context, id uint) (*domain.Group, error) {
+	var group domain.Group
+	if err := s.scoped(ctx).First(&group, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("group not found")
+		}
+		return nil, err
+	}
+	if err := s.loadMembers(ctx, []*domain.Group{&group}); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (s *groupStore) List(ctx context.Context, page, limit int) ([]*domain.Group, int64, error) {
+	var groups []*domain.Group
+	var total int64
+
+	query := s.scoped(ctx).Model(&domain.Group{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id").Offset(offset).Limit(limit).Find(&groups).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := s.loadMembers(ctx, groups); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (s *groupStore) Update(ctx context.Context, group *domain.Group) (*domain.Group, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&domain.Group{}).Where("id = ?", group.ID)
+		if tenantID, ok := domain.TenantFromContext(ctx); ok {
+			query = query.Where("tenant_id = ?", tenantID)
+		}
+		res := query.Updates(map[string]any{"name": group.Name, "role": group.Role})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// Nothing changed, or nothing there: tell them apart.
+			var count int64
+			if err := tx.Model(&domain.Group{}).Where("id = ?", group.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return domain.NewNotFound("group not found")
+			}
+		}
+		return replaceMembers(tx, group.ID, group.MemberIDs, group.SubgroupIDs)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, domain.NewConflict("group name already exists")
+		}
+		return nil, err
+	}
+	return s.GetByID(ctx, group.ID)
+}
+
+func (s *groupStore) Delete(ctx context.Context, id uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("id = ?", id)
+		if tenantID, ok := domain.TenantFromContext(ctx); ok {
+			query = query.Where("tenant_id = ?", tenantID)
+		}
+		res := query.Delete(&domain.Group{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.NewNotFound("group not found")
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&domain.GroupMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("group_id = ? OR subgroup_id = ?", id, id).Delete(&domain.GroupSubgroup{}).Error
+	})
+}
+
+func (s *groupStore) AddMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*domain.Group, error) {
+	return s.modifyMembers(ctx, id, func(tx *gorm.DB) error {
+		return insertMembers(tx.Clauses(clause.OnConflict{DoNothing: true}), id, userIDs, subgroupIDs)
+	})
+}
+
+func (s *groupStore) RemoveMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*domain.Group, error) {
+	return s.modifyMembers(ctx, id, func(tx *gorm.DB) error {
+		if len(userIDs) > 0 {
+			if err := tx.Where("group_id = ? AND user_id IN ?", id, userIDs).Delete(&domain.GroupMember{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(subgroupIDs) > 0 {
+			return tx.Where("group_id = ? AND subgroup_id IN ?", id, subgroupIDs).Delete(&domain.GroupSubgroup{}).Error
+		}
+		return nil
+	})
+}
+
+// modifyMembers runs modify in a transaction after checking that the group
+// exists in the caller's tenant, and bumps its UpdatedAt.
+func (s *groupStore) modifyMembers(ctx context.Context, id uint, modify func(tx *gorm.DB) error) (*domain.Group, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&domain.Group{}).Where("id = ?", id)
+		if tenantID, ok := domain.TenantFromContext(ctx); ok {
+			query = query.Where("tenant_id = ?", tenantID)
+		}
+		res := query.Update("updated_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.NewNotFound("group not found")
+		}
+		return modify(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *groupStore) ListByMember(ctx context.Context, userID uint) ([]*domain.Group, error) {
+	members := s.db.Model(&domain.GroupMember{}).Select("group_id").Where("user_id = ?", userID)
+	return s.find(ctx, s.scoped(ctx).Where("id IN (?)", members))
+}
+
+func (s *groupStore) ListBySubgroup(ctx context.Context, subgroupIDs []uint) ([]*domain.Group, error) {
+	if len(subgroupIDs) == 0 {
+		return []*domain.Group{}, nil
+	}
+	parents := s.db.Model(&domain.GroupSubgroup{}).Select("group_id").Where("subgroup_id IN ?", subgroupIDs)
+	return s.find(ctx, s.scoped(ctx).Where("id IN (?)", parents))
+}
+
+func (s *groupStore) find(ctx context.Context, query *gorm.DB) ([]*domain.Group, error) {
+	var groups []*domain.Group
+	if err := query.Order("id").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	if err := s.loadMembers(ctx, groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// loadMembers fills in MemberIDs and SubgroupIDs with one query each for
+// all groups.
+func (s *groupStore) loadMembers(ctx context.Context, groups []*domain.Group) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	byID := make(map[uint]*domain.Group, len(groups))
+	ids := make([]uint, 0, len(groups))
+	for _, g := range groups {
+		g.MemberIDs = []uint{}
+		g.SubgroupIDs = []uint{}
+		byID[g.ID] = g
+		ids = append(ids, g.ID)
+	}
+
+	var members []domain.GroupMember
+	if err := s.db.WithContext(ctx).Where("group_id IN ?", ids).Order("group_id, user_id").Find(&members).Error; err != nil {
+		return err
+	}
+	for _, m := range members {
+		g := byID[m.GroupID]
+		g.MemberIDs = append(g.MemberIDs, m.UserID)
+	}
+
+	var subgroups []domain.GroupSubgroup
+	if err := s.db.WithContext(ctx).Where("group_id IN ?", ids).Order("group_id, subgroup_id").Find(&subgroups).Error; err != nil {
+		return err
+	}
+	for _, sg := range subgroups {
+		g := byID[sg.GroupID]
+		g.SubgroupIDs = append(g.SubgroupIDs, sg.SubgroupID)
+	}
+	return nil
+}
+
+func replaceMembers(tx *gorm.DB, groupID uint, userIDs, subgroupIDs []uint) error {
+	if err := tx.Where("group_id = ?", groupID).Delete(&domain.GroupMember{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("group_id = ?", groupID).Delete(&domain.GroupSubgroup{}).Error; err != nil {
+		return err
+	}
+	return insertMembers(tx, groupID, userIDs, subgroupIDs)
+}
+
+func insertMembers(tx *gorm.DB, groupID uint, userIDs, subgroupIDs []uint) error {
+	if len(userIDs) > 0 {
+		rows := make([]domain.GroupMember, 0, len(userIDs))
+		for _, id := range userIDs {
+			rows = append(rows, domain.GroupMember{GroupID: groupID, UserID: id})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	if len(subgroupIDs) > 0 {
+		rows := make([]domain.GroupSubgroup, 0, len(subgroupIDs))
+		for _, id := range subgroupIDs {
+			rows = append(rows, domain.GroupSubgroup{GroupID: groupID, SubgroupID: id})
+		}
+		return tx.Create(&rows).Error
+	}
+	return nil
+}
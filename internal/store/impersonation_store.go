@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// impersonationStore implements domain.ImpersonationRepository
+type impersonationStore struct {
+	db *gorm.DB
+}
+
+// NewImpersonationStore creates a new ImpersonationRepository backed by GORM
+func NewImpersonationStore(db *gorm.DB) domain.ImpersonationRepository {
+	return &impersonationStore{db: db}
+}
+
+// scoped restricts queries to the tenant carried by ctx, if any.
+func (s *impersonationStore) scoped(ctx context.Context) *gorm.DB {
+	db := s.db.WithContext(ctx).Model(&domain.Impersonation{})
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		db = db.Where("tenant_id = ?", tenantID)
+	}
+	return db
+}
+
+func (s *impersonationStore) Create(ctx context.Context, imp *domain.Impersonation) (*domain.Impersonation, error) {
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		imp.TenantID = tenantID
+	}
+	if err := s.db.WithContext(ctx).Create(imp).Error; err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+func (s *impersonationStore) GetByID(ctx context.Context, id uint) (*domain.Impersonation, error) {
+	var imp domain.Impersonation
+	if err := s.scoped(ctx).Where("id = ?", id).First(&imp).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("impersonation not found")
+		}
+		return nil, err
+	}
+	return &imp, nil
+}
+
+func (s *impersonationStore) MarkEnded(ctx context.Context, id uint, at time.Time) error {
+	res := s.scoped(ctx).Where("id = ? AND ended_at IS NULL", id).Update("ended_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if _, err := s.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return domain.NewConflict("impersonation has already ended")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// invitationStore implements domain.InvitationRepository
+type invitationStore struct {
+	db *gorm.DB
+}
+
+// NewInvitationStore creates a new InvitationRepository backed by GORM
+func NewInvitationStore(db *gorm.DB) domain.InvitationRepository {
+	return &invitationStore{db: db}
+}
+
+// scoped restricts queries to the tenant carried by ctx, if any.
+func (s *invitationStore) scoped(ctx context.Context) *gorm.DB {
+	db := s.db.WithContext(ctx).Model(&domain.Invitation{})
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		db = db.Where("tenant_id = ?", tenantID)
+	}
+	return db
+}
+
+func (s *invitationStore) Create(ctx context.Context, inv *domain.Invitation) (*domain.Invitation, error) {
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		inv.TenantID = tenantID
+	}
+	if err := s.db.WithContext(ctx).Create(inv).Error; err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+func (s *invitationStore) GetByID(ctx context.Context, id uint) (*domain.Invitation, error) {
+	return s.first(s.scoped(ctx).Where("id = ?", id))
+}
+
+func (s *invitationStore) GetByHash(ctx context.Context, hash string) (*domain.Invitation, error) {
+	return s.first(s.db.WithContext(ctx).Where("token_hash = ?", hash))
+}
+
+func (s *invitationStore) GetPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error) {
+	return s.first(s.scoped(ctx).Where("email = ? AND status = ?", email, domain.InvitationPending).Order("id DESC"))
+}
+
+func (s *invitationStore) List(ctx context.Context, page, limit int) ([]*domain.Invitation, int64, error) {
+	var invitations []*domain.Invitation
+	var total int64
+
+	query := s.scoped(ctx)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&invitations).Error; err != nil {
+		return nil, 0, err
+	}
+	return invitations, total, nil
+}
+
+func (s *invitationStore) Reissue(ctx context.Context, id uint, tokenHash string, expiresAt time.Time) (*domain.Invitation, error) {
+	res := s.scoped(ctx).
+		Where("id = ? AND status = ?", id, domain.InvitationPending).
+		Updates(map[string]any{"token_hash": tokenHash, "expires_at": expiresAt})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		if _, err := s.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.NewConflict("invitation is no longer pending")
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *invitationStore) MarkAccepted(ctx context.Context, id, userID uint, at time.Time) error {
+	return s.end(ctx, id, map[string]any{"status": domain.InvitationAccepted, "user_id": userID, "accepted_at": at})
+}
+
+func (s *invitationStore) MarkRevoked(ctx context.Context, id uint, at time.Time) error {
+	return s.end(ctx, id, map[string]any{"status": domain.InvitationRevoked, "revoked_at": at})
+}
+
+func (s *invitationStore) end(ctx context.Context, id uint, changes map[string]any) error {
+	res := s.scoped(ctx).Where("id = ? AND status = ?", id, domain.InvitationPending).Updates(changes)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewConflict("invitation is no longer pending")
+	}
+	return nil
+}
+
+func (s *invitationStore) first(query *gorm.DB) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	if err := query.First(&inv).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("invitation not found")
+		}
+		return nil, err
+	}
+	return &inv, nil
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// apiKeyStore is an in-memory implementation of domain.APIKeyRepository.
+type apiKeyStore struct {
+    mu     sync.Mutex
+    nextID uint
+    keys   map[uint]domain.APIKey
+}
+
+func NewAPIKeyStore() domain.APIKeyRepository {
+    return &apiKeyStore{
+        nextID: 1,
+        keys:   make(map[uint]domain.APIKey),
+    }
+}
+
+func (s *apiKeyStore) Create(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, existing := range s.keys {
+        if existing.KeyHash == key.KeyHash {
+            return nil, domain.NewConflict("API key already exists")
+        }
+    }
+
+    k := copyAPIKey(*key)
+    k.ID = s.nextID
+    s.nextID++
+    if k.CreatedAt.IsZero() {
+        k.CreatedAt = time.Now().UTC()
+    }
+    s.keys[k.ID] = k
+    out := copyAPIKey(k)
+    return &out, nil
+}
+
+func (s *apiKeyStore) GetByID(ctx context.Context, id uint) (*domain.APIKey, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    k, ok := s.keys[id]
+    if !ok {
+        return nil, domain.NewNotFound("API key not found")
+    }
+    out := copyAPIKey(k)
+    return &out, nil
+}
+
+func (s *apiKeyStore) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, k := range s.keys {
+        if k.KeyHash == hash {
+            out := copyAPIKey(k)
+            return &out, nil
+        }
+    }
+    return nil, domain.NewNotFound("API key not found")
+}
+
+func (s *apiKeyStore) ListForUser(ctx context.Context, userID uint) ([]domain.APIKey, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    keys := make([]domain.APIKey, 0)
+    for _, k := range s.keys {
+        if k.UserID == userID {
+            keys = append(keys, copyAPIKey(k))
+        }
+    }
+    sort.Slice(keys, func(i, j int) bool { return keys[i].ID > keys[j].ID })
+    return keys, nil
+}
+
+func (s *apiKeyStore) Revoke(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    k, ok := s.keys[id]
+    if !ok {
+        return domain.NewNotFound("API key not found")
+    }
+    if k.RevokedAt == nil {
+        k.RevokedAt = &at
+        s.keys[id] = k
+    }
+    return nil
+}
+
+func (s *apiKeyStore) RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, k := range s.keys {
+        if k.UserID == userID && k.RevokedAt == nil {
+            k.RevokedAt = &at
+            s.keys[id] = k
+        }
+    }
+    return nil
+}
+
+func (s *apiKeyStore) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    k, ok := s.keys[id]
+    if !ok {
+        return domain.NewNotFound("API key not found")
+    }
+    k.LastUsedAt = &at
+    s.keys[id] = k
+    return nil
+}
+
+// copyAPIKey returns k with its own copy of the scopes slice.
+func copyAPIKey(k domain.APIKey) domain.APIKey {
+    k.Scopes = append([]domain.Scope(nil), k.Scopes...)
+    return k
+}
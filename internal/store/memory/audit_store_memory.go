@@ -0,0 +1,86 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// auditStore is an in-memory, append-only implementation of domain.AuditRepository.
+type auditStore struct {
+    mu     sync.RWMutex
+    events []domain.AuditEvent
+}
+
+func NewAuditStore() domain.AuditRepository {
+    return &auditStore{}
+}
+
+func (s *auditStore) Append(ctx context.Context, event *domain.AuditEvent) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    e := *event
+    e.ID = uint(len(s.events) + 1)
+    if tenantID, ok := domain.TenantFromContext(ctx); ok {
+        e.TenantID = tenantID
+    }
+    if e.CreatedAt.IsZero() {
+        e.CreatedAt = time.Now().UTC()
+    }
+    // Copy the map so later changes by the caller cannot rewrite history.
+    if e.Changes != nil {
+        changes := make(map[string]domain.FieldChange, len(e.Changes))
+        for k, v := range e.Changes {
+            changes[k] = v
+        }
+        e.Changes = changes
+    }
+    s.events = append(s.events, e)
+    *event = e
+    return nil
+}
+
+func (s *auditStore) List(ctx context.Context, f domain.AuditFilter) ([]*domain.AuditEvent, int64, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    tenantID, scoped := domain.TenantFromContext(ctx)
+
+    // Newest first: walk the log backwards.
+    filtered := make([]domain.AuditEvent, 0)
+    for i := len(s.events) - 1; i >= 0; i-- {
+        e := s.events[i]
+        switch {
+        case scoped && e.TenantID != tenantID:
+        case f.ActorID != 0 && e.ActorID != f.ActorID:
+        case f.ImpersonatorID != 0 && e.ImpersonatorID != f.ImpersonatorID:
+        case f.TargetID != 0 && e.TargetID != f.TargetID:
+        case f.Action != "" && e.Action != f.Action:
+        case !f.From.IsZero() && e.CreatedAt.Before(f.From):
+        case !f.To.IsZero() && !e.CreatedAt.Before(f.To):
+        default:
+            filtered = append(filtered, e)
+        }
+    }
+
+    total := int64(len(filtered))
+
+    start := (f.Page - 1) * f.Limit
+    if start >= len(filtered) {
+        return []*domain.AuditEvent{}, total, nil
+    }
+    end := start + f.Limit
+    if end > len(filtered) {
+        end = len(filtered)
+    }
+
+    out := make([]*domain.AuditEvent, 0, end-start)
+    for i := start; i < end; i++ {
+        e := filtered[i]
+        out = append(out, &e)
+    }
+    return out, total, nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// emailChangeStore is an in-memory implementation of domain.EmailChangeRepository.
+type emailChangeStore struct {
+    mu      sync.Mutex
+    nextID  uint
+    changes map[uint]domain.EmailChange
+}
+
+func NewEmailChangeStore() domain.EmailChangeRepository {
+    return &emailChangeStore{
+        nextID:  1,
+        changes: make(map[uint]domain.EmailChange),
+    }
+}
+
+func (s *emailChangeStore) Create(ctx context.Context, change *domain.EmailChange) (*domain.EmailChange, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    c := *change
+    c.ID = s.nextID
+    s.nextID++
+    if c.CreatedAt.IsZero() {
+        c.CreatedAt = time.Now().UTC()
+    }
+    s.changes[c.ID] = c
+    return &c, nil
+}
+
+func (s *emailChangeStore) GetByHash(ctx context.Context, hash string) (*domain.EmailChange, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, c := range s.changes {
+        if c.TokenHash == hash {
+            cc := c
+            return &cc, nil
+        }
+    }
+    return nil, domain.NewNotFound("email change not found")
+}
+
+func (s *emailChangeStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    c, ok := s.changes[id]
+    if !ok {
+        return domain.NewNotFound("email change not found")
+    }
+    if c.ConsumedAt != nil {
+        return domain.NewConflict("email change already used")
+    }
+    c.ConsumedAt = &at
+    s.changes[id] = c
+    return nil
+}
+
+func (s *emailChangeStore) ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, c := range s.changes {
+        if c.UserID == userID && c.ConsumedAt == nil {
+            c.ConsumedAt = &at
+            s.changes[id] = c
+        }
+    }
+    return nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// emailVerificationStore is an in-memory implementation of domain.EmailVerificationRepository.
+type emailVerificationStore struct {
+    mu     sync.Mutex
+    nextID uint
+    tokens map[uint]domain.EmailVerification
+}
+
+func NewEmailVerificationStore() domain.EmailVerificationRepository {
+    return &emailVerificationStore{
+        nextID: 1,
+        tokens: make(map[uint]domain.EmailVerification),
+    }
+}
+
+func (s *emailVerificationStore) Create(ctx context.Context, v *domain.EmailVerification) (*domain.EmailVerification, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t := *v
+    t.ID = s.nextID
+    s.nextID++
+    if t.CreatedAt.IsZero() {
+        t.CreatedAt = time.Now().UTC()
+    }
+    s.tokens[t.ID] = t
+    return &t, nil
+}
+
+func (s *emailVerificationStore) GetByHash(ctx context.Context, hash string) (*domain.EmailVerification, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, t := range s.tokens {
+        if t.TokenHash == hash {
+            tt := t
+            return &tt, nil
+        }
+    }
+    return nil, domain.NewNotFound("email verification not found")
+}
+
+func (s *emailVerificationStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, ok := s.tokens[id]
+    if !ok {
+        return domain.NewNotFound("email verification not found")
+    }
+    if t.ConsumedAt != nil {
+        return domain.NewConflict("email verification already used")
+    }
+    t.ConsumedAt = &at
+    s.tokens[id] = t
+    return nil
+}
+
+func (s *emailVerificationStore) ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, t := range s.tokens {
+        if t.UserID == userID && t.ConsumedAt == nil {
+            t.ConsumedAt = &at
+            s.tokens[id] = t
+        }
+    }
+    return nil
+}
@@ -0,0 +1,247 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// groupStore is an in-memory implementation of domain.GroupRepository.
+type groupStore struct {
+    mu     sync.RWMutex
+    nextID uint
+    groups map[uint]domain.Group
+}
+
+func NewGroupStore() domain.GroupRepository {
+    return &groupStore{
+        nextID: 1,
+        groups: make(map[uint]domain.Group),
+    }
+}
+
+func (s *groupStore) Create(ctx context.Context, group *domain.Group) (*domain.Group, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    g := copyGroup(*group)
+    if tenantID, ok := domain.TenantFromContext(ctx); ok {
+        g.TenantID = tenantID
+    }
+    if g.TenantID == 0 {
+        g.TenantID = domain.DefaultTenantID
+    }
+    if s.nameTaken(g.TenantID, g.Name, 0) {
+        return nil, domain.NewConflict("group name already exists")
+    }
+
+    g.ID = s.nextID
+    s.nextID++
+    now := time.Now().UTC()
+    g.CreatedAt, g.UpdatedAt = now, now
+    s.groups[g.ID] = g
+    out := copyGroup(g)
+    return &out, nil
+}
+
+func (s *groupStore) GetByID(ctx context.Context, id uint) (*domain.Group, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    g, ok := s.groups[id]
+    if !ok || !groupInTenant(ctx, g) {
+        return nil, domain.NewNotFound("group not found")
+    }
+    out := copyGroup(g)
+    return &out, nil
+}
+
+func (s *groupStore) List(ctx context.Context, page, limit int) ([]*domain.Group, int64, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 {
+        limit = 10
+    }
+
+    all := make([]*domain.Group, 0, len(s.groups))
+    for _, g := range s.groups {
+        if groupInTenant(ctx, g) {
+            out := copyGroup(g)
+            all = append(all, &out)
+        }
+    }
+    sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+    total := int64(len(all))
+    start := (page - 1) * limit
+    if start >= len(all) {
+        return []*domain.Group{}, total, nil
+    }
+    end := start + limit
+    if end > len(all) {
+        end = len(all)
+    }
+    return all[start:end], total, nil
+}
+
+func (s *groupStore) Update(ctx context.Context, group *domain.Group) (*domain.Group, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    existing, ok := s.groups[group.ID]
+    if !ok || !groupInTenant(ctx, existing) {
+        return nil, domain.NewNotFound("group not found")
+    }
+    if s.nameTaken(existing.TenantID, group.Name, group.ID) {
+        return nil, domain.NewConflict("group name already exists")
+    }
+
+    existing.Name = group.Name
+    existing.Role = group.Role
+    existing.MemberIDs = sortedIDs(group.MemberIDs)
+    existing.SubgroupIDs = sortedIDs(group.SubgroupIDs)
+    existing.UpdatedAt = time.Now().UTC()
+    s.groups[existing.ID] = existing
+    out := copyGroup(existing)
+    return &out, nil
+}
+
+func (s *groupStore) Delete(ctx context.Context, id uint) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    g, ok := s.groups[id]
+    if !ok || !groupInTenant(ctx, g) {
+        return domain.NewNotFound("group not found")
+    }
+    delete(s.groups, id)
+    for _, parent := range s.groups {
+        if containsID(parent.SubgroupIDs, id) {
+            parent.SubgroupIDs = withoutIDs(parent.SubgroupIDs, []uint{id})
+            s.groups[parent.ID] = parent
+        }
+    }
+    return nil
+}
+
+func (s *groupStore) AddMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*domain.Group, error) {
+    return s.modifyMembers(ctx, id, func(g *domain.Group) {
+        g.MemberIDs = sortedIDs(unionIDs(g.MemberIDs, userIDs))
+        g.SubgroupIDs = sortedIDs(unionIDs(g.SubgroupIDs, subgroupIDs))
+    })
+}
+
+func (s *groupStore) RemoveMembers(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*domain.Group, error) {
+    return s.modifyMembers(ctx, id, func(g *domain.Group) {
+        g.MemberIDs = withoutIDs(g.MemberIDs, userIDs)
+        g.SubgroupIDs = withoutIDs(g.SubgroupIDs, subgroupIDs)
+    })
+}
+
+func (s *groupStore) modifyMembers(ctx context.Context, id uint, modify func(*domain.Group)) (*domain.Group, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    g, ok := s.groups[id]
+    if !ok || !groupInTenant(ctx, g) {
+        return nil, domain.NewNotFound("group not found")
+    }
+    modify(&g)
+    g.UpdatedAt = time.Now().UTC()
+    s.groups[id] = g
+    out := copyGroup(g)
+    return &out, nil
+}
+
+func (s *groupStore) ListByMember(ctx context.Context, userID uint) ([]*domain.Group, error) {
+    return s.filter(ctx, func(g domain.Group) bool { return containsID(g.MemberIDs, userID) }), nil
+}
+
+func (s *groupStore) ListBySubgroup(ctx context.Context, subgroupIDs []uint) ([]*domain.Group, error) {
+    return s.filter(ctx, func(g domain.Group) bool {
+        for _, id := range subgroupIDs {
+            if containsID(g.SubgroupIDs, id) {
+                return true
+            }
+        }
+        return false
+    }), nil
+}
+
+func (s *groupStore) filter(ctx context.Context, match func(domain.Group) bool) []*domain.Group {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    out := []*domain.Group{}
+    for _, g := range s.groups {
+        if groupInTenant(ctx, g) && match(g) {
+            c := copyGroup(g)
+            out = append(out, &c)
+        }
+    }
+    sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+    return out
+}
+
+func (s *groupStore) nameTaken(tenantID uint, name string, except uint) bool {
+    for id, g := range s.groups {
+        if id != except && g.TenantID == tenantID && strings.EqualFold(g.Name, name) {
+            return true
+        }
+    }
+    return false
+}
+
+func groupInTenant(ctx context.Context, g domain.Group) bool {
+    tenantID, ok := domain.TenantFromContext(ctx)
+    return !ok || g.TenantID == tenantID
+}
+
+func copyGroup(g domain.Group) domain.Group {
+    g.MemberIDs = sortedIDs(g.MemberIDs)
+    g.SubgroupIDs = sortedIDs(g.SubgroupIDs)
+    return g
+}
+
+func sortedIDs(ids []uint) []uint {
+    out := append([]uint{}, ids...)
+    sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+    return out
+}
+
+func containsID(ids []uint, id uint) bool {
+    for _, candidate := range ids {
+        if candidate == id {
+            return true
+        }
+    }
+    return false
+}
+
+func unionIDs(ids, more []uint) []uint {
+    out := append([]uint{}, ids...)
+    for _, id := range more {
+        if !containsID(out, id) {
+            out = append(out, id)
+        }
+    }
+    return out
+}
+
+func withoutIDs(ids, remove []uint) []uint {
+    out := make([]uint, 0, len(ids))
+    for _, id := range ids {
+        if !containsID(remove, id) {
+            out = append(out, id)
+        }
+    }
+    return out
+}
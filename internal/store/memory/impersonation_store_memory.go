@@ -0,0 +1,74 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// impersonationStore is an in-memory implementation of domain.ImpersonationRepository.
+type impersonationStore struct {
+    mu             sync.RWMutex
+    nextID         uint
+    impersonations map[uint]domain.Impersonation
+}
+
+func NewImpersonationStore() domain.ImpersonationRepository {
+    return &impersonationStore{
+        nextID:         1,
+        impersonations: make(map[uint]domain.Impersonation),
+    }
+}
+
+func (s *impersonationStore) Create(ctx context.Context, imp *domain.Impersonation) (*domain.Impersonation, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    i := *imp
+    if tenantID, ok := domain.TenantFromContext(ctx); ok {
+        i.TenantID = tenantID
+    }
+    if i.TenantID == 0 {
+        i.TenantID = domain.DefaultTenantID
+    }
+    i.ID = s.nextID
+    s.nextID++
+    i.CreatedAt = time.Now().UTC()
+    s.impersonations[i.ID] = i
+    out := i
+    return &out, nil
+}
+
+func (s *impersonationStore) GetByID(ctx context.Context, id uint) (*domain.Impersonation, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    i, ok := s.impersonations[id]
+    if !ok || !impersonationInTenant(ctx, i) {
+        return nil, domain.NewNotFound("impersonation not found")
+    }
+    return &i, nil
+}
+
+func (s *impersonationStore) MarkEnded(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    i, ok := s.impersonations[id]
+    if !ok || !impersonationInTenant(ctx, i) {
+        return domain.NewNotFound("impersonation not found")
+    }
+    if i.EndedAt != nil {
+        return domain.NewConflict("impersonation has already ended")
+    }
+    i.EndedAt = &at
+    s.impersonations[id] = i
+    return nil
+}
+
+func impersonationInTenant(ctx context.Context, i domain.Impersonation) bool {
+    tenantID, ok := domain.TenantFromContext(ctx)
+    return !ok || i.TenantID == tenantID
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// invitationStore is an in-memory implementation of domain.InvitationRepository.
+type invitationStore struct {
+    mu          sync.RWMutex
+    nextID      uint
+    invitations map[uint]domain.Invitation
+}
+
+func NewInvitationStore() domain.InvitationRepository {
+    return &invitationStore{
+        nextID:      1,
+        invitations: make(map[uint]domain.Invitation),
+    }
+}
+
+func (s *invitationStore) Create(ctx context.Context, inv *domain.Invitation) (*domain.Invitation, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    i := *inv
+    if tenantID, ok := domain.TenantFromContext(ctx); ok {
+        i.TenantID = tenantID
+    }
+    if i.TenantID == 0 {
+        i.TenantID = domain.DefaultTenantID
+    }
+    i.ID = s.nextID
+    s.nextID++
+    now := time.Now().UTC()
+    i.CreatedAt, i.UpdatedAt = now, now
+    s.invitations[i.ID] = i
+    out := i
+    return &out, nil
+}
+
+func (s *invitationStore) GetByID(ctx context.Context, id uint) (*domain.Invitation, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    i, ok := s.invitations[id]
+    if !ok || !invitationInTenant(ctx, i) {
+        return nil, domain.NewNotFound("invitation not found")
+    }
+    return &i, nil
+}
+
+func (s *invitationStore) GetByHash(ctx context.Context, hash string) (*domain.Invitation, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    for _, i := range s.invitations {
+        if i.TokenHash == hash {
+            out := i
+            return &out, nil
+        }
+    }
+    return nil, domain.NewNotFound("invitation not found")
+}
+
+func (s *invitationStore) GetPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    var found *domain.Invitation
+    for _, i := range s.invitations {
+        if invitationInTenant(ctx, i) && i.Status == domain.InvitationPending && strings.EqualFold(i.Email, email) {
+            if found == nil || i.ID > found.ID {
+                out := i
+                found = &out
+            }
+        }
+    }
+    if found == nil {
+        return nil, domain.NewNotFound("invitation not found")
+    }
+    return found, nil
+}
+
+func (s *invitationStore) List(ctx context.Context, page, limit int) ([]*domain.Invitation, int64, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    if page < 1 {
+        page = 1
+    }
+    if limit < 1 {
+        limit = 10
+    }
+
+    all := make([]*domain.Invitation, 0, len(s.invitations))
+    for _, i := range s.invitations {
+        if invitationInTenant(ctx, i) {
+            out := i
+            all = append(all, &out)
+        }
+    }
+    sort.Slice(all, func(a, b int) bool { return all[a].ID > all[b].ID })
+
+    total := int64(len(all))
+    start := (page - 1) * limit
+    if start >= len(all) {
+        return []*domain.Invitation{}, total, nil
+    }
+    end := start + limit
+    if end > len(all) {
+        end = len(all)
+    }
+    return all[start:end], total, nil
+}
+
+func (s *invitationStore) Reissue(ctx context.Context, id uint, tokenHash string, expiresAt time.Time) (*domain.Invitation, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    i, err := s.pending(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    i.TokenHash = tokenHash
+    i.ExpiresAt = expiresAt
+    i.UpdatedAt = time.Now().UTC()
+    s.invitations[id] = i
+    return &i, nil
+}
+
+func (s *invitationStore) MarkAccepted(ctx context.Context, id, userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    i, err := s.pending(ctx, id)
+    if err != nil {
+        return err
+    }
+    i.Status = domain.InvitationAccepted
+    i.UserID = userID
+    i.AcceptedAt = &at
+    i.UpdatedAt = at
+    s.invitations[id] = i
+    return nil
+}
+
+func (s *invitationStore) MarkRevoked(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    i, err := s.pending(ctx, id)
+    if err != nil {
+        return err
+    }
+    i.Status = domain.InvitationRevoked
+    i.RevokedAt = &at
+    i.UpdatedAt = at
+    s.invitations[id] = i
+    return nil
+}
+
+// pending returns the invitation if it is still pending. Callers hold the lock.
+func (s *invitationStore) pending(ctx context.Context, id uint) (domain.Invitation, error) {
+    i, ok := s.invitations[id]
+    if !ok || !invitationInTenant(ctx, i) {
+        return i, domain.NewNotFound("invitation not found")
+    }
+    if i.Status != domain.InvitationPending {
+        return i, domain.NewConflict("invitation is no longer pending")
+    }
+    return i, nil
+}
+
+func invitationInTenant(ctx context.Context, i domain.Invitation) bool {
+    tenantID, ok := domain.TenantFromContext(ctx)
+    return !ok || i.TenantID == tenantID
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// oauthClientStore is an in-memory implementation of domain.OAuthClientRepository.
+type oauthClientStore struct {
+    mu      sync.Mutex
+    nextID  uint
+    clients map[uint]domain.OAuthClient
+}
+
+func NewOAuthClientStore() domain.OAuthClientRepository {
+    return &oauthClientStore{
+        nextID:  1,
+        clients: make(map[uint]domain.OAuthClient),
+    }
+}
+
+func (s *oauthClientStore) Create(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, existing := range s.clients {
+        if existing.ClientID == client.ClientID {
+            return nil, domain.NewConflict("client ID already exists")
+        }
+    }
+
+    c := copyOAuthClient(*client)
+    c.ID = s.nextID
+    s.nextID++
+    if c.CreatedAt.IsZero() {
+        c.CreatedAt = time.Now().UTC()
+    }
+    s.clients[c.ID] = c
+    out := copyOAuthClient(c)
+    return &out, nil
+}
+
+func (s *oauthClientStore) GetByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, c := range s.clients {
+        if c.ClientID == clientID {
+            out := copyOAuthClient(c)
+            return &out, nil
+        }
+    }
+    return nil, domain.NewNotFound("OAuth client not found")
+}
+
+func (s *oauthClientStore) ListByTenant(ctx context.Context, tenantID uint) ([]domain.OAuthClient, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    clients := make([]domain.OAuthClient, 0)
+    for _, c := range s.clients {
+        if c.TenantID == tenantID {
+            clients = append(clients, copyOAuthClient(c))
+        }
+    }
+    sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+    return clients, nil
+}
+
+func (s *oauthClientStore) Delete(ctx context.Context, id uint) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, ok := s.clients[id]; !ok {
+        return domain.NewNotFound("OAuth client not found")
+    }
+    delete(s.clients, id)
+    return nil
+}
+
+// copyOAuthClient returns c with its own copies of the slices it holds.
+func copyOAuthClient(c domain.OAuthClient) domain.OAuthClient {
+    c.RedirectURIs = append([]string(nil), c.RedirectURIs...)
+    c.GrantTypes = append([]string(nil), c.GrantTypes...)
+    c.Scopes = append([]string(nil), c.Scopes...)
+    return c
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// oauthCodeStore is an in-memory implementation of domain.OAuthCodeRepository.
+type oauthCodeStore struct {
+    mu     sync.Mutex
+    nextID uint
+    codes  map[uint]domain.OAuthCode
+}
+
+func NewOAuthCodeStore() domain.OAuthCodeRepository {
+    return &oauthCodeStore{
+        nextID: 1,
+        codes:  make(map[uint]domain.OAuthCode),
+    }
+}
+
+func (s *oauthCodeStore) Create(ctx context.Context, code *domain.OAuthCode) (*domain.OAuthCode, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    c := *code
+    c.ID = s.nextID
+    s.nextID++
+    if c.CreatedAt.IsZero() {
+        c.CreatedAt = time.Now().UTC()
+    }
+    s.codes[c.ID] = c
+    return &c, nil
+}
+
+func (s *oauthCodeStore) GetByHash(ctx context.Context, hash string) (*domain.OAuthCode, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, c := range s.codes {
+        if c.CodeHash == hash {
+            cc := c
+            return &cc, nil
+        }
+    }
+    return nil, domain.NewNotFound("authorization code not found")
+}
+
+func (s *oauthCodeStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    c, ok := s.codes[id]
+    if !ok {
+        return domain.NewNotFound("authorization code not found")
+    }
+    if c.ConsumedAt != nil {
+        return domain.NewConflict("authorization code already used")
+    }
+    c.ConsumedAt = &at
+    s.codes[id] = c
+    return nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// oauthTokenStore is an in-memory implementation of domain.OAuthTokenRepository.
+type oauthTokenStore struct {
+    mu     sync.Mutex
+    nextID uint
+    tokens map[uint]domain.OAuthToken
+}
+
+func NewOAuthTokenStore() domain.OAuthTokenRepository {
+    return &oauthTokenStore{
+        nextID: 1,
+        tokens: make(map[uint]domain.OAuthToken),
+    }
+}
+
+func (s *oauthTokenStore) Create(ctx context.Context, token *domain.OAuthToken) (*domain.OAuthToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t := *token
+    t.ID = s.nextID
+    s.nextID++
+    if t.CreatedAt.IsZero() {
+        t.CreatedAt = time.Now().UTC()
+    }
+    s.tokens[t.ID] = t
+    return &t, nil
+}
+
+func (s *oauthTokenStore) GetByHash(ctx context.Context, hash string) (*domain.OAuthToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, t := range s.tokens {
+        if t.TokenHash == hash {
+            tt := t
+            return &tt, nil
+        }
+    }
+    return nil, domain.NewNotFound("OAuth token not found")
+}
+
+func (s *oauthTokenStore) Revoke(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, ok := s.tokens[id]
+    if !ok {
+        return domain.NewNotFound("OAuth token not found")
+    }
+    if t.RevokedAt != nil {
+        return domain.NewConflict("OAuth token already revoked")
+    }
+    t.RevokedAt = &at
+    s.tokens[id] = t
+    return nil
+}
+
+func (s *oauthTokenStore) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, t := range s.tokens {
+        if t.FamilyID == familyID && t.RevokedAt == nil {
+            t.RevokedAt = &at
+            s.tokens[id] = t
+        }
+    }
+    return nil
+}
+
+func (s *oauthTokenStore) RevokeAllForClient(ctx context.Context, clientID string, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, t := range s.tokens {
+        if t.ClientID == clientID && t.RevokedAt == nil {
+            t.RevokedAt = &at
+            s.tokens[id] = t
+        }
+    }
+    return nil
+}
+
+func (s *oauthTokenStore) RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, t := range s.tokens {
+        if t.UserID == userID && t.RevokedAt == nil {
+            t.RevokedAt = &at
+            s.tokens[id] = t
+        }
+    }
+    return nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// organizationStore is an in-memory implementation of domain.OrganizationRepository.
+type organizationStore struct {
+    mu     sync.RWMutex
+    nextID uint
+    orgs   map[uint]domain.Organization
+}
+
+func NewOrganizationStore() domain.OrganizationRepository {
+    return &organizationStore{
+        nextID: 1,
+        orgs:   make(map[uint]domain.Organization),
+    }
+}
+
+func (s *organizationStore) Create(ctx context.Context, org *domain.Organization) (*domain.Organization, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, o := range s.orgs {
+        if o.Slug == org.Slug {
+            return nil, domain.NewConflict("organization slug already exists")
+        }
+    }
+
+    o := *org
+    if o.ID == 0 {
+        o.ID = s.nextID
+    }
+    if _, taken := s.orgs[o.ID]; taken {
+        return nil, domain.NewConflict("organization already exists")
+    }
+    if o.ID >= s.nextID {
+        s.nextID = o.ID + 1
+    }
+    if o.CreatedAt.IsZero() {
+        o.CreatedAt = time.Now().UTC()
+    }
+    s.orgs[o.ID] = o
+    return &o, nil
+}
+
+func (s *organizationStore) GetByID(ctx context.Context, id uint) (*domain.Organization, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    o, ok := s.orgs[id]
+    if !ok {
+        return nil, domain.NewNotFound("organization not found")
+    }
+    return &o, nil
+}
+
+func (s *organizationStore) GetBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    for _, o := range s.orgs {
+        if o.Slug == slug {
+            oo := o
+            return &oo, nil
+        }
+    }
+    return nil, domain.NewNotFound("organization not found")
+}
+
+func (s *organizationStore) List(ctx context.Context) ([]*domain.Organization, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    out := make([]*domain.Organization, 0, len(s.orgs))
+    for _, o := range s.orgs {
+        oo := o
+        out = append(out, &oo)
+    }
+    sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+    return out, nil
+}
+
+func (s *organizationStore) Update(ctx context.Context, org *domain.Organization) (*domain.Organization, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    existing, ok := s.orgs[org.ID]
+    if !ok {
+        return nil, domain.NewNotFound("organization not found")
+    }
+    existing.Name = org.Name
+    existing.MFARequiredRoles = append([]domain.Role(nil), org.MFARequiredRoles...)
+    s.orgs[org.ID] = existing
+    return &existing, nil
+}
+
+func (s *organizationStore) Delete(ctx context.Context, id uint) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, ok := s.orgs[id]; !ok {
+        return domain.NewNotFound("organization not found")
+    }
+    delete(s.orgs, id)
+    return nil
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// passwordResetStore is an in-memory implementation of domain.PasswordResetRepository.
+type passwordResetStore struct {
+    mu     sync.Mutex
+    nextID uint
+    resets map[uint]domain.PasswordReset
+}
+
+func NewPasswordResetStore() domain.PasswordResetRepository {
+    return &passwordResetStore{
+        nextID: 1,
+        resets: make(map[uint]domain.PasswordReset),
+    }
+}
+
+func (s *passwordResetStore) Create(ctx context.Context, reset *domain.PasswordReset) (*domain.PasswordReset, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    r := *reset
+    r.ID = s.nextID
+    s.nextID++
+    if r.CreatedAt.IsZero() {
+        r.CreatedAt = time.Now().UTC()
+    }
+    s.resets[r.ID] = r
+    return &r, nil
+}
+
+func (s *passwordResetStore) GetByHash(ctx context.Context, hash string) (*domain.PasswordReset, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, r := range s.resets {
+        if r.TokenHash == hash {
+            rr := r
+            return &rr, nil
+        }
+    }
+    return nil, domain.NewNotFound("password reset not found")
+}
+
+func (s *passwordResetStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    r, ok := s.resets[id]
+    if !ok {
+        return domain.NewNotFound("password reset not found")
+    }
+    if r.ConsumedAt != nil {
+        return domain.NewConflict("password reset already used")
+    }
+    r.ConsumedAt = &at
+    s.resets[id] = r
+    return nil
+}
+
+func (s *passwordResetStore) ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, r := range s.resets {
+        if r.UserID == userID && r.ConsumedAt == nil {
+            r.ConsumedAt = &at
+            s.resets[id] = r
+        }
+    }
+    return nil
+}
+
+func (s *passwordResetStore) CountSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    var n int64
+    for _, r := range s.resets {
+        if r.UserID == userID && !r.CreatedAt.Before(since) {
+            n++
+        }
+    }
+    return n, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// recoveryCodeStore is an in-memory implementation of domain.RecoveryCodeRepository.
+type recoveryCodeStore struct {
+    mu     sync.Mutex
+    nextID uint
+    codes  map[uint][]domain.RecoveryCode
+}
+
+func NewRecoveryCodeStore() domain.RecoveryCodeRepository {
+    return &recoveryCodeStore{
+        nextID: 1,
+        codes:  make(map[uint][]domain.RecoveryCode),
+    }
+}
+
+func (s *recoveryCodeStore) Replace(ctx context.Context, userID uint, hashes []string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    codes := make([]domain.RecoveryCode, 0, len(hashes))
+    for _, h := range hashes {
+        codes = append(codes, domain.RecoveryCode{ID: s.nextID, UserID: userID, CodeHash: h})
+        s.nextID++
+    }
+    s.codes[userID] = codes
+    return nil
+}
+
+func (s *recoveryCodeStore) Consume(ctx context.Context, userID uint, hash string, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    codes := s.codes[userID]
+    for i := range codes {
+        if codes[i].CodeHash == hash && codes[i].UsedAt == nil {
+            codes[i].UsedAt = &at
+            return nil
+        }
+    }
+    return domain.NewNotFound("recovery code not found")
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "userHub/internal/domain"
+)
+
+// refreshTokenStore is an in-memory implementation of domain.RefreshTokenRepository.
+type refreshTokenStore struct {
+    mu     sync.Mutex
+    nextID uint
+    tokens map[uint]domain.RefreshToken
+}
+
+func NewRefreshTokenStore() domain.RefreshTokenRepository {
+    return &refreshTokenStore{
+        nextID: 1,
+        tokens: make(map[uint]domain.RefreshToken),
+    }
+}
+
+func (s *refreshTokenStore) Create(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t := *token
+    t.ID = s.nextID
+    s.nextID++
+    if t.CreatedAt.IsZero() {
+        t.CreatedAt = time.Now().UTC()
+    }
+    s.tokens[t.ID] = t
+    return &t, nil
+}
+
+func (s *refreshTokenStore) GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, t := range s.tokens {
+        if t.TokenHash == hash {
+            tt := t
+            return &tt, nil
+        }
+    }
+    return nil, domain.NewNotFound("refresh token not found")
+}
+
+func (s *refreshTokenStore) MarkRotated(ctx context.Context, id uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, ok := s.tokens[id]
+    if !ok {
+        return domain.NewNotFound("refresh token not found")
+    }
+    if t.RotatedAt != nil || t.RevokedAt != nil {
+        return domain.NewConflict("refresh token already used")
+    }
+    t.RotatedAt = &at
+    s.tokens[id] = t
+    return nil
+}
+
+func (s *refreshTokenStore) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, t := range s.tokens {
+        if t.FamilyID == familyID && t.RevokedAt == nil {
+            t.RevokedAt = &at
+            s.tokens[id] = t
+        }
+    }
+    return nil
+}
+
+func (s *refreshTokenStore) RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, t := range s.tokens {
+        if t.UserID == userID && t.RevokedAt == nil {
+            t.RevokedAt = &at
+            s.tokens[id] = t
+        }
+    }
+    return nil
+}
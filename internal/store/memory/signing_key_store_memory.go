@@ -0,0 +1,52 @@
+package memory
+
+import (
+    "context"
+    "sort"
+    "sync"
+
+    "userHub/internal/domain"
+)
+
+// signingKeyStore is an in-memory implementation of domain.SigningKeyRepository.
+type signingKeyStore struct {
+    mu   sync.RWMutex
+    keys map[string]domain.SigningKey
+}
+
+func NewSigningKeyStore() domain.SigningKeyRepository {
+    return &signingKeyStore{keys: make(map[string]domain.SigningKey)}
+}
+
+func (s *signingKeyStore) List(ctx context.Context) ([]domain.SigningKey, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    keys := make([]domain.SigningKey, 0, len(s.keys))
+    for _, k := range s.keys {
+        keys = append(keys, k)
+    }
+    sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+    return keys, nil
+}
+
+func (s *signingKeyStore) Create(ctx context.Context, key *domain.SigningKey) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.keys[key.ID]; exists {
+        return domain.NewConflict("signing key already exists")
+    }
+    s.keys[key.ID] = *key
+    return nil
+}
+
+func (s *signingKeyStore) Delete(ctx context.Context, ids ...string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, id := range ids {
+        delete(s.keys, id)
+    }
+    return nil
+}
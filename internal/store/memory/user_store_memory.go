@@ -2,42 +2,82 @@ package memory
 
 import (
     "context"
+    "fmt"
+    "sort"
     "strings"
     "sync"
+    "time"
 
     "userHub/internal/domain"
+    "userHub/pkg/search"
+
+    "gorm.io/gorm"
 )
 
-// userStore is an in-memory implementation of domain.UserRepository.
-// Useful for tests and local development.
+// userStore is an in-memory implementation of domain.UserRepository and
+// domain.UserSearcher. Useful for tests and local development.
 type userStore struct {
     mu     sync.RWMutex
     nextID uint
     users  map[uint]domain.User
+    // index holds the name and email of every stored user, deleted or not.
+    index *search.Index
 }
 
 func NewUserStore() domain.UserRepository {
     return &userStore{
         nextID: 1,
         users:  make(map[uint]domain.User),
+        index:  search.NewIndex(),
+    }
+}
+
+// NewUserSearcher returns a searcher over users, which must come from
+// NewUserStore. The store keeps its index up to date on every write.
+func NewUserSearcher(users domain.UserRepository) (domain.UserSearcher, error) {
+    s, ok := users.(*userStore)
+    if !ok {
+        return nil, fmt.Errorf("memory: cannot search %T, only users from NewUserStore", users)
     }
+    return s, nil
+}
+
+// inTenant reports whether u belongs to the tenant carried by ctx, if any.
+func inTenant(ctx context.Context, u domain.User) bool {
+    tenantID, ok := domain.TenantFromContext(ctx)
+    return !ok || u.TenantID == tenantID
+}
+
+// visible reports whether u is a live (not soft-deleted) user of the tenant in ctx.
+func visible(ctx context.Context, u domain.User) bool {
+    return inTenant(ctx, u) && !u.DeletedAt.Valid
 }
 
 func (s *userStore) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
     s.mu.Lock()
     defer s.mu.Unlock()
 
-    // Enforce unique email
-    for _, u := range s.users {
-        if strings.EqualFold(u.Email, user.Email) {
+    u := *user
+    if tenantID, ok := domain.TenantFromContext(ctx); ok {
+        u.TenantID = tenantID
+    }
+    if u.TenantID == 0 {
+        u.TenantID = domain.DefaultTenantID
+    }
+
+    // Enforce unique email within the tenant; soft-deleted users keep theirs reserved.
+    for _, existing := range s.users {
+        if existing.TenantID == u.TenantID && strings.EqualFold(existing.Email, u.Email) {
             return nil, domain.NewConflict("email already exists")
         }
     }
 
-    u := *user
+    u.Version = 1
+    u.MarkCreated(ctx)
     u.ID = s.nextID
     s.nextID++
     s.users[u.ID] = u
+    s.index.Add(u.ID, u.Name, u.Email)
     return &u, nil
 }
 
@@ -46,7 +86,7 @@ func (s *userStore) GetByID(ctx context.Context, id uint) (*domain.User, error)
     defer s.mu.RUnlock()
 
     u, ok := s.users[id]
-    if !ok {
+    if !ok || !visible(ctx, u) {
         return nil, domain.NewNotFound("user not found")
     }
     return &u, nil
@@ -57,7 +97,7 @@ func (s *userStore) GetByEmail(ctx context.Context, email string) (*domain.User,
     defer s.mu.RUnlock()
 
     for _, u := range s.users {
-        if strings.EqualFold(u.Email, email) {
+        if visible(ctx, u) && strings.EqualFold(u.Email, email) {
             uu := u
             return &uu, nil
         }
@@ -69,31 +109,75 @@ func (s *userStore) Update(ctx context.Context, user *domain.User) (*domain.User
     s.mu.Lock()
     defer s.mu.Unlock()
 
-    if _, ok := s.users[user.ID]; !ok {
+    existing, ok := s.users[user.ID]
+    if !ok || !visible(ctx, existing) {
         return nil, domain.NewNotFound("user not found")
     }
 
-    // If email is being updated in the future, enforce uniqueness here.
+    if existing.Version != user.Version {
+        return nil, domain.NewPreconditionFailed("user was modified by another request")
+    }
+
+    // Email changes must not collide with another user of the tenant,
+    // including soft-deleted ones.
+    if !strings.EqualFold(existing.Email, user.Email) {
+        for id, other := range s.users {
+            if id != user.ID && other.TenantID == existing.TenantID && strings.EqualFold(other.Email, user.Email) {
+                return nil, domain.NewConflict("email already exists")
+            }
+        }
+    }
+
     u := *user
+    u.TenantID = existing.TenantID
+    u.CreatedAt, u.CreatedBy = existing.CreatedAt, existing.CreatedBy
+    // Credentials change only through UpdateCredential.
+    u.Credential = existing.Credential
+    u.MarkUpdated(ctx)
+    u.Version++
     s.users[u.ID] = u
+    s.index.Add(u.ID, u.Name, u.Email)
     return &u, nil
 }
 
-func (s *userStore) Delete(ctx context.Context, id uint) error {
+func (s *userStore) UpdateCredential(ctx context.Context, id uint, prev, next domain.Credential) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, ok := s.users[id]
+    if !ok || !visible(ctx, u) {
+        return domain.NewNotFound("user not found")
+    }
+    if u.Credential.TOTPLastStep != prev.TOTPLastStep || u.Credential.MFAFailedAttempts != prev.MFAFailedAttempts {
+        return domain.NewPreconditionFailed("user was modified by another request")
+    }
+    u.Credential = next
+    s.users[id] = u
+    return nil
+}
+
+func (s *userStore) Delete(ctx context.Context, id uint, expectedVersion uint) error {
     s.mu.Lock()
     defer s.mu.Unlock()
 
-    if _, ok := s.users[id]; !ok {
+    u, ok := s.users[id]
+    if !ok || !visible(ctx, u) {
         return domain.NewNotFound("user not found")
     }
-    delete(s.users, id)
+    if expectedVersion != 0 && u.Version != expectedVersion {
+        return domain.NewPreconditionFailed("user was modified by another request")
+    }
+    u.MarkUpdated(ctx)
+    u.DeletedAt = gorm.DeletedAt{Time: u.UpdatedAt, Valid: true}
+    s.users[id] = u
     return nil
 }
 
-func (s *userStore) List(ctx context.Context, page, limit int, q string) ([]*domain.User, int64, error) {
+func (s *userStore) List(ctx context.Context, query domain.UserQuery) ([]*domain.User, int64, error) {
     s.mu.RLock()
     defer s.mu.RUnlock()
 
+    page, limit := query.Page, query.Limit
     if page < 1 {
         page = 1
     }
@@ -104,23 +188,9 @@ func (s *userStore) List(ctx context.Context, page, limit int, q string) ([]*dom
         limit = 100
     }
 
-    qq := strings.ToLower(strings.TrimSpace(q))
-
-    // Filter
-    filtered := make([]domain.User, 0, len(s.users))
-    for _, u := range s.users {
-        if qq == "" {
-            filtered = append(filtered, u)
-            continue
-        }
-        if strings.Contains(strings.ToLower(u.Name), qq) || strings.Contains(strings.ToLower(u.Email), qq) {
-            filtered = append(filtered, u)
-        }
-    }
-
+    filtered := s.filtered(ctx, query)
     total := int64(len(filtered))
 
-    // Paginate
     start := (page - 1) * limit
     if start >= len(filtered) {
         return []*domain.User{}, total, nil
@@ -137,3 +207,101 @@ func (s *userStore) List(ctx context.Context, page, limit int, q string) ([]*dom
     }
     return out, total, nil
 }
+
+func (s *userStore) ListByCursor(ctx context.Context, query domain.UserQuery, cursor domain.UserCursor) ([]*domain.User, bool, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    filtered := s.filtered(ctx, query)
+    ordering := query.Ordering()
+    limit := query.Limit
+
+    // filtered is in query order, so the cursor splits it in two.
+    var window []domain.User
+    more := false
+    if cursor.Backward {
+        end := len(filtered)
+        if cursor.ID != 0 {
+            end = sort.Search(len(filtered), func(i int) bool { return cursor.Compare(&filtered[i], ordering) >= 0 })
+        }
+        start := max(end-limit, 0)
+        window, more = filtered[start:end], start > 0
+    } else {
+        start := 0
+        if cursor.ID != 0 {
+            start = sort.Search(len(filtered), func(i int) bool { return cursor.Compare(&filtered[i], ordering) > 0 })
+        }
+        end := min(start+limit, len(filtered))
+        window, more = filtered[start:end], end < len(filtered)
+    }
+
+    out := make([]*domain.User, 0, len(window))
+    for i := range window {
+        u := window[i]
+        out = append(out, &u)
+    }
+    return out, more, nil
+}
+
+// filtered returns the visible users matching query in its order, so
+// consecutive pages neither overlap nor skip users. Callers hold the lock.
+func (s *userStore) filtered(ctx context.Context, query domain.UserQuery) []domain.User {
+    filtered := make([]domain.User, 0, len(s.users))
+    for _, u := range s.users {
+        if visible(ctx, u) && query.Matches(&u) {
+            filtered = append(filtered, u)
+        }
+    }
+
+    ordering := query.Ordering()
+    sort.Slice(filtered, func(i, j int) bool { return domain.CompareUsers(&filtered[i], &filtered[j], ordering) < 0 })
+    return filtered
+}
+
+func (s *userStore) Restore(ctx context.Context, id uint) (*domain.User, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, ok := s.users[id]
+    if !ok || !inTenant(ctx, u) || !u.DeletedAt.Valid {
+        return nil, domain.NewNotFound("deleted user not found")
+    }
+    u.DeletedAt = gorm.DeletedAt{}
+    u.MarkUpdated(ctx)
+    u.Version++
+    s.users[id] = u
+    return &u, nil
+}
+
+func (s *userStore) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    var purged int64
+    for id, u := range s.users {
+        if inTenant(ctx, u) && u.DeletedAt.Valid && u.DeletedAt.Time.Before(deletedBefore) {
+            delete(s.users, id)
+            s.index.Remove(id)
+            purged++
+        }
+    }
+    return purged, nil
+}
+
+func (s *userStore) Search(ctx context.Context, text string, limit int) ([]domain.UserSearchHit, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    var hits []domain.UserSearchHit
+    for _, hit := range s.index.Search(text) {
+        if limit > 0 && len(hits) == limit {
+            break
+        }
+        u, ok := s.users[hit.ID]
+        if !ok || !visible(ctx, u) {
+            continue
+        }
+        hits = append(hits, domain.UserSearchHit{User: &u, Score: hit.Score})
+    }
+    return hits, nil
+}
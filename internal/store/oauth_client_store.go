@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// oauthClientStore implements domain.OAuthClientRepository
+type oauthClientStore struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientStore creates a new OAuthClientRepository backed by GORM
+func NewOAuthClientStore(db *gorm.DB) domain.OAuthClientRepository {
+	return &oauthClientStore{db: db}
+}
+
+func (s *oauthClientStore) Create(ctx context.Context, client *domain.OAuthClient) (*domain.OAuthClient, error) {
+	if err := s.db.WithContext(ctx).Create(client).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, domain.NewConflict("client ID already exists")
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+func (s *oauthClientStore) GetByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	var client domain.OAuthClient
+	if err := s.db.WithContext(ctx).Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("OAuth client not found")
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (s *oauthClientStore) ListByTenant(ctx context.Context, tenantID uint) ([]domain.OAuthClient, error) {
+	var clients []domain.OAuthClient
+	if err := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("id").Find(&clients).Error; err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (s *oauthClientStore) Delete(ctx context.Context, id uint) error {
+	res := s.db.WithContext(ctx).Delete(&domain.OAuthClient{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewNotFound("OAuth client not found")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// oauthCodeStore implements domain.OAuthCodeRepository
+type oauthCodeStore struct {
+	db *gorm.DB
+}
+
+// NewOAuthCodeStore creates a new OAuthCodeRepository backed by GORM
+func NewOAuthCodeStore(db *gorm.DB) domain.OAuthCodeRepository {
+	return &oauthCodeStore{db: db}
+}
+
+func (s *oauthCodeStore) Create(ctx context.Context, code *domain.OAuthCode) (*domain.OAuthCode, error) {
+	if err := s.db.WithContext(ctx).Create(code).Error; err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+func (s *oauthCodeStore) GetByHash(ctx context.Context, hash string) (*domain.OAuthCode, error) {
+	var code domain.OAuthCode
+	if err := s.db.WithContext(ctx).Where("code_hash = ?", hash).First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("authorization code not found")
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (s *oauthCodeStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+	res := s.db.WithContext(ctx).Model(&domain.OAuthCode{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewConflict("authorization code already used")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// oauthTokenStore implements domain.OAuthTokenRepository
+type oauthTokenStore struct {
+	db *gorm.DB
+}
+
+// NewOAuthTokenStore creates a new OAuthTokenRepository backed by GORM
+func NewOAuthTokenStore(db *gorm.DB) domain.OAuthTokenRepository {
+	return &oauthTokenStore{db: db}
+}
+
+func (s *oauthTokenStore) Create(ctx context.Context, token *domain.OAuthToken) (*domain.OAuthToken, error) {
+	if err := s.db.WithContext(ctx).Create(token).Error; err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *oauthTokenStore) GetByHash(ctx context.Context, hash string) (*domain.OAuthToken, error) {
+	var token domain.OAuthToken
+	if err := s.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("OAuth token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *oauthTokenStore) Revoke(ctx context.Context, id uint, at time.Time) error {
+	res := s.db.WithContext(ctx).Model(&domain.OAuthToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewConflict("OAuth token already revoked")
+	}
+	return nil
+}
+
+func (s *oauthTokenStore) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.OAuthToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+func (s *oauthTokenStore) RevokeAllForClient(ctx context.Context, clientID string, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.OAuthToken{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientID).
+		Update("revoked_at", at).Error
+}
+
+func (s *oauthTokenStore) RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.OAuthToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
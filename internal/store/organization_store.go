@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// organizationStore implements domain.OrganizationRepository
+type organizationStore struct {
+	db *gorm.DB
+}
+
+// NewOrganizationStore creates a new OrganizationRepository backed by GORM
+func NewOrganizationStore(db *gorm.DB) domain.OrganizationRepository {
+	return &organizationStore{db: db}
+}
+
+func (s *organizationStore) Create(ctx context.Context, org *domain.Organization) (*domain.Organization, error) {
+	if err := s.db.WithContext(ctx).Create(org).Error; err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *organizationStore) GetByID(ctx context.Context, id uint) (*domain.Organization, error) {
+	var org domain.Organization
+	if err := s.db.WithContext(ctx).First(&org, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("organization not found")
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (s *organizationStore) GetBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	var org domain.Organization
+	if err := s.db.WithContext(ctx).Where("slug = ?", slug).First(&org).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("organization not found")
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (s *organizationStore) List(ctx context.Context) ([]*domain.Organization, error) {
+	var orgs []*domain.Organization
+	if err := s.db.WithContext(ctx).Order("id").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (s *organizationStore) Update(ctx context.Context, org *domain.Organization) (*domain.Organization, error) {
+	res := s.db.WithContext(ctx).Model(org).Select("name", "mfa_required_roles").Updates(org)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	// MySQL does not count rows whose values did not change, so
+	// RowsAffected cannot tell a missing organization; GetByID can.
+	return s.GetByID(ctx, org.ID)
+}
+
+func (s *organizationStore) Delete(ctx context.Context, id uint) error {
+	res := s.db.WithContext(ctx).Delete(&domain.Organization{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewNotFound("organization not found")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"userHub/internal/domain"
+)
+
+// unchangedDB answers like MySQL after a write that changed nothing: UPDATE
+// affects no rows, and organization 1 is still there for a SELECT.
+type unchangedDB struct{}
+
+func init() { sql.Register("unchanged", unchangedDB{}) }
+
+func (unchangedDB) Open(string) (driver.Conn, error) { return unchangedConn{}, nil }
+
+type unchangedConn struct{}
+
+func (unchangedConn) Prepare(query string) (driver.Stmt, error) { return unchangedStmt(query), nil }
+func (unchangedConn) Close() error                              { return nil }
+func (unchangedConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type unchangedStmt string
+
+func (unchangedStmt) Close() error  { return nil }
+func (unchangedStmt) NumInput() int { return -1 }
+
+func (unchangedStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (s unchangedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := &orgRows{}
+	if strings.HasPrefix(string(s), "SELECT") && len(args) > 0 && args[0] == int64(1) {
+		rows.values = [][]driver.Value{{int64(1), "Acme", "acme", `["admin"]`}}
+	}
+	return rows, nil
+}
+
+type orgRows struct{ values [][]driver.Value }
+
+func (*orgRows) Columns() []string { return []string{"id", "name", "slug", "mfa_required_roles"} }
+func (*orgRows) Close() error      { return nil }
+
+func (r *orgRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestOrganizationStore_UpdateWithoutChanges(t *testing.T) {
+	conn, err := sql.Open("unchanged", "")
+	require.NoError(t, err)
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true}), &gorm.Config{SkipDefaultTransaction: true, Logger: logger.Discard})
+	require.NoError(t, err)
+	orgs := NewOrganizationStore(db)
+	ctx := context.Background()
+
+	// Saving the policy it already has is no reason for a 404.
+	org := &domain.Organization{ID: 1, Name: "Acme", MFARequiredRoles: []domain.Role{domain.RoleAdmin}}
+	for i := 0; i < 2; i++ {
+		updated, err := orgs.Update(ctx, org)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Role{domain.RoleAdmin}, updated.MFARequiredRoles)
+	}
+
+	_, err = orgs.Update(ctx, &domain.Organization{ID: 2, Name: "Gone"})
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domain.CodeNotFound, appErr.Code)
+}
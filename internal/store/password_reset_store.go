@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// passwordResetStore implements domain.PasswordResetRepository
+type passwordResetStore struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetStore creates a new PasswordResetRepository backed by GORM
+func NewPasswordResetStore(db *gorm.DB) domain.PasswordResetRepository {
+	return &passwordResetStore{db: db}
+}
+
+func (s *passwordResetStore) Create(ctx context.Context, reset *domain.PasswordReset) (*domain.PasswordReset, error) {
+	if err := s.db.WithContext(ctx).Create(reset).Error; err != nil {
+		return nil, err
+	}
+	return reset, nil
+}
+
+func (s *passwordResetStore) GetByHash(ctx context.Context, hash string) (*domain.PasswordReset, error) {
+	var reset domain.PasswordReset
+	if err := s.db.WithContext(ctx).Where("token_hash = ?", hash).First(&reset).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("password reset not found")
+		}
+		return nil, err
+	}
+	return &reset, nil
+}
+
+func (s *passwordResetStore) MarkConsumed(ctx context.Context, id uint, at time.Time) error {
+	res := s.db.WithContext(ctx).Model(&domain.PasswordReset{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewConflict("password reset already used")
+	}
+	return nil
+}
+
+func (s *passwordResetStore) ConsumeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.PasswordReset{}).
+		Where("user_id = ? AND consumed_at IS NULL", userID).
+		Update("consumed_at", at).Error
+}
+
+func (s *passwordResetStore) CountSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var n int64
+	err := s.db.WithContext(ctx).Model(&domain.PasswordReset{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&n).Error
+	return n, err
+}
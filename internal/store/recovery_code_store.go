@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// recoveryCodeStore implements domain.RecoveryCodeRepository
+type recoveryCodeStore struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeStore creates a new RecoveryCodeRepository backed by GORM
+func NewRecoveryCodeStore(db *gorm.DB) domain.RecoveryCodeRepository {
+	return &recoveryCodeStore{db: db}
+}
+
+func (s *recoveryCodeStore) Replace(ctx context.Context, userID uint, hashes []string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(hashes) == 0 {
+			return nil
+		}
+		codes := make([]domain.RecoveryCode, 0, len(hashes))
+		for _, h := range hashes {
+			codes = append(codes, domain.RecoveryCode{UserID: userID, CodeHash: h})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (s *recoveryCodeStore) Consume(ctx context.Context, userID uint, hash string, at time.Time) error {
+	res := s.db.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewNotFound("recovery code not found")
+	}
+	return nil
+}
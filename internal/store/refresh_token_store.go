@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// refreshTokenStore implements domain.RefreshTokenRepository
+type refreshTokenStore struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenStore creates a new RefreshTokenRepository backed by GORM
+func NewRefreshTokenStore(db *gorm.DB) domain.RefreshTokenRepository {
+	return &refreshTokenStore{db: db}
+}
+
+func (s *refreshTokenStore) Create(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	if err := s.db.WithContext(ctx).Create(token).Error; err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *refreshTokenStore) GetByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	if err := s.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.NewNotFound("refresh token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (s *refreshTokenStore) MarkRotated(ctx context.Context, id uint, at time.Time) error {
+	res := s.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.NewConflict("refresh token already used")
+	}
+	return nil
+}
+
+func (s *refreshTokenStore) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+func (s *refreshTokenStore) RevokeAllForUser(ctx context.Context, userID uint, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// signingKeyStore implements domain.SigningKeyRepository
+type signingKeyStore struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyStore creates a new SigningKeyRepository backed by GORM
+func NewSigningKeyStore(db *gorm.DB) domain.SigningKeyRepository {
+	return &signingKeyStore{db: db}
+}
+
+func (s *signingKeyStore) List(ctx context.Context) ([]domain.SigningKey, error) {
+	var keys []domain.SigningKey
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *signingKeyStore) Create(ctx context.Context, key *domain.SigningKey) error {
+	return s.db.WithContext(ctx).Create(key).Error
+}
+
+func (s *signingKeyStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Where("id IN ?", ids).Delete(&domain.SigningKey{}).Error
+}
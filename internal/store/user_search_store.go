@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+
+	"userHub/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NewUserSearcher creates a UserSearcher backed by the FULLTEXT index on the
+// users' name and email. Matching follows the column collation, so accents
+// and case are ignored, but whole words are matched and typos are not
+// tolerated. Scores are MySQL's relevance values.
+func NewUserSearcher(db *gorm.DB) domain.UserSearcher {
+	return &userStore{db: db}
+}
+
+func (s *userStore) Search(ctx context.Context, text string, limit int) ([]domain.UserSearchHit, error) {
+	relevance := clause.Expr{SQL: "MATCH (name, email) AGAINST (? IN NATURAL LANGUAGE MODE)", Vars: []any{text}}
+
+	var rows []struct {
+		domain.User
+		Score float64
+	}
+	db := s.scoped(ctx).Model(&domain.User{}).
+		Select("users.*, ? AS score", relevance).
+		Where(relevance).
+		Order("score DESC, id")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	hits := make([]domain.UserSearchHit, len(rows))
+	for i := range rows {
+		hits[i] = domain.UserSearchHit{User: &rows[i].User, Score: rows[i].Score}
+	}
+	return hits, nil
+}
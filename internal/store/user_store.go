@@ -2,10 +2,15 @@ package store
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"time"
 
 	"userHub/internal/domain"
+	"userHub/pkg/filter"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // userStore implements domain.UserRepository
@@ -18,8 +23,26 @@ func NewUserStore(db *gorm.DB) domain.UserRepository {
 	return &userStore{db: db}
 }
 
+// scoped restricts queries to the tenant carried by ctx, if any.
+func (s *userStore) scoped(ctx context.Context) *gorm.DB {
+	db := s.db.WithContext(ctx)
+	if tenantID, ok := domain.TenantFromContext(ctx); ok {
+		db = db.Where("tenant_id = ?", tenantID)
+	}
+	return db
+}
+
 func (s *userStore) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+    if tenantID, ok := domain.TenantFromContext(ctx); ok {
+        user.TenantID = tenantID
+    }
+    user.Version = 1
+    user.MarkCreated(ctx)
     if err := s.db.WithContext(ctx).Create(user).Error; err != nil {
+        // Soft-deleted users keep their email reserved until they are purged.
+        if errors.Is(err, gorm.ErrDuplicatedKey) {
+            return nil, domain.NewConflict("email already exists")
+        }
         return nil, err
     }
     return user, nil
@@ -27,7 +50,7 @@ func (s *userStore) Create(ctx context.Context, user *domain.User) (*domain.User
 
 func (s *userStore) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-    if err := s.db.WithContext(ctx).First(&user, id).Error; err != nil {
+    if err := s.scoped(ctx).First(&user, id).Error; err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, domain.NewNotFound("user not found")
         }
@@ -38,7 +61,7 @@ func (s *userStore) GetByID(ctx context.Context, id uint) (*domain.User, error)
 
 func (s *userStore) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-    if err := s.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+    if err := s.scoped(ctx).Where("email = ?", email).First(&user).Error; err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, domain.NewNotFound("user not found")
         }
@@ -48,41 +71,230 @@ func (s *userStore) GetByEmail(ctx context.Context, email string) (*domain.User,
 }
 
 func (s *userStore) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
-    if err := s.db.WithContext(ctx).Save(user).Error; err != nil {
-        return nil, err
-    }
-    return user, nil
+	expected := user.Version
+	next := *user
+	next.Version = expected + 1
+	next.MarkUpdated(ctx)
+
+	// Compare-and-swap: the row is only written if nobody bumped the version since it was read.
+	// Credentials change only through UpdateCredential, so a stale read cannot undo them.
+	res := s.scoped(ctx).Model(&next).
+		Where("version = ?", expected).
+		Select("*").Omit(updateOmits...).
+		Updates(&next)
+	if res.Error != nil {
+		if errors.Is(res.Error, gorm.ErrDuplicatedKey) {
+			return nil, domain.NewConflict("email already exists")
+		}
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, s.casFailure(ctx, user.ID)
+	}
+
+	return s.GetByID(ctx, user.ID)
 }
 
-func (s *userStore) Delete(ctx context.Context, id uint) error {
-    res := s.db.WithContext(ctx).Delete(&domain.User{}, id)
-    if res.Error != nil {
-        return res.Error
-    }
-    if res.RowsAffected == 0 {
-        return domain.NewNotFound("user not found")
-    }
-    return nil
+// updateOmits are the columns Update never writes: the identity and creation
+// stamp of the row, and the columns of domain.Credential.
+var updateOmits = []string{
+	"id", "tenant_id", "deleted_at", "created_at", "created_by",
+	"password_hash", "password_changed_at", "totp_secret", "totp_pending_secret",
+	"totp_last_step", "mfa_enabled_at", "mfa_failed_attempts", "mfa_locked_until",
 }
 
-func (s *userStore) List(ctx context.Context, page, limit int, q string) ([]*domain.User, int64, error) {
-	var users []*domain.User
-	var total int64
+func (s *userStore) UpdateCredential(ctx context.Context, id uint, prev, next domain.Credential) error {
+	res := s.scoped(ctx).Model(&domain.User{}).
+		Where("id = ? AND totp_last_step = ? AND mfa_failed_attempts = ?", id, prev.TOTPLastStep, prev.MFAFailedAttempts).
+		Updates(map[string]any{
+			"password_hash":       next.PasswordHash,
+			"password_changed_at": next.PasswordChangedAt,
+			"totp_secret":         next.TOTPSecret,
+			"totp_pending_secret": next.TOTPPendingSecret,
+			"totp_last_step":      next.TOTPLastStep,
+			"mfa_enabled_at":      next.MFAEnabledAt,
+			"mfa_failed_attempts": next.MFAFailedAttempts,
+			"mfa_locked_until":    next.MFALockedUntil,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return s.casFailure(ctx, id)
+	}
+	return nil
+}
+
+func (s *userStore) Delete(ctx context.Context, id uint, expectedVersion uint) error {
+	query := s.scoped(ctx).Model(&domain.User{}).Where("id = ?", id)
+	if expectedVersion != 0 {
+		query = query.Where("version = ?", expectedVersion)
+	}
 
-	query := s.db.WithContext(ctx).Model(&domain.User{})
+	var stamp domain.User
+	stamp.MarkUpdated(ctx)
+	res := query.Updates(map[string]any{"deleted_at": stamp.UpdatedAt, "updated_at": stamp.UpdatedAt, "updated_by": stamp.UpdatedBy})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return s.casFailure(ctx, id)
+	}
+	return nil
+}
 
-	if q != "" {
-		query = query.Where("name LIKE ? OR email LIKE ?", "%"+q+"%", "%"+q+"%")
+// casFailure explains why a conditional write touched no rows.
+func (s *userStore) casFailure(ctx context.Context, id uint) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
 	}
+	return domain.NewPreconditionFailed("user was modified by another request")
+}
 
-	if err := query.Count(&total).Error; err != nil {
+func (s *userStore) List(ctx context.Context, query domain.UserQuery) ([]*domain.User, int64, error) {
+	var users []*domain.User
+	var total int64
+
+	db := s.filtered(ctx, query)
+	if err := db.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	offset := (page - 1) * limit
-	if err := query.Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+	offset := (query.Page - 1) * query.Limit
+	if err := db.Order(orderBy(query.Ordering(), false)).Offset(offset).Limit(query.Limit).Find(&users).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return users, total, nil
 }
+
+func (s *userStore) ListByCursor(ctx context.Context, query domain.UserQuery, cursor domain.UserCursor) ([]*domain.User, bool, error) {
+	var users []*domain.User
+
+	ordering := query.Ordering()
+	db := s.filtered(ctx, query)
+	if cursor.ID != 0 {
+		db = db.Where(keysetAfter(ordering, cursor))
+	}
+
+	// One extra row tells whether another page follows.
+	if err := db.Order(orderBy(ordering, cursor.Backward)).Limit(query.Limit + 1).Find(&users).Error; err != nil {
+		return nil, false, err
+	}
+
+	more := len(users) > query.Limit
+	if more {
+		users = users[:query.Limit]
+	}
+	if cursor.Backward {
+		slices.Reverse(users)
+	}
+	return users, more, nil
+}
+
+// filtered selects the users of the tenant matching query.
+func (s *userStore) filtered(ctx context.Context, query domain.UserQuery) *gorm.DB {
+	db := s.scoped(ctx).Model(&domain.User{})
+	if q := query.Search; q != "" {
+		db = db.Where("name LIKE ? OR email LIKE ?", "%"+q+"%", "%"+q+"%")
+	}
+	if query.Verified != nil {
+		if *query.Verified {
+			db = db.Where("email_verified_at IS NOT NULL")
+		} else {
+			db = db.Where("email_verified_at IS NULL")
+		}
+	}
+	if query.Gender != "" {
+		db = db.Where("gender = ?", query.Gender)
+	}
+	if query.EmailDomain != "" {
+		db = db.Where("email LIKE ?", "%@"+filter.EscapeLike(query.EmailDomain))
+	}
+	if len(query.IDs) > 0 {
+		db = db.Where("id IN ?", query.IDs)
+	}
+	if !query.CreatedAfter.IsZero() {
+		db = db.Where("created_at > ?", query.CreatedAfter)
+	}
+	if !query.CreatedBefore.IsZero() {
+		db = db.Where("created_at < ?", query.CreatedBefore)
+	}
+	if query.Filter != nil {
+		sql, args := filter.SQL(query.Filter, userColumn)
+		db = db.Where(sql, args...)
+	}
+	return db
+}
+
+// userColumn maps a field of domain.UserFilterFields to its column. Parsing
+// admits no other fields, so the rest are columns of the same name.
+func userColumn(field string) string {
+	if field == "verified" {
+		return "(email_verified_at IS NOT NULL)"
+	}
+	return field
+}
+
+// orderBy renders ordering as an ORDER BY clause, reversed for scanning
+// backward from a cursor. Sort fields are whitelisted column names.
+func orderBy(ordering []domain.UserSort, reverse bool) clause.OrderBy {
+	var order clause.OrderBy
+	for _, s := range ordering {
+		order.Columns = append(order.Columns, clause.OrderByColumn{Column: clause.Column{Name: string(s.Field)}, Desc: s.Desc != reverse})
+	}
+	return order
+}
+
+// keysetAfter matches the users past cursor in its direction: for an
+// ordering (a, b, id) that is a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ?
+// AND id > ?), with the comparisons flipped for descending fields.
+func keysetAfter(ordering []domain.UserSort, cursor domain.UserCursor) clause.Expression {
+	value := func(f domain.UserSortField) any {
+		if f == domain.UserSortID {
+			return cursor.ID
+		}
+		return f.Value(cursor.Keys[f])
+	}
+
+	var alternatives []clause.Expression
+	for i, s := range ordering {
+		var and []clause.Expression
+		for _, prev := range ordering[:i] {
+			and = append(and, clause.Eq{Column: clause.Column{Name: string(prev.Field)}, Value: value(prev.Field)})
+		}
+		column := clause.Column{Name: string(s.Field)}
+		if s.Desc != cursor.Backward {
+			and = append(and, clause.Lt{Column: column, Value: value(s.Field)})
+		} else {
+			and = append(and, clause.Gt{Column: column, Value: value(s.Field)})
+		}
+		alternatives = append(alternatives, clause.And(and...))
+	}
+	return clause.Or(alternatives...)
+}
+
+func (s *userStore) Restore(ctx context.Context, id uint) (*domain.User, error) {
+	var stamp domain.User
+	stamp.MarkUpdated(ctx)
+	res := s.scoped(ctx).Unscoped().Model(&domain.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1"), "updated_at": stamp.UpdatedAt, "updated_by": stamp.UpdatedBy})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, domain.NewNotFound("deleted user not found")
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *userStore) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res := s.scoped(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Delete(&domain.User{})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+    Name      string     `json:"name" validate:"required,min=1,max=100"`
+    Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+    ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyResponse struct {
+    ID         uint       `json:"id"`
+    Name       string     `json:"name"`
+    Prefix     string     `json:"prefix"`
+    Scopes     []string   `json:"scopes"`
+    ExpiresAt  time.Time  `json:"expires_at"`
+    LastUsedAt *time.Time `json:"last_used_at"`
+    CreatedAt  time.Time  `json:"created_at"`
+    RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKeyResponse is the only response that ever contains the key itself.
+type CreatedAPIKeyResponse struct {
+    APIKeyResponse
+    Key string `json:"key"`
+}
+
+type ListAPIKeysResponse struct {
+    Data []APIKeyResponse `json:"data"`
+}
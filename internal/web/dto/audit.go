@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type FieldChangeResponse struct {
+    From string `json:"from"`
+    To   string `json:"to"`
+}
+
+type AuditEventResponse struct {
+    ID        uint                           `json:"id"`
+    ActorID   uint                           `json:"actor_id"`
+    TargetID  uint                           `json:"target_id"`
+    Action    string                         `json:"action"`
+    Changes   map[string]FieldChangeResponse `json:"changes,omitempty"`
+    RequestID string                         `json:"request_id"`
+    ClientIP  string                         `json:"client_ip"`
+    CreatedAt time.Time                      `json:"created_at"`
+
+    // ImpersonatorID is the admin who acted as ActorID, if any.
+    ImpersonatorID uint `json:"impersonator_id,omitempty"`
+}
+
+type ListAuditEventsResponse struct {
+    Data []AuditEventResponse `json:"data"`
+    Meta struct {
+        Page  int   `json:"page"`
+        Limit int   `json:"limit"`
+        Total int64 `json:"total"`
+    } `json:"meta"`
+}
@@ -0,0 +1,60 @@
+package dto
+
+type LoginRequest struct {
+    Email    string `json:"email" validate:"required,email"`
+    Password string `json:"password" validate:"required"`
+}
+
+type RefreshRequest struct {
+    RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type TokenResponse struct {
+    AccessToken  string `json:"access_token"`
+    TokenType    string `json:"token_type"`
+    ExpiresIn    int64  `json:"expires_in"`
+    RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+    Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+    Token       string `json:"token" validate:"required"`
+    NewPassword string `json:"new_password" validate:"required,password"`
+}
+
+// MFAChallengeResponse is returned by login instead of tokens when the
+// account has a second factor; mfa_token goes to /auth/mfa/verify.
+type MFAChallengeResponse struct {
+    MFARequired bool   `json:"mfa_required"`
+    MFAToken    string `json:"mfa_token"`
+    ExpiresIn   int64  `json:"expires_in"`
+}
+
+type VerifyMFARequest struct {
+    MFAToken string `json:"mfa_token" validate:"required"`
+    Code     string `json:"code" validate:"required"`
+}
+
+type MFACodeRequest struct {
+    Code string `json:"code" validate:"required"`
+}
+
+type TOTPEnrollmentResponse struct {
+    Secret string `json:"secret"`
+    URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+    RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFAPolicyRequest struct {
+    Roles []string `json:"roles" validate:"required,dive,oneof=admin manager member"`
+}
+
+type MFAPolicyResponse struct {
+    Roles []string `json:"roles"`
+}
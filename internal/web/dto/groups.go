@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type CreateGroupRequest struct {
+    Name        string `json:"name" validate:"required,min=1,max=255"`
+    Role        string `json:"role" validate:"omitempty,role"`
+    MemberIDs   []uint `json:"member_ids"`
+    SubgroupIDs []uint `json:"subgroup_ids"`
+}
+
+// UpdateGroupRequest renames a group or changes its role; an empty role
+// stops the group from granting one. Members are changed with
+// GroupMembersRequest.
+type UpdateGroupRequest struct {
+    Name *string `json:"name" validate:"omitempty,min=1,max=255"`
+    Role *string `json:"role"`
+}
+
+// GroupMembersRequest lists the users and subgroups to add to or remove
+// from a group.
+type GroupMembersRequest struct {
+    UserIDs     []uint `json:"user_ids"`
+    SubgroupIDs []uint `json:"subgroup_ids"`
+}
+
+type GroupResponse struct {
+    ID          uint      `json:"id"`
+    Name        string    `json:"name"`
+    Role        string    `json:"role,omitempty"`
+    MemberIDs   []uint    `json:"member_ids"`
+    SubgroupIDs []uint    `json:"subgroup_ids"`
+    CreatedAt   time.Time `json:"created_at"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ListGroupsResponse struct {
+    Data []GroupResponse `json:"data"`
+    Meta struct {
+        Page  int   `json:"page"`
+        Limit int   `json:"limit"`
+        Total int64 `json:"total"`
+    } `json:"meta"`
+}
+
+// UserGroupResponse is a group a user belongs to; Direct is false when they
+// only belong to it through a subgroup.
+type UserGroupResponse struct {
+    GroupResponse
+    Direct bool `json:"direct"`
+}
+
+// ListUserGroupsResponse also reports the role the user holds once the
+// roles of their groups are taken into account.
+type ListUserGroupsResponse struct {
+    Data          []UserGroupResponse `json:"data"`
+    EffectiveRole string              `json:"effective_role"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// StartImpersonationRequest says why an admin acts as a user; the reason is
+// kept in the audit log.
+type StartImpersonationRequest struct {
+    Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type ImpersonationResponse struct {
+    ID             uint       `json:"id"`
+    UserID         uint       `json:"user_id"`
+    ImpersonatorID uint       `json:"impersonator_id"`
+    Reason         string     `json:"reason"`
+    ExpiresAt      time.Time  `json:"expires_at"`
+    CreatedAt      time.Time  `json:"created_at"`
+    EndedAt        *time.Time `json:"ended_at,omitempty"`
+}
+
+// StartImpersonationResponse carries the access token that acts as the
+// user. It cannot be refreshed.
+type StartImpersonationResponse struct {
+    ImpersonationResponse
+    Token TokenResponse `json:"token"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type CreateInvitationRequest struct {
+    Email string `json:"email" validate:"required,email"`
+    Role  string `json:"role" validate:"omitempty,role"`
+}
+
+// AcceptInvitationRequest completes the account of an invited user; the
+// email address and role come from the invitation.
+type AcceptInvitationRequest struct {
+    Token    string `json:"token" validate:"required"`
+    Name     string `json:"name" validate:"required,min=2,max=50"`
+    Gender   string `json:"gender" validate:"required,gender"`
+    Password string `json:"password" validate:"required,password"`
+}
+
+type InvitationResponse struct {
+    ID         uint       `json:"id"`
+    Email      string     `json:"email"`
+    Role       string     `json:"role"`
+    Status     string     `json:"status"`
+    InviterID  uint       `json:"inviter_id"`
+    UserID     uint       `json:"user_id,omitempty"`
+    ExpiresAt  time.Time  `json:"expires_at"`
+    CreatedAt  time.Time  `json:"created_at"`
+    AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+    RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type ListInvitationsResponse struct {
+    Data []InvitationResponse `json:"data"`
+    Meta struct {
+        Page  int   `json:"page"`
+        Limit int   `json:"limit"`
+        Total int64 `json:"total"`
+    } `json:"meta"`
+}
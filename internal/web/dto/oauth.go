@@ -0,0 +1,80 @@
+package dto
+
+import "time"
+
+type RegisterOAuthClientRequest struct {
+    Name         string   `json:"name" validate:"required,max=100"`
+    RedirectURIs []string `json:"redirect_uris" validate:"dive,required"`
+    GrantTypes   []string `json:"grant_types" validate:"required,min=1"`
+    Scopes       []string `json:"scopes"`
+    // Public clients (browser and native apps) get no secret and must use PKCE.
+    Public bool `json:"public"`
+}
+
+type OAuthClientResponse struct {
+    ClientID     string    `json:"client_id"`
+    Name         string    `json:"name"`
+    RedirectURIs []string  `json:"redirect_uris"`
+    GrantTypes   []string  `json:"grant_types"`
+    Scopes       []string  `json:"scopes"`
+    Public       bool      `json:"public"`
+    CreatedAt    time.Time `json:"created_at"`
+}
+
+// RegisteredOAuthClientResponse is the only response that ever contains the secret.
+type RegisteredOAuthClientResponse struct {
+    OAuthClientResponse
+    ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type ListOAuthClientsResponse struct {
+    Data []OAuthClientResponse `json:"data"`
+}
+
+// OAuthTokenRequest is the form body of POST /oauth/token. Client
+// credentials may come from HTTP Basic authentication instead.
+type OAuthTokenRequest struct {
+    GrantType    string `form:"grant_type"`
+    Code         string `form:"code"`
+    RedirectURI  string `form:"redirect_uri"`
+    CodeVerifier string `form:"code_verifier"`
+    RefreshToken string `form:"refresh_token"`
+    Scope        string `form:"scope"`
+    ClientID     string `form:"client_id"`
+    ClientSecret string `form:"client_secret"`
+}
+
+// OAuthTokenFormRequest is the form body of the introspection and revocation endpoints.
+type OAuthTokenFormRequest struct {
+    Token         string `form:"token"`
+    TokenTypeHint string `form:"token_type_hint"`
+    ClientID      string `form:"client_id"`
+    ClientSecret  string `form:"client_secret"`
+}
+
+type OAuthTokenResponse struct {
+    AccessToken  string `json:"access_token"`
+    TokenType    string `json:"token_type"`
+    ExpiresIn    int64  `json:"expires_in"`
+    RefreshToken string `json:"refresh_token,omitempty"`
+    IDToken      string `json:"id_token,omitempty"`
+    Scope        string `json:"scope,omitempty"`
+}
+
+// IntrospectionResponse follows RFC 7662; inactive tokens only carry "active".
+type IntrospectionResponse struct {
+    Active    bool   `json:"active"`
+    Scope     string `json:"scope,omitempty"`
+    ClientID  string `json:"client_id,omitempty"`
+    Username  string `json:"username,omitempty"`
+    TokenType string `json:"token_type,omitempty"`
+    Exp       int64  `json:"exp,omitempty"`
+    Iat       int64  `json:"iat,omitempty"`
+    Sub       string `json:"sub,omitempty"`
+}
+
+// OAuthErrorResponse is the RFC 6749 error format.
+type OAuthErrorResponse struct {
+    Error            string `json:"error"`
+    ErrorDescription string `json:"error_description,omitempty"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+// OIDCDiscoveryResponse is the OpenID Provider Metadata document.
+type OIDCDiscoveryResponse struct {
+    Issuer                            string   `json:"issuer"`
+    AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+    TokenEndpoint                     string   `json:"token_endpoint"`
+    UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+    JWKSURI                           string   `json:"jwks_uri"`
+    IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+    RevocationEndpoint                string   `json:"revocation_endpoint"`
+    ScopesSupported                   []string `json:"scopes_supported"`
+    ResponseTypesSupported            []string `json:"response_types_supported"`
+    GrantTypesSupported               []string `json:"grant_types_supported"`
+    SubjectTypesSupported             []string `json:"subject_types_supported"`
+    IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+    TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+    CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+    ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+type JSONWebKeyResponse struct {
+    Kty string `json:"kty"`
+    Use string `json:"use"`
+    Alg string `json:"alg"`
+    Kid string `json:"kid"`
+    N   string `json:"n"`
+    E   string `json:"e"`
+}
+
+type JWKSResponse struct {
+    Keys []JSONWebKeyResponse `json:"keys"`
+}
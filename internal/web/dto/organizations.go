@@ -0,0 +1,18 @@
+package dto
+
+type CreateOrganizationRequest struct {
+    Name  string            `json:"name" validate:"required,min=2,max=100"`
+    Slug  string            `json:"slug" validate:"required,slug"`
+    Owner CreateUserRequest `json:"owner" validate:"required"`
+}
+
+type OrganizationResponse struct {
+    ID   uint   `json:"id"`
+    Name string `json:"name"`
+    Slug string `json:"slug"`
+}
+
+type CreateOrganizationResponse struct {
+    Organization OrganizationResponse `json:"organization"`
+    Owner        UserResponse         `json:"owner"`
+}
@@ -0,0 +1,175 @@
+package dto
+
+import "userHub/pkg/scim"
+
+// SCIM schema and message URNs (RFC 7643, RFC 7644).
+const (
+    SCIMUserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+    SCIMGroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+    SCIMUserExtensionSchema         = "urn:userhub:params:scim:schemas:extension:2.0:User"
+    SCIMServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+    SCIMResourceTypeSchema          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+    SCIMSchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+    SCIMListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+    SCIMPatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+    SCIMErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+type SCIMMeta struct {
+    ResourceType string `json:"resourceType"`
+    Created      string `json:"created,omitempty"`
+    LastModified string `json:"lastModified,omitempty"`
+    Location     string `json:"location,omitempty"`
+    Version      string `json:"version,omitempty"`
+}
+
+type SCIMName struct {
+    Formatted  string `json:"formatted,omitempty"`
+    GivenName  string `json:"givenName,omitempty"`
+    FamilyName string `json:"familyName,omitempty"`
+}
+
+type SCIMEmail struct {
+    Value   string `json:"value"`
+    Type    string `json:"type,omitempty"`
+    Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMUserExtension carries the userHub attributes that have no place in the
+// core User schema.
+type SCIMUserExtension struct {
+    Gender string `json:"gender,omitempty"`
+    Role   string `json:"role,omitempty"`
+}
+
+// SCIMUser is a userHub user as a SCIM User resource. userName is the email
+// address; Active is a pointer so requests can leave it out.
+type SCIMUser struct {
+    Schemas     []string           `json:"schemas"`
+    ID          string             `json:"id,omitempty"`
+    UserName    string             `json:"userName"`
+    Name        *SCIMName          `json:"name,omitempty"`
+    DisplayName string             `json:"displayName,omitempty"`
+    Emails      []SCIMEmail        `json:"emails,omitempty"`
+    Active      *bool              `json:"active,omitempty"`
+    Password    string             `json:"password,omitempty"`
+    Extension   *SCIMUserExtension `json:"urn:userhub:params:scim:schemas:extension:2.0:User,omitempty"`
+    Meta        *SCIMMeta          `json:"meta,omitempty"`
+}
+
+// SCIMUserFields are the user fields a SCIM User maps to, validated like
+// their counterparts in CreateUserRequest.
+type SCIMUserFields struct {
+    Name     string `validate:"required,min=2,max=50"`
+    Email    string `validate:"required,email"`
+    Gender   string `validate:"omitempty,gender"`
+    Role     string `validate:"omitempty,role"`
+    Password string `validate:"omitempty,password"`
+}
+
+type SCIMMember struct {
+    Value string `json:"value"`
+    Ref   string `json:"$ref,omitempty"`
+    Type  string `json:"type,omitempty"`
+}
+
+type SCIMGroup struct {
+    Schemas     []string     `json:"schemas"`
+    ID          string       `json:"id,omitempty"`
+    DisplayName string       `json:"displayName"`
+    Members     []SCIMMember `json:"members"`
+    Meta        *SCIMMeta    `json:"meta,omitempty"`
+}
+
+type SCIMPatchRequest struct {
+    Schemas    []string       `json:"schemas"`
+    Operations []scim.PatchOp `json:"Operations"`
+}
+
+type SCIMListResponse struct {
+    Schemas      []string `json:"schemas"`
+    TotalResults int64    `json:"totalResults"`
+    StartIndex   int      `json:"startIndex"`
+    ItemsPerPage int      `json:"itemsPerPage"`
+    Resources    []any    `json:"Resources"`
+}
+
+type SCIMErrorResponse struct {
+    Schemas  []string `json:"schemas"`
+    Status   string   `json:"status"`
+    ScimType string   `json:"scimType,omitempty"`
+    Detail   string   `json:"detail"`
+}
+
+type SCIMSupported struct {
+    Supported bool `json:"supported"`
+}
+
+type SCIMFilterSupport struct {
+    Supported  bool `json:"supported"`
+    MaxResults int  `json:"maxResults"`
+}
+
+type SCIMBulkSupport struct {
+    Supported      bool `json:"supported"`
+    MaxOperations  int  `json:"maxOperations"`
+    MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type SCIMAuthenticationScheme struct {
+    Type        string `json:"type"`
+    Name        string `json:"name"`
+    Description string `json:"description"`
+    Primary     bool   `json:"primary,omitempty"`
+}
+
+type SCIMServiceProviderConfig struct {
+    Schemas               []string                   `json:"schemas"`
+    Patch                 SCIMSupported              `json:"patch"`
+    Bulk                  SCIMBulkSupport            `json:"bulk"`
+    Filter                SCIMFilterSupport          `json:"filter"`
+    ChangePassword        SCIMSupported              `json:"changePassword"`
+    Sort                  SCIMSupported              `json:"sort"`
+    ETag                  SCIMSupported              `json:"etag"`
+    AuthenticationSchemes []SCIMAuthenticationScheme `json:"authenticationSchemes"`
+    Meta                  SCIMMeta                   `json:"meta"`
+}
+
+type SCIMSchemaExtension struct {
+    Schema   string `json:"schema"`
+    Required bool   `json:"required"`
+}
+
+type SCIMResourceType struct {
+    Schemas          []string              `json:"schemas"`
+    ID               string                `json:"id"`
+    Name             string                `json:"name"`
+    Endpoint         string                `json:"endpoint"`
+    Description      string                `json:"description"`
+    Schema           string                `json:"schema"`
+    SchemaExtensions []SCIMSchemaExtension `json:"schemaExtensions,omitempty"`
+    Meta             SCIMMeta              `json:"meta"`
+}
+
+type SCIMAttribute struct {
+    Name           string          `json:"name"`
+    Type           string          `json:"type"`
+    MultiValued    bool            `json:"multiValued"`
+    Description    string          `json:"description"`
+    Required       bool            `json:"required"`
+    CaseExact      bool            `json:"caseExact"`
+    Mutability     string          `json:"mutability"`
+    Returned       string          `json:"returned"`
+    Uniqueness     string          `json:"uniqueness"`
+    ReferenceTypes []string        `json:"referenceTypes,omitempty"`
+    SubAttributes  []SCIMAttribute `json:"subAttributes,omitempty"`
+}
+
+type SCIMSchema struct {
+    Schemas     []string        `json:"schemas"`
+    ID          string          `json:"id"`
+    Name        string          `json:"name"`
+    Description string          `json:"description"`
+    Attributes  []SCIMAttribute `json:"attributes"`
+    Meta        SCIMMeta        `json:"meta"`
+}
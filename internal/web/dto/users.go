@@ -1,30 +1,101 @@
 package dto
 
+import "time"
+
 // Transport-layer DTOs for HTTP requests/responses.
 
 type CreateUserRequest struct {
-    Name   string `json:"name" validate:"required,min=2,max=50"`
-    Email  string `json:"email" validate:"required,email"`
-    Gender string `json:"gender" validate:"required,gender"`
+    Name     string `json:"name" validate:"required,min=2,max=50"`
+    Email    string `json:"email" validate:"required,email"`
+    Gender   string `json:"gender" validate:"required,gender"`
+    Password string `json:"password" validate:"omitempty,password"`
 }
 
 type UpdateUserRequest struct {
     Name   *string `json:"name" validate:"omitempty,min=2,max=50"`
     Gender *string `json:"gender" validate:"omitempty,gender"`
+    Role   *string `json:"role" validate:"omitempty,role"`
+}
+
+// PatchUserDocument is a user's representation after a PATCH has been applied
+// to it. ID, Email, EmailVerified and the change metadata are read-only and
+// must come out of the patch unchanged.
+type PatchUserDocument struct {
+    ID     uint   `json:"id"`
+    Name   string `json:"name" validate:"required,min=2,max=50"`
+    Email  string `json:"email"`
+    Gender string `json:"gender" validate:"required,gender"`
+    Role   string `json:"role" validate:"required,role"`
+
+    EmailVerified bool `json:"email_verified"`
+
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+    CreatedBy uint      `json:"created_by"`
+    UpdatedBy uint      `json:"updated_by"`
 }
 
+type ChangePasswordRequest struct {
+    CurrentPassword string `json:"current_password"`
+    NewPassword     string `json:"new_password" validate:"required,password"`
+}
+
+// UserResponse deliberately has no credential fields. CreatedBy and
+// UpdatedBy are omitted when no signed-in user made the change.
 type UserResponse struct {
     ID     uint   `json:"id"`
     Name   string `json:"name"`
     Email  string `json:"email"`
     Gender string `json:"gender"`
+    Role   string `json:"role"`
+
+    EmailVerified bool `json:"email_verified"`
+
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+    CreatedBy uint      `json:"created_by,omitempty"`
+    UpdatedBy uint      `json:"updated_by,omitempty"`
+}
+
+type PurgeUsersResponse struct {
+    Purged int64 `json:"purged"`
 }
 
+// ListUsersResponse pages either by page number, with page and total set,
+// or by cursor, with next and prev set unless that end has been reached.
 type ListUsersResponse struct {
     Data []UserResponse `json:"data"`
     Meta struct {
-        Page  int   `json:"page"`
-        Limit int   `json:"limit"`
-        Total int64 `json:"total"`
+        Page  int    `json:"page,omitempty"`
+        Limit int    `json:"limit"`
+        Total *int64 `json:"total,omitempty"`
+        Next  string `json:"next,omitempty"`
+        Prev  string `json:"prev,omitempty"`
+    } `json:"meta"`
+}
+
+// UserSearchResponse lists the users matching a search, best match first.
+type UserSearchResponse struct {
+    Data []UserSearchHitResponse `json:"data"`
+    Meta struct {
+        Query string `json:"query"`
     } `json:"meta"`
 }
+
+// UserSearchHitResponse is a user matching a search. Highlights repeats the
+// fields that matched, HTML-escaped, with the matching words in <em> tags.
+type UserSearchHitResponse struct {
+    User       UserResponse      `json:"user"`
+    Score      float64           `json:"score"`
+    Highlights map[string]string `json:"highlights"`
+}
+
+type EmailChangeRequest struct {
+    Email string `json:"email" validate:"required,email"`
+}
+
+// ConfirmEmailTokenRequest carries a token mailed by the email change or
+// verification flow.
+type ConfirmEmailTokenRequest struct {
+    Token string `json:"token" validate:"required"`
+}
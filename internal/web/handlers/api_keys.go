@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler holds dependencies for API key HTTP handlers
+type APIKeyHandler struct {
+	keys domain.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(keys domain.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys}
+}
+
+// CreateAPIKey handles POST /users/:id/api-keys
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	scopes := make([]domain.Scope, 0, len(req.Scopes))
+	for _, s := range req.Scopes {
+		scopes = append(scopes, domain.Scope(s))
+	}
+
+	key, raw, err := h.keys.Create(c.Request.Context(), uint(id), req.Name, scopes, req.ExpiresAt)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusCreated, dto.CreatedAPIKeyResponse{APIKeyResponse: toAPIKeyResponse(key), Key: raw})
+}
+
+// ListAPIKeys handles GET /users/:id/api-keys
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	keys, err := h.keys.List(c.Request.Context(), uint(id))
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := dto.ListAPIKeysResponse{Data: make([]dto.APIKeyResponse, 0, len(keys))}
+	for i := range keys {
+		resp.Data = append(resp.Data, toAPIKeyResponse(&keys[i]))
+	}
+	Success(c, http.StatusOK, resp)
+}
+
+// RevokeAPIKey handles DELETE /users/:id/api-keys/:keyId
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+	keyID, err := strconv.ParseUint(c.Param("keyId"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid API key ID", nil)
+		return
+	}
+
+	if err := h.keys.Revoke(c.Request.Context(), uint(id), uint(keyID)); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toAPIKeyResponse(k *domain.APIKey) dto.APIKeyResponse {
+	scopes := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, string(s))
+	}
+	return dto.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler holds dependencies for audit log HTTP handlers
+type AuditHandler struct {
+	auditService domain.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(svc domain.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: svc}
+}
+
+// ListAuditEvents handles GET /audit?actor_id=&impersonator_id=&target_id=&action=&from=&to=&page=&limit=
+func (h *AuditHandler) ListAuditEvents(c *gin.Context) {
+	filter, fields := parseAuditFilter(c)
+	if len(fields) > 0 {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid audit filter", fields)
+		return
+	}
+	h.list(c, filter)
+}
+
+// ListUserAuditEvents handles GET /users/:id/audit
+func (h *AuditHandler) ListUserAuditEvents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	filter, fields := parseAuditFilter(c)
+	if len(fields) > 0 {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid audit filter", fields)
+		return
+	}
+	filter.TargetID = uint(id)
+	h.list(c, filter)
+}
+
+func (h *AuditHandler) list(c *gin.Context, filter domain.AuditFilter) {
+	events, total, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := dto.ListAuditEventsResponse{}
+	resp.Data = make([]dto.AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		resp.Data = append(resp.Data, toAuditEventResponse(e))
+	}
+	resp.Meta.Page = filter.Page
+	resp.Meta.Limit = filter.Limit
+	resp.Meta.Total = total
+
+	Success(c, http.StatusOK, resp)
+}
+
+// parseAuditFilter reads the filter query parameters, collecting errors per field.
+func parseAuditFilter(c *gin.Context) (domain.AuditFilter, map[string]string) {
+	fields := make(map[string]string)
+	filter := domain.AuditFilter{Action: domain.AuditAction(c.Query("action"))}
+
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	parseID := func(name string) uint {
+		v := c.Query(name)
+		if v == "" {
+			return 0
+		}
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			fields[name] = "must be a positive integer"
+		}
+		return uint(id)
+	}
+	filter.ActorID = parseID("actor_id")
+	filter.ImpersonatorID = parseID("impersonator_id")
+	filter.TargetID = parseID("target_id")
+
+	parseTime := func(name string) time.Time {
+		v := c.Query(name)
+		if v == "" {
+			return time.Time{}
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			fields[name] = "must be an RFC 3339 timestamp"
+		}
+		return t
+	}
+	filter.From = parseTime("from")
+	filter.To = parseTime("to")
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+
+	return filter, fields
+}
+
+func toAuditEventResponse(e *domain.AuditEvent) dto.AuditEventResponse {
+	resp := dto.AuditEventResponse{
+		ID:        e.ID,
+		ActorID:   e.ActorID,
+		TargetID:  e.TargetID,
+		Action:    string(e.Action),
+		RequestID: e.RequestID,
+		ClientIP:  e.ClientIP,
+		CreatedAt: e.CreatedAt,
+	}
+	resp.ImpersonatorID = e.ImpersonatorID
+	if len(e.Changes) > 0 {
+		resp.Changes = make(map[string]dto.FieldChangeResponse, len(e.Changes))
+		for field, ch := range e.Changes {
+			resp.Changes[field] = dto.FieldChangeResponse{From: ch.From, To: ch.To}
+		}
+	}
+	return resp
+}
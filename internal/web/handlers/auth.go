@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthHandler holds dependencies for authentication HTTP handlers
+type AuthHandler struct {
+	authService domain.AuthService
+	resets      domain.PasswordResetService
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(svc domain.AuthService, resets domain.PasswordResetService) *AuthHandler {
+	return &AuthHandler{authService: svc, resets: resets}
+}
+
+// Login handles POST /auth/login
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	result, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	if result.Challenge != nil {
+		Success(c, http.StatusOK, dto.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    result.Challenge.Token,
+			ExpiresIn:   int64(time.Until(result.Challenge.ExpiresAt).Seconds()),
+		})
+		return
+	}
+	Success(c, http.StatusOK, toTokenResponse(result.Token))
+}
+
+// VerifyMFA handles POST /auth/mfa/verify, the second step of a challenged login.
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req dto.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	token, err := h.authService.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toTokenResponse(token))
+}
+
+// Refresh handles POST /auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	token, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toTokenResponse(token))
+}
+
+// Logout handles POST /auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword handles POST /auth/password/forgot. The response is the
+// same whether or not the email belongs to an account.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	if err := h.resets.Forgot(c.Request.Context(), req.Email); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword handles POST /auth/password/reset
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	if err := h.resets.Reset(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeSessions handles DELETE /users/:id/sessions
+func (h *AuthHandler) RevokeSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	if err := h.authService.RevokeAllSessions(c.Request.Context(), uint(id)); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toTokenResponse(t *domain.AuthToken) dto.TokenResponse {
+	return dto.TokenResponse{
+		AccessToken:  t.AccessToken,
+		TokenType:    t.TokenType,
+		ExpiresIn:    int64(time.Until(t.ExpiresAt).Seconds()),
+		RefreshToken: t.RefreshToken,
+	}
+}
+
+// currentPrincipal returns the authenticated caller set by the auth middleware.
+func currentPrincipal(c *gin.Context) (*domain.Principal, bool) {
+	return domain.PrincipalFromContext(c.Request.Context())
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailHandler holds dependencies for email address HTTP handlers
+type EmailHandler struct {
+	changes       domain.EmailChangeService
+	verifications domain.EmailVerificationService
+}
+
+// NewEmailHandler creates a new EmailHandler
+func NewEmailHandler(changes domain.EmailChangeService, verifications domain.EmailVerificationService) *EmailHandler {
+	return &EmailHandler{changes: changes, verifications: verifications}
+}
+
+// RequestEmailChange handles POST /users/:id/email
+func (h *EmailHandler) RequestEmailChange(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	var req dto.EmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	if err := h.changes.Request(c.Request.Context(), uint(id), req.Email); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ConfirmEmailChange handles POST /users/email/confirm
+func (h *EmailHandler) ConfirmEmailChange(c *gin.Context) {
+	var req dto.ConfirmEmailTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	user, err := h.changes.Confirm(c.Request.Context(), req.Token)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	setUserETag(c, user)
+	Success(c, http.StatusOK, toUserResponse(user))
+}
+
+// ResendVerification handles POST /users/:id/verification
+func (h *EmailHandler) ResendVerification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	if err := h.verifications.Resend(c.Request.Context(), uint(id)); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ConfirmVerification handles POST /users/verification/confirm
+func (h *EmailHandler) ConfirmVerification(c *gin.Context) {
+	var req dto.ConfirmEmailTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	user, err := h.verifications.Confirm(c.Request.Context(), req.Token)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	setUserETag(c, user)
+	Success(c, http.StatusOK, toUserResponse(user))
+}
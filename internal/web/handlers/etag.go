@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"userHub/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userETag renders the user's version as a strong entity tag.
+func userETag(u *domain.User) string {
+	return `"` + strconv.FormatUint(uint64(u.Version), 10) + `"`
+}
+
+// setUserETag exposes the current version so clients can send it back in If-Match.
+func setUserETag(c *gin.Context, u *domain.User) {
+	c.Header("ETag", userETag(u))
+}
+
+// ifMatchVersion reads the If-Match header and returns the version it names.
+// A version of 0 means the write is unconditional: the header was absent and
+// not required, or it was "*". ok is false once a response has been written.
+func ifMatchVersion(c *gin.Context, required bool) (version uint, ok bool) {
+	version, err := parseIfMatch(c, required)
+	if err != nil {
+		FailFromError(c, err)
+		return 0, false
+	}
+	return version, true
+}
+
+// parseIfMatch is ifMatchVersion without the response, for handlers that
+// report errors in their own format.
+func parseIfMatch(c *gin.Context, required bool) (uint, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		if required {
+			return 0, domain.NewPreconditionRequired("If-Match header is required")
+		}
+		return 0, nil
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	// Only strong tags can match; a weak or malformed tag never does.
+	tag := strings.TrimSpace(strings.Split(header, ",")[0])
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, domain.NewPreconditionFailed("If-Match does not match the current version")
+	}
+	v, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 32)
+	if err != nil || v == 0 {
+		return 0, domain.NewPreconditionFailed("If-Match does not match the current version")
+	}
+	return uint(v), nil
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupHandler holds dependencies for group HTTP handlers
+type GroupHandler struct {
+	groups domain.GroupService
+}
+
+// NewGroupHandler creates a new GroupHandler
+func NewGroupHandler(groups domain.GroupService) *GroupHandler {
+	return &GroupHandler{groups: groups}
+}
+
+// CreateGroup handles POST /groups
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	var req dto.CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	group, err := h.groups.Create(c.Request.Context(), &domain.Group{
+		Name:        req.Name,
+		Role:        domain.Role(req.Role),
+		MemberIDs:   req.MemberIDs,
+		SubgroupIDs: req.SubgroupIDs,
+	})
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusCreated, toGroupResponse(group))
+}
+
+// GetGroup handles GET /groups/:id
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	id, ok := groupID(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.groups.GetByID(c.Request.Context(), id)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toGroupResponse(group))
+}
+
+// ListGroups handles GET /groups
+func (h *GroupHandler) ListGroups(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	groups, total, err := h.groups.List(c.Request.Context(), page, limit)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := dto.ListGroupsResponse{}
+	resp.Data = make([]dto.GroupResponse, 0, len(groups))
+	for _, g := range groups {
+		resp.Data = append(resp.Data, toGroupResponse(g))
+	}
+	resp.Meta.Page = page
+	resp.Meta.Limit = limit
+	resp.Meta.Total = total
+
+	Success(c, http.StatusOK, resp)
+}
+
+// UpdateGroup handles PUT /groups/:id
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	id, ok := groupID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	group, err := h.groups.GetByID(c.Request.Context(), id)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+	if req.Name != nil {
+		group.Name = *req.Name
+	}
+	if req.Role != nil {
+		group.Role = domain.Role(*req.Role)
+	}
+
+	updated, err := h.groups.Update(c.Request.Context(), group)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toGroupResponse(updated))
+}
+
+// DeleteGroup handles DELETE /groups/:id
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	id, ok := groupID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.groups.Delete(c.Request.Context(), id); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddMembers handles POST /groups/:id/members
+func (h *GroupHandler) AddMembers(c *gin.Context) {
+	h.changeMembers(c, h.groups.AddMembers)
+}
+
+// RemoveMembers handles DELETE /groups/:id/members
+func (h *GroupHandler) RemoveMembers(c *gin.Context) {
+	h.changeMembers(c, h.groups.RemoveMembers)
+}
+
+func (h *GroupHandler) changeMembers(c *gin.Context, change func(ctx context.Context, id uint, userIDs, subgroupIDs []uint) (*domain.Group, error)) {
+	id, ok := groupID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.GroupMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if len(req.UserIDs) == 0 && len(req.SubgroupIDs) == 0 {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", map[string]string{"user_ids": "at least one user or subgroup is required"})
+		return
+	}
+
+	group, err := change(c.Request.Context(), id, req.UserIDs, req.SubgroupIDs)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toGroupResponse(group))
+}
+
+// ListUserGroups handles GET /users/:id/groups
+func (h *GroupHandler) ListUserGroups(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	memberships, role, err := h.groups.ListForUser(c.Request.Context(), uint(id))
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := dto.ListUserGroupsResponse{
+		Data:          make([]dto.UserGroupResponse, 0, len(memberships)),
+		EffectiveRole: string(role),
+	}
+	for _, m := range memberships {
+		resp.Data = append(resp.Data, dto.UserGroupResponse{GroupResponse: toGroupResponse(m.Group), Direct: m.Direct})
+	}
+
+	Success(c, http.StatusOK, resp)
+}
+
+func groupID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid group ID", nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func toGroupResponse(g *domain.Group) dto.GroupResponse {
+	return dto.GroupResponse{
+		ID:          g.ID,
+		Name:        g.Name,
+		Role:        string(g.Role),
+		MemberIDs:   append([]uint{}, g.MemberIDs...),
+		SubgroupIDs: append([]uint{}, g.SubgroupIDs...),
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+	}
+}
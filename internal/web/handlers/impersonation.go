@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler holds dependencies for impersonation HTTP handlers
+type ImpersonationHandler struct {
+	impersonation domain.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new ImpersonationHandler
+func NewImpersonationHandler(impersonation domain.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonation: impersonation}
+}
+
+// StartImpersonation handles POST /users/:id/impersonate
+func (h *ImpersonationHandler) StartImpersonation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	var req dto.StartImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	imp, token, err := h.impersonation.Start(c.Request.Context(), uint(id), req.Reason)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusCreated, dto.StartImpersonationResponse{
+		ImpersonationResponse: toImpersonationResponse(imp),
+		Token:                 toTokenResponse(token),
+	})
+}
+
+// EndImpersonation handles POST /impersonation/end with the impersonation
+// token itself, which stops working afterwards.
+func (h *ImpersonationHandler) EndImpersonation(c *gin.Context) {
+	imp, err := h.impersonation.End(c.Request.Context())
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toImpersonationResponse(imp))
+}
+
+func toImpersonationResponse(imp *domain.Impersonation) dto.ImpersonationResponse {
+	return dto.ImpersonationResponse{
+		ID:             imp.ID,
+		UserID:         imp.UserID,
+		ImpersonatorID: imp.ImpersonatorID,
+		Reason:         imp.Reason,
+		ExpiresAt:      imp.ExpiresAt,
+		CreatedAt:      imp.CreatedAt,
+		EndedAt:        imp.EndedAt,
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvitationHandler holds dependencies for invitation HTTP handlers
+type InvitationHandler struct {
+	invitations domain.InvitationService
+	passwords   domain.PasswordService
+}
+
+// NewInvitationHandler creates a new InvitationHandler
+func NewInvitationHandler(invitations domain.InvitationService, passwords domain.PasswordService) *InvitationHandler {
+	return &InvitationHandler{invitations: invitations, passwords: passwords}
+}
+
+// CreateInvitation handles POST /invitations
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req dto.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	inv, err := h.invitations.Create(c.Request.Context(), req.Email, domain.Role(req.Role))
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusCreated, toInvitationResponse(inv))
+}
+
+// ListInvitations handles GET /invitations
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	invitations, total, err := h.invitations.List(c.Request.Context(), page, limit)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := dto.ListInvitationsResponse{}
+	resp.Data = make([]dto.InvitationResponse, 0, len(invitations))
+	for _, inv := range invitations {
+		resp.Data = append(resp.Data, toInvitationResponse(inv))
+	}
+	resp.Meta.Page = page
+	resp.Meta.Limit = limit
+	resp.Meta.Total = total
+
+	Success(c, http.StatusOK, resp)
+}
+
+// RevokeInvitation handles POST /invitations/:id/revoke
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	id, ok := invitationID(c)
+	if !ok {
+		return
+	}
+
+	inv, err := h.invitations.Revoke(c.Request.Context(), id)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toInvitationResponse(inv))
+}
+
+// ResendInvitation handles POST /invitations/:id/resend
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	id, ok := invitationID(c)
+	if !ok {
+		return
+	}
+
+	inv, err := h.invitations.Resend(c.Request.Context(), id)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toInvitationResponse(inv))
+}
+
+// AcceptInvitation handles POST /invitations/accept
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var req dto.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	cred, err := h.passwords.NewCredential(req.Password)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	user, err := h.invitations.Accept(c.Request.Context(), req.Token, &domain.User{
+		Name:       req.Name,
+		Gender:     req.Gender,
+		Credential: cred,
+	})
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusCreated, toUserResponse(user))
+}
+
+func invitationID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid invitation ID", nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func toInvitationResponse(inv *domain.Invitation) dto.InvitationResponse {
+	return dto.InvitationResponse{
+		ID:         inv.ID,
+		Email:      inv.Email,
+		Role:       string(inv.Role),
+		Status:     string(inv.StatusAt(time.Now())),
+		InviterID:  inv.InviterID,
+		UserID:     inv.UserID,
+		ExpiresAt:  inv.ExpiresAt,
+		CreatedAt:  inv.CreatedAt,
+		AcceptedAt: inv.AcceptedAt,
+		RevokedAt:  inv.RevokedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler holds dependencies for multi-factor authentication HTTP handlers
+type MFAHandler struct {
+	mfa domain.MFAService
+}
+
+// NewMFAHandler creates a new MFAHandler
+func NewMFAHandler(mfa domain.MFAService) *MFAHandler {
+	return &MFAHandler{mfa: mfa}
+}
+
+// EnrollTOTP handles POST /auth/mfa/totp
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	enrollment, err := h.mfa.Enroll(c.Request.Context())
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, dto.TOTPEnrollmentResponse{Secret: enrollment.Secret, URI: enrollment.URI})
+}
+
+// ConfirmTOTP handles POST /auth/mfa/totp/confirm
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	req, ok := bindMFACode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfa.Confirm(c.Request.Context(), req.Code)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA handles POST /auth/mfa/disable
+func (h *MFAHandler) DisableMFA(c *gin.Context) {
+	req, ok := bindMFACode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mfa.Disable(c.Request.Context(), req.Code); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes handles POST /auth/mfa/recovery-codes
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	req, ok := bindMFACode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfa.RegenerateRecoveryCodes(c.Request.Context(), req.Code)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// GetPolicy handles GET /auth/mfa/policy
+func (h *MFAHandler) GetPolicy(c *gin.Context) {
+	roles, err := h.mfa.Policy(c.Request.Context())
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toMFAPolicyResponse(roles))
+}
+
+// UpdatePolicy handles PUT /auth/mfa/policy
+func (h *MFAHandler) UpdatePolicy(c *gin.Context) {
+	var req dto.MFAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	roles := make([]domain.Role, 0, len(req.Roles))
+	for _, r := range req.Roles {
+		roles = append(roles, domain.Role(r))
+	}
+
+	updated, err := h.mfa.SetPolicy(c.Request.Context(), roles)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toMFAPolicyResponse(updated))
+}
+
+func bindMFACode(c *gin.Context) (dto.MFACodeRequest, bool) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return req, false
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return req, false
+	}
+	return req, true
+}
+
+func toMFAPolicyResponse(roles []domain.Role) dto.MFAPolicyResponse {
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, string(r))
+	}
+	return dto.MFAPolicyResponse{Roles: names}
+}
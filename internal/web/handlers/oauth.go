@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthHandler holds dependencies for the OAuth 2.0 authorization server
+type OAuthHandler struct {
+	oauth domain.OAuthService
+	auth  domain.AuthService
+}
+
+// NewOAuthHandler creates a new OAuthHandler. auth signs in the browsers
+// that reach the authorization endpoint without an access token.
+func NewOAuthHandler(oauth domain.OAuthService, auth domain.AuthService) *OAuthHandler {
+	return &OAuthHandler{oauth: oauth, auth: auth}
+}
+
+// RegisterClient handles POST /oauth/clients
+func (h *OAuthHandler) RegisterClient(c *gin.Context) {
+	var req dto.RegisterOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	client, secret, err := h.oauth.RegisterClient(c.Request.Context(), &domain.OAuthClient{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		GrantTypes:   req.GrantTypes,
+		Scopes:       req.Scopes,
+	}, req.Public)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusCreated, dto.RegisteredOAuthClientResponse{OAuthClientResponse: toOAuthClientResponse(client), ClientSecret: secret})
+}
+
+// ListClients handles GET /oauth/clients
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	clients, err := h.oauth.ListClients(c.Request.Context())
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := dto.ListOAuthClientsResponse{Data: make([]dto.OAuthClientResponse, 0, len(clients))}
+	for i := range clients {
+		resp.Data = append(resp.Data, toOAuthClientResponse(&clients[i]))
+	}
+	Success(c, http.StatusOK, resp)
+}
+
+// DeleteClient handles DELETE /oauth/clients/:clientId
+func (h *OAuthHandler) DeleteClient(c *gin.Context) {
+	if err := h.oauth.DeleteClient(c.Request.Context(), c.Param("clientId")); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Authorize handles GET /oauth/authorize. A caller with an access token
+// consents implicitly and is redirected back with a code at once; browsers
+// are shown a login or consent page instead (see AuthorizeForm).
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	req := authorizeRequest(c.Query)
+	if _, ok := domain.PrincipalFromContext(c.Request.Context()); !ok {
+		h.promptAuthorize(c, req)
+		return
+	}
+
+	code, err := h.oauth.Authorize(c.Request.Context(), req)
+	redirectAuthorize(c, req, code, err)
+}
+
+// authorizeRequest reads the authorization request parameters with get,
+// from either the query or a posted form.
+func authorizeRequest(get func(string) string) domain.AuthorizeRequest {
+	return domain.AuthorizeRequest{
+		ClientID:            get("client_id"),
+		RedirectURI:         get("redirect_uri"),
+		ResponseType:        get("response_type"),
+		Scope:               get("scope"),
+		CodeChallenge:       get("code_challenge"),
+		CodeChallengeMethod: get("code_challenge_method"),
+		Nonce:               get("nonce"),
+		State:               get("state"),
+	}
+}
+
+// redirectAuthorize sends the user back to the client with code, or with
+// err if the client can act on it. Other errors are answered here: the
+// client or redirect URI is not trusted, so never redirect.
+func redirectAuthorize(c *gin.Context, req domain.AuthorizeRequest, code string, err error) {
+	params := url.Values{}
+	var oauthErr *domain.OAuthError
+	switch {
+	case errors.As(err, &oauthErr):
+		params.Set("error", oauthErr.Code)
+		params.Set("error_description", oauthErr.Description)
+	case err != nil:
+		FailFromError(c, err)
+		return
+	default:
+		params.Set("code", code)
+	}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+
+	target, _ := url.Parse(req.RedirectURI)
+	query := target.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	target.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
+
+// Token handles POST /oauth/token
+func (h *OAuthHandler) Token(c *gin.Context) {
+	noStore(c)
+
+	var req dto.OAuthTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		oauthFail(c, domain.NewOAuthError(domain.OAuthInvalidRequest, "invalid form body"))
+		return
+	}
+
+	set, err := h.oauth.Token(c.Request.Context(), domain.TokenRequest{
+		Client:       clientCredentials(c, req.ClientID, req.ClientSecret),
+		GrantType:    req.GrantType,
+		Code:         req.Code,
+		RedirectURI:  req.RedirectURI,
+		CodeVerifier: req.CodeVerifier,
+		RefreshToken: req.RefreshToken,
+		Scope:        req.Scope,
+	})
+	if err != nil {
+		oauthFail(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, dto.OAuthTokenResponse{
+		AccessToken:  set.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(set.ExpiresAt).Seconds()),
+		RefreshToken: set.RefreshToken,
+		IDToken:      set.IDToken,
+		Scope:        set.Scope,
+	})
+}
+
+// Introspect handles POST /oauth/introspect (RFC 7662)
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	noStore(c)
+
+	var req dto.OAuthTokenFormRequest
+	if err := c.ShouldBind(&req); err != nil || req.Token == "" {
+		oauthFail(c, domain.NewOAuthError(domain.OAuthInvalidRequest, "token is required"))
+		return
+	}
+
+	info, err := h.oauth.Introspect(c.Request.Context(), clientCredentials(c, req.ClientID, req.ClientSecret), req.Token)
+	if err != nil {
+		oauthFail(c, err)
+		return
+	}
+
+	resp := dto.IntrospectionResponse{Active: info.Active}
+	if info.Active {
+		resp.Scope = info.Scope
+		resp.ClientID = info.ClientID
+		resp.Username = info.Username
+		resp.Exp = info.ExpiresAt.Unix()
+		resp.Iat = info.IssuedAt.Unix()
+		resp.TokenType = "Bearer"
+		if info.Kind == domain.OAuthRefreshToken {
+			resp.TokenType = "refresh_token"
+		}
+		if info.UserID != 0 {
+			resp.Sub = strconv.FormatUint(uint64(info.UserID), 10)
+		}
+	}
+	Success(c, http.StatusOK, resp)
+}
+
+// Revoke handles POST /oauth/revoke (RFC 7009)
+func (h *OAuthHandler) Revoke(c *gin.Context) {
+	var req dto.OAuthTokenFormRequest
+	if err := c.ShouldBind(&req); err != nil || req.Token == "" {
+		oauthFail(c, domain.NewOAuthError(domain.OAuthInvalidRequest, "token is required"))
+		return
+	}
+
+	if err := h.oauth.Revoke(c.Request.Context(), clientCredentials(c, req.ClientID, req.ClientSecret), req.Token); err != nil {
+		oauthFail(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// clientCredentials prefers HTTP Basic authentication over form parameters.
+func clientCredentials(c *gin.Context, formID, formSecret string) domain.ClientCredentials {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		// RFC 6749 section 2.3.1 form-encodes both parts.
+		if decoded, err := url.QueryUnescape(id); err == nil {
+			id = decoded
+		}
+		if decoded, err := url.QueryUnescape(secret); err == nil {
+			secret = decoded
+		}
+		return domain.ClientCredentials{ClientID: id, ClientSecret: secret}
+	}
+	return domain.ClientCredentials{ClientID: formID, ClientSecret: formSecret}
+}
+
+// oauthFail writes err in the RFC 6749 error format.
+func oauthFail(c *gin.Context, err error) {
+	var oauthErr *domain.OAuthError
+	if !errors.As(err, &oauthErr) {
+		log.Printf("oauth: %v", err)
+		c.JSON(http.StatusInternalServerError, dto.OAuthErrorResponse{Error: domain.OAuthServerError})
+		return
+	}
+
+	status := http.StatusBadRequest
+	switch oauthErr.Code {
+	case domain.OAuthInvalidClient:
+		c.Header("WWW-Authenticate", `Basic realm="userhub"`)
+		status = http.StatusUnauthorized
+	case domain.OAuthInvalidToken:
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		status = http.StatusUnauthorized
+	case domain.OAuthInsufficientScope:
+		c.Header("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		status = http.StatusForbidden
+	}
+	c.JSON(status, dto.OAuthErrorResponse{Error: oauthErr.Code, ErrorDescription: oauthErr.Description})
+}
+
+// noStore keeps tokens out of caches (RFC 6749 section 5.1).
+func noStore(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+}
+
+func toOAuthClientResponse(client *domain.OAuthClient) dto.OAuthClientResponse {
+	return dto.OAuthClientResponse{
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectURIs,
+		GrantTypes:   client.GrantTypes,
+		Scopes:       client.Scopes,
+		Public:       !client.Confidential(),
+		CreatedAt:    client.CreatedAt,
+	}
+}
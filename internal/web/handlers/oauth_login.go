@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"userHub/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// OAuthSessionCookie holds the access token of a user who signed in on
+	// the authorization page, so they need not sign in again for every client.
+	OAuthSessionCookie = "userhub_oauth_session"
+	// oauthCSRFCookie is compared with the csrf field of every posted form.
+	oauthCSRFCookie = "userhub_oauth_csrf"
+	oauthCookiePath = "/oauth"
+)
+
+// authorizePage asks a browser to sign in, enter a second factor or
+// consent. The authorization request travels along in hidden fields.
+var authorizePage = template.Must(template.New("authorize").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>{{.Client}}</title></head>
+<body>
+<h1>{{if .Consent}}Authorize {{.Client}}{{else}}Sign in to continue to {{.Client}}{{end}}</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+<form method="post" action="authorize">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<input type="hidden" name="csrf" value="{{.CSRF}}">
+{{if .Consent}}<p>{{.Client}} asks for access to your account:</p>
+<ul>{{range .Scopes}}<li>{{.}}</li>{{end}}</ul>
+<button type="submit" name="action" value="approve">Allow</button>
+<button type="submit" name="action" value="deny">Deny</button>
+{{else if .MFAToken}}<input type="hidden" name="mfa_token" value="{{.MFAToken}}">
+<label>Authentication or recovery code <input name="code" autocomplete="one-time-code" required autofocus></label>
+<button type="submit" name="action" value="mfa">Verify</button>
+{{else}}<label>Email <input type="email" name="email" value="{{.Email}}" autocomplete="username" required autofocus></label>
+<label>Password <input type="password" name="password" autocomplete="current-password" required></label>
+<button type="submit" name="action" value="login">Sign in</button>
+{{end}}</form>
+</body>
+</html>
+`))
+
+type authorizePageData struct {
+	Client   string
+	Params   map[string]string
+	CSRF     string
+	Consent  bool
+	Scopes   []string
+	MFAToken string
+	Email    string
+	Error    string
+}
+
+// AuthorizeForm handles POST /oauth/authorize, which the pages shown by
+// Authorize submit: action is login, mfa, approve or deny.
+func (h *OAuthHandler) AuthorizeForm(c *gin.Context) {
+	req := authorizeRequest(c.PostForm)
+	ctx := c.Request.Context()
+	client, scope, err := h.oauth.CheckAuthorize(ctx, req)
+	if err != nil {
+		redirectAuthorize(c, req, "", err)
+		return
+	}
+
+	page := authorizePageData{Client: client.Name, Email: c.PostForm("email")}
+	if !h.validCSRF(c) {
+		page.Error = "Your session expired. Please try again."
+		h.renderAuthorize(c, http.StatusForbidden, req, page)
+		return
+	}
+
+	// Sign-ins happen in the client's organization.
+	ctx = domain.WithTenant(ctx, client.TenantID)
+	var token *domain.AuthToken
+	switch c.PostForm("action") {
+	case "login":
+		result, err := h.auth.Login(ctx, c.PostForm("email"), c.PostForm("password"))
+		if err != nil {
+			page.Error = "Incorrect email or password."
+			h.renderAuthorize(c, http.StatusUnauthorized, req, page)
+			return
+		}
+		if result.Challenge != nil {
+			page.MFAToken = result.Challenge.Token
+			h.renderAuthorize(c, http.StatusOK, req, page)
+			return
+		}
+		token = result.Token
+	case "mfa":
+		token, err = h.auth.VerifyMFA(ctx, c.PostForm("mfa_token"), c.PostForm("code"))
+		if err != nil {
+			page.MFAToken = c.PostForm("mfa_token")
+			page.Error = "Incorrect code."
+			h.renderAuthorize(c, http.StatusUnauthorized, req, page)
+			return
+		}
+	case "approve", "deny":
+		p := h.browserPrincipal(c, client)
+		if p == nil {
+			page.Error = "Please sign in again."
+			h.renderAuthorize(c, http.StatusUnauthorized, req, page)
+			return
+		}
+		if c.PostForm("action") == "deny" {
+			redirectAuthorize(c, req, "", domain.NewOAuthError(domain.OAuthAccessDenied, "the user denied the request"))
+			return
+		}
+		code, err := h.oauth.Authorize(domain.WithPrincipal(ctx, p), req)
+		redirectAuthorize(c, req, code, err)
+		return
+	default:
+		h.renderAuthorize(c, http.StatusBadRequest, req, page)
+		return
+	}
+
+	// The page only needs the access token; the refresh token is revoked.
+	if token.RefreshToken != "" {
+		if err := h.auth.Logout(ctx, token.RefreshToken); err != nil {
+			log.Printf("oauth: revoke login refresh token: %v", err)
+		}
+	}
+	setOAuthCookie(c, OAuthSessionCookie, token.AccessToken, time.Until(token.ExpiresAt))
+	page.Consent = true
+	page.Scopes = strings.Fields(scope)
+	h.renderAuthorize(c, http.StatusOK, req, page)
+}
+
+// promptAuthorize shows a browser without an access token the consent page
+// if it signed in here before, and the login page otherwise.
+func (h *OAuthHandler) promptAuthorize(c *gin.Context, req domain.AuthorizeRequest) {
+	client, scope, err := h.oauth.CheckAuthorize(c.Request.Context(), req)
+	if err != nil {
+		redirectAuthorize(c, req, "", err)
+		return
+	}
+
+	page := authorizePageData{Client: client.Name}
+	if h.browserPrincipal(c, client) != nil {
+		page.Consent = true
+		page.Scopes = strings.Fields(scope)
+	}
+	h.renderAuthorize(c, http.StatusOK, req, page)
+}
+
+// browserPrincipal returns the user signed in by the session cookie, if
+// they belong to the client's organization.
+func (h *OAuthHandler) browserPrincipal(c *gin.Context, client *domain.OAuthClient) *domain.Principal {
+	token, err := c.Cookie(OAuthSessionCookie)
+	if err != nil || token == "" {
+		return nil
+	}
+	p, err := h.auth.Authenticate(domain.WithTenant(c.Request.Context(), client.TenantID), token)
+	if err != nil || p.TenantID != client.TenantID {
+		return nil
+	}
+	return p
+}
+
+func (h *OAuthHandler) renderAuthorize(c *gin.Context, status int, req domain.AuthorizeRequest, page authorizePageData) {
+	page.Params = map[string]string{
+		"client_id":             req.ClientID,
+		"redirect_uri":          req.RedirectURI,
+		"response_type":         req.ResponseType,
+		"scope":                 req.Scope,
+		"code_challenge":        req.CodeChallenge,
+		"code_challenge_method": req.CodeChallengeMethod,
+		"nonce":                 req.Nonce,
+		"state":                 req.State,
+	}
+	page.CSRF = h.csrfToken(c)
+
+	var buf bytes.Buffer
+	if err := authorizePage.Execute(&buf, page); err != nil {
+		FailFromError(c, domain.NewInternal("failed to render page"))
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	// A framed consent page could be clickjacked into approving a client.
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// csrfToken returns the browser's CSRF token, issuing one if it has none.
+func (h *OAuthHandler) csrfToken(c *gin.Context) string {
+	if token, err := c.Cookie(oauthCSRFCookie); err == nil && token != "" {
+		return token
+	}
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	token := base64.RawURLEncoding.EncodeToString(b)
+	setOAuthCookie(c, oauthCSRFCookie, token, 0)
+	return token
+}
+
+// validCSRF reports whether the posted csrf field matches the cookie. Only
+// a page this server rendered knows it, so other sites cannot submit forms
+// on the user's behalf.
+func (h *OAuthHandler) validCSRF(c *gin.Context) bool {
+	cookie, err := c.Cookie(oauthCSRFCookie)
+	return err == nil && cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(c.PostForm("csrf"))) == 1
+}
+
+// setOAuthCookie sets an HTTP-only cookie for the OAuth pages, kept for the
+// browser session when maxAge is zero.
+func setOAuthCookie(c *gin.Context, name, value string, maxAge time.Duration) {
+	secure := c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     oauthCookiePath,
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCHandler holds dependencies for the OpenID Connect endpoints
+type OIDCHandler struct {
+	oidc domain.OIDCService
+}
+
+// NewOIDCHandler creates a new OIDCHandler
+func NewOIDCHandler(oidc domain.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidc: oidc}
+}
+
+// Discovery handles GET /.well-known/openid-configuration
+func (h *OIDCHandler) Discovery(c *gin.Context) {
+	issuer := h.oidc.Issuer()
+	Success(c, http.StatusOK, dto.OIDCDiscoveryResponse{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth/authorize",
+		TokenEndpoint:                     issuer + "/oauth/token",
+		UserInfoEndpoint:                  issuer + "/userinfo",
+		JWKSURI:                           issuer + "/jwks.json",
+		IntrospectionEndpoint:             issuer + "/oauth/introspect",
+		RevocationEndpoint:                issuer + "/oauth/revoke",
+		ScopesSupported:                   []string{domain.ScopeOpenID, domain.ScopeProfile, domain.ScopeEmail},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{domain.GrantAuthorizationCode, domain.GrantRefreshToken, domain.GrantClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "name", "gender", "email", "email_verified"},
+	})
+}
+
+// JWKS handles GET /jwks.json
+func (h *OIDCHandler) JWKS(c *gin.Context) {
+	keys := h.oidc.JWKS()
+	resp := dto.JWKSResponse{Keys: make([]dto.JSONWebKeyResponse, 0, len(keys))}
+	for _, k := range keys {
+		resp.Keys = append(resp.Keys, dto.JSONWebKeyResponse{Kty: k.Kty, Use: k.Use, Alg: k.Alg, Kid: k.Kid, N: k.N, E: k.E})
+	}
+	// Let verifiers cache the keys, but not past a rotation by much.
+	c.Header("Cache-Control", "public, max-age=300")
+	Success(c, http.StatusOK, resp)
+}
+
+// UserInfo handles GET and POST /userinfo
+func (h *OIDCHandler) UserInfo(c *gin.Context) {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		oauthFail(c, domain.NewOAuthError(domain.OAuthInvalidToken, "missing bearer token"))
+		return
+	}
+
+	claims, err := h.oidc.UserInfo(c.Request.Context(), strings.TrimSpace(token))
+	if err != nil {
+		oauthFail(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, claims)
+}
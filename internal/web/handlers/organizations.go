@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler holds dependencies for organization HTTP handlers
+type OrganizationHandler struct {
+	orgService domain.OrganizationService
+	passwords  domain.PasswordService
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler
+func NewOrganizationHandler(svc domain.OrganizationService, passwords domain.PasswordService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: svc, passwords: passwords}
+}
+
+// CreateOrganization handles POST /orgs
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req dto.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	owner := &domain.User{
+		Name:   req.Owner.Name,
+		Email:  req.Owner.Email,
+		Gender: req.Owner.Gender,
+	}
+	if req.Owner.Password != "" {
+		cred, err := h.passwords.NewCredential(req.Owner.Password)
+		if err != nil {
+			FailFromError(c, err)
+			return
+		}
+		owner.Credential = cred
+	}
+
+	org, admin, err := h.orgService.Create(c.Request.Context(), &domain.Organization{Name: req.Name, Slug: req.Slug}, owner)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusCreated, dto.CreateOrganizationResponse{
+		Organization: toOrganizationResponse(org),
+		Owner:        toUserResponse(admin),
+	})
+}
+
+// GetOrganization handles GET /orgs/:id
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid organization ID", nil)
+		return
+	}
+
+	org, err := h.orgService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, toOrganizationResponse(org))
+}
+
+// ListOrganizations handles GET /orgs
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.orgService.List(c.Request.Context())
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := make([]dto.OrganizationResponse, 0, len(orgs))
+	for _, o := range orgs {
+		resp = append(resp, toOrganizationResponse(o))
+	}
+	Success(c, http.StatusOK, gin.H{"data": resp})
+}
+
+func toOrganizationResponse(o *domain.Organization) dto.OrganizationResponse {
+	return dto.OrganizationResponse{ID: o.ID, Name: o.Name, Slug: o.Slug}
+}
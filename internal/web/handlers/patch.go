@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"userHub/internal/domain"
+)
+
+// Media types accepted by PATCH endpoints.
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+// errUnsupportedPatch is returned for a Content-Type that is not a patch format.
+var errUnsupportedPatch = errors.New("unsupported patch media type")
+
+// applyPatch applies an RFC 7396 merge patch or an RFC 6902 JSON Patch to doc,
+// depending on contentType. A failed "test" operation yields a
+// precondition-failed error; any other malformed patch a validation error.
+func applyPatch(contentType string, doc, patch []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errUnsupportedPatch
+	}
+
+	switch mediaType {
+	case MergePatchMediaType:
+		out, err := jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, domain.NewValidationError("invalid merge patch", nil)
+		}
+		return out, nil
+	case JSONPatchMediaType:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, domain.NewValidationError("invalid JSON patch", nil)
+		}
+		out, err := ops.Apply(doc)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, domain.NewPreconditionFailed(err.Error())
+		}
+		if err != nil {
+			return nil, domain.NewValidationError("JSON patch could not be applied: "+err.Error(), nil)
+		}
+		return out, nil
+	default:
+		return nil, errUnsupportedPatch
+	}
+}
+
+// decodeStrict unmarshals a patched document, rejecting members the
+// representation does not have.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return domain.NewValidationError("patched document is invalid: "+err.Error(), nil)
+	}
+	return nil
+}
@@ -37,6 +37,19 @@ func FailFromError(c *gin.Context, err error) {
 		case domain.CodeConflict:
 			Fail(c, http.StatusConflict, ae.Code, ae.Message, nil)
 			return
+		case domain.CodeUnauthorized:
+			c.Header("WWW-Authenticate", `Bearer realm="userhub"`)
+			Fail(c, http.StatusUnauthorized, ae.Code, ae.Message, nil)
+			return
+		case domain.CodeForbidden:
+			Fail(c, http.StatusForbidden, ae.Code, ae.Message, nil)
+			return
+		case domain.CodePreconditionFailed:
+			Fail(c, http.StatusPreconditionFailed, ae.Code, ae.Message, nil)
+			return
+		case domain.CodePreconditionRequired:
+			Fail(c, http.StatusPreconditionRequired, ae.Code, ae.Message, nil)
+			return
 		default:
 			Fail(c, http.StatusInternalServerError, domain.CodeInternal, ae.Message, nil)
 			return
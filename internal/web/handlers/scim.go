@@ -0,0 +1,742 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+	"userHub/pkg/filter"
+	"userHub/pkg/scim"
+	"userHub/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMMediaType is the content type of SCIM requests and responses.
+const SCIMMediaType = "application/scim+json"
+
+// scimMaxResults caps count, and is the page size used to gather resources
+// for filtering.
+const scimMaxResults = 100
+
+// scimMaxFiltered is the most resources a filter is evaluated against. A
+// filter the repository cannot narrow down further fails with tooMany.
+const scimMaxFiltered = 1000
+
+// SCIMHandler serves SCIM 2.0 (RFC 7644) provisioning on top of the user and
+// group services, so identity providers get the same rules as everyone else.
+type SCIMHandler struct {
+	users          domain.UserService
+	passwords      domain.PasswordService
+	groups         domain.GroupService
+	requireIfMatch bool
+}
+
+// NewSCIMHandler creates a new SCIMHandler. With requireIfMatch set, PUT,
+// PATCH and DELETE on Users need an If-Match header, as on /users/:id.
+func NewSCIMHandler(users domain.UserService, passwords domain.PasswordService, groups domain.GroupService, requireIfMatch bool) *SCIMHandler {
+	return &SCIMHandler{users: users, passwords: passwords, groups: groups, requireIfMatch: requireIfMatch}
+}
+
+// CreateUser handles POST /scim/v2/Users
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var res dto.SCIMUser
+	if err := c.ShouldBindJSON(&res); err != nil {
+		scimFail(c, &scim.Error{Type: scim.ErrInvalidSyntax, Detail: "invalid JSON body"})
+		return
+	}
+	fields, err := scimUserFields(res, "")
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	user := &domain.User{Name: fields.Name, Email: fields.Email, Gender: fields.Gender, Role: domain.Role(fields.Role)}
+	if fields.Password != "" {
+		cred, err := h.passwords.NewCredential(fields.Password)
+		if err != nil {
+			scimFail(c, err)
+			return
+		}
+		user.Credential = cred
+	}
+
+	created, err := h.users.Create(c.Request.Context(), user)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	out := toSCIMUser(c, created)
+	c.Header("Location", out.Meta.Location)
+	setUserETag(c, created)
+	scimJSON(c, http.StatusCreated, out)
+}
+
+// GetUser handles GET /scim/v2/Users/:id
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	user, ok := h.loadUser(c)
+	if !ok {
+		return
+	}
+
+	setUserETag(c, user)
+	scimJSON(c, http.StatusOK, toSCIMUser(c, user))
+}
+
+// ListUsers handles GET /scim/v2/Users
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+	list := func(f scim.Filter, page, limit int) ([]*domain.User, int64, error) {
+		query := scimUserQuery(f)
+		query.Page, query.Limit = page, limit
+		return h.users.List(ctx, query)
+	}
+
+	resources := make([]any, 0)
+	total, startIndex, err := scimQuery(c, list, func(u *domain.User) any { return toSCIMUser(c, u) }, &resources)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, scimListResponse(resources, total, startIndex))
+}
+
+// ReplaceUser handles PUT /scim/v2/Users/:id
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	user, ok := h.loadUserForWrite(c)
+	if !ok {
+		return
+	}
+
+	var res dto.SCIMUser
+	if err := c.ShouldBindJSON(&res); err != nil {
+		scimFail(c, &scim.Error{Type: scim.ErrInvalidSyntax, Detail: "invalid JSON body"})
+		return
+	}
+	h.saveUser(c, user, res)
+}
+
+// PatchUser handles PATCH /scim/v2/Users/:id
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	user, ok := h.loadUserForWrite(c)
+	if !ok {
+		return
+	}
+
+	var res dto.SCIMUser
+	if err := applySCIMPatch(c, toSCIMUser(c, user), &res); err != nil {
+		scimFail(c, err)
+		return
+	}
+	h.saveUser(c, user, res)
+}
+
+// DeleteUser handles DELETE /scim/v2/Users/:id
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	id, ok := scimID(c)
+	if !ok {
+		return
+	}
+	expected, err := parseIfMatch(c, h.requireIfMatch)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	if err := h.users.Delete(c.Request.Context(), id, expected); err != nil {
+		scimFail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) loadUser(c *gin.Context) (*domain.User, bool) {
+	id, ok := scimID(c)
+	if !ok {
+		return nil, false
+	}
+	user, err := h.users.GetByID(c.Request.Context(), id)
+	if err != nil {
+		scimFail(c, err)
+		return nil, false
+	}
+	return user, true
+}
+
+// loadUserForWrite is loadUser for PUT and PATCH. The user carries the
+// version named in If-Match, if any, so the store refuses a stale write.
+func (h *SCIMHandler) loadUserForWrite(c *gin.Context) (*domain.User, bool) {
+	expected, err := parseIfMatch(c, h.requireIfMatch)
+	if err != nil {
+		scimFail(c, err)
+		return nil, false
+	}
+	user, ok := h.loadUser(c)
+	if ok && expected != 0 {
+		user.Version = expected
+	}
+	return user, ok
+}
+
+// saveUser writes the new representation res of user. The email address
+// has its own confirmation flow, so userName cannot change here, and
+// "active": false deactivates the user with a soft delete.
+func (h *SCIMHandler) saveUser(c *gin.Context, user *domain.User, res dto.SCIMUser) {
+	if !strings.EqualFold(res.UserName, user.Email) {
+		scimFail(c, &scim.Error{Type: scim.ErrMutability, Detail: "userName can only be changed through the email change flow"})
+		return
+	}
+	if res.Password != "" {
+		scimFail(c, &scim.Error{Type: scim.ErrMutability, Detail: "password can only be set when the user is created"})
+		return
+	}
+	fields, err := scimUserFields(res, user.Name)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	if res.Active != nil && !*res.Active {
+		if err := h.users.Delete(c.Request.Context(), user.ID, user.Version); err != nil {
+			scimFail(c, err)
+			return
+		}
+		out := toSCIMUser(c, user)
+		*out.Active = false
+		out.Meta.Version = ""
+		scimJSON(c, http.StatusOK, out)
+		return
+	}
+
+	user.Name = fields.Name
+	if fields.Gender != "" {
+		user.Gender = fields.Gender
+	}
+	if fields.Role != "" {
+		user.Role = domain.Role(fields.Role)
+	}
+	updated, err := h.users.Update(c.Request.Context(), user)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	setUserETag(c, updated)
+	scimJSON(c, http.StatusOK, toSCIMUser(c, updated))
+}
+
+// CreateGroup handles POST /scim/v2/Groups
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var res dto.SCIMGroup
+	if err := c.ShouldBindJSON(&res); err != nil {
+		scimFail(c, &scim.Error{Type: scim.ErrInvalidSyntax, Detail: "invalid JSON body"})
+		return
+	}
+	group := &domain.Group{}
+	if err := applySCIMGroup(group, res); err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	created, err := h.groups.Create(c.Request.Context(), group)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	out := toSCIMGroup(c, created)
+	c.Header("Location", out.Meta.Location)
+	scimJSON(c, http.StatusCreated, out)
+}
+
+// GetGroup handles GET /scim/v2/Groups/:id
+func (h *SCIMHandler) GetGroup(c *gin.Context) {
+	group, ok := h.loadGroup(c)
+	if !ok {
+		return
+	}
+	scimJSON(c, http.StatusOK, toSCIMGroup(c, group))
+}
+
+// ListGroups handles GET /scim/v2/Groups
+func (h *SCIMHandler) ListGroups(c *gin.Context) {
+	ctx := c.Request.Context()
+	list := func(_ scim.Filter, page, limit int) ([]*domain.Group, int64, error) {
+		return h.groups.List(ctx, page, limit)
+	}
+
+	resources := make([]any, 0)
+	total, startIndex, err := scimQuery(c, list, func(g *domain.Group) any { return toSCIMGroup(c, g) }, &resources)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, scimListResponse(resources, total, startIndex))
+}
+
+// ReplaceGroup handles PUT /scim/v2/Groups/:id
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	group, ok := h.loadGroup(c)
+	if !ok {
+		return
+	}
+
+	var res dto.SCIMGroup
+	if err := c.ShouldBindJSON(&res); err != nil {
+		scimFail(c, &scim.Error{Type: scim.ErrInvalidSyntax, Detail: "invalid JSON body"})
+		return
+	}
+	h.saveGroup(c, group, res)
+}
+
+// PatchGroup handles PATCH /scim/v2/Groups/:id
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	group, ok := h.loadGroup(c)
+	if !ok {
+		return
+	}
+
+	var res dto.SCIMGroup
+	if err := applySCIMPatch(c, toSCIMGroup(c, group), &res); err != nil {
+		scimFail(c, err)
+		return
+	}
+	h.saveGroup(c, group, res)
+}
+
+// DeleteGroup handles DELETE /scim/v2/Groups/:id
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	id, ok := scimID(c)
+	if !ok {
+		return
+	}
+	if err := h.groups.Delete(c.Request.Context(), id); err != nil {
+		scimFail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) loadGroup(c *gin.Context) (*domain.Group, bool) {
+	id, ok := scimID(c)
+	if !ok {
+		return nil, false
+	}
+	group, err := h.groups.GetByID(c.Request.Context(), id)
+	if err != nil {
+		scimFail(c, err)
+		return nil, false
+	}
+	return group, true
+}
+
+func (h *SCIMHandler) saveGroup(c *gin.Context, group *domain.Group, res dto.SCIMGroup) {
+	if err := applySCIMGroup(group, res); err != nil {
+		scimFail(c, err)
+		return
+	}
+	updated, err := h.groups.Update(c.Request.Context(), group)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, toSCIMGroup(c, updated))
+}
+
+// scimQuery answers a list request: the filter, if any, is evaluated against
+// each resource's representation, and startIndex/count select the window.
+// Without a filter the window maps directly onto the service's pages. list is
+// handed the filter so it can narrow the listing; it receives nil without one.
+func scimQuery[T any](c *gin.Context, list func(f scim.Filter, page, limit int) ([]T, int64, error), represent func(T) any, out *[]any) (total int64, startIndex int, err error) {
+	startIndex, count, err := scimWindow(c)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	expr := c.Query("filter")
+	if expr == "" {
+		unfiltered := func(page, limit int) ([]T, int64, error) { return list(nil, page, limit) }
+		items, total, err := listWindow(unfiltered, startIndex, count)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, item := range items {
+			*out = append(*out, represent(item))
+		}
+		return total, startIndex, nil
+	}
+
+	f, err := scim.ParseFilter(expr)
+	if err != nil {
+		return 0, 0, err
+	}
+	items, err := listAll(func(page, limit int) ([]T, int64, error) { return list(f, page, limit) })
+	if err != nil {
+		return 0, 0, err
+	}
+	var matched []any
+	for _, item := range items {
+		res := represent(item)
+		doc, err := toDocument(res)
+		if err != nil {
+			return 0, 0, err
+		}
+		if f.Match(doc) {
+			matched = append(matched, res)
+		}
+	}
+
+	total = int64(len(matched))
+	if start := startIndex - 1; start < len(matched) {
+		end := start + count
+		if end > len(matched) {
+			end = len(matched)
+		}
+		*out = append(*out, matched[start:end]...)
+	}
+	return total, startIndex, nil
+}
+
+// scimUserQuery narrows a user listing by the equalities on userName, id
+// and displayName that f requires. The listing is a superset of the matches;
+// f itself still decides.
+func scimUserQuery(f scim.Filter) domain.UserQuery {
+	var query domain.UserQuery
+	and := func(e filter.Expr) {
+		if query.Filter != nil {
+			e = filter.And{Left: query.Filter, Right: e}
+		}
+		query.Filter = e
+	}
+	for _, eq := range scim.Equalities(f) {
+		attr := strings.TrimPrefix(strings.ToLower(eq.Attr), strings.ToLower(dto.SCIMUserSchema)+":")
+		switch attr {
+		case "username":
+			and(filter.Compare{Field: "email", Type: filter.String, Op: filter.Eq, Value: eq.Value})
+		case "displayname":
+			and(filter.Compare{Field: "name", Type: filter.String, Op: filter.Eq, Value: eq.Value})
+		case "id":
+			// An id that is no number matches nobody, and no user has ID 0.
+			id, _ := strconv.ParseUint(eq.Value, 10, 32)
+			query.IDs = append(query.IDs, uint(id))
+		}
+	}
+	return query
+}
+
+// scimWindow reads startIndex (1-based, default 1) and count (default and
+// at most scimMaxResults).
+func scimWindow(c *gin.Context) (startIndex, count int, err error) {
+	startIndex, count = 1, scimMaxResults
+	if v := c.Query("startIndex"); v != "" {
+		if startIndex, err = strconv.Atoi(v); err != nil {
+			return 0, 0, &scim.Error{Type: scim.ErrInvalidValue, Detail: "startIndex must be an integer"}
+		}
+		if startIndex < 1 {
+			startIndex = 1
+		}
+	}
+	if v := c.Query("count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil {
+			return 0, 0, &scim.Error{Type: scim.ErrInvalidValue, Detail: "count must be an integer"}
+		}
+		count = max(0, min(count, scimMaxResults))
+	}
+	return startIndex, count, nil
+}
+
+// listWindow maps a SCIM window onto page-based List calls: one page when
+// startIndex falls on a page boundary of size count, otherwise the two pages
+// the window straddles.
+func listWindow[T any](list func(page, limit int) ([]T, int64, error), startIndex, count int) ([]T, int64, error) {
+	if count == 0 {
+		_, total, err := list(1, 1)
+		return nil, total, err
+	}
+
+	offset := startIndex - 1
+	page := offset/count + 1
+	items, total, err := list(page, count)
+	if err != nil {
+		return nil, 0, err
+	}
+	if skip := offset % count; skip > 0 {
+		items = items[min(skip, len(items)):]
+		more, _, err := list(page+1, count)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, more...)
+		items = items[:min(count, len(items))]
+	}
+	return items, total, nil
+}
+
+// listAll collects every item, scimMaxResults at a time, unless there are
+// more than scimMaxFiltered.
+func listAll[T any](list func(page, limit int) ([]T, int64, error)) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		items, total, err := list(page, scimMaxResults)
+		if err != nil {
+			return nil, err
+		}
+		if total > scimMaxFiltered {
+			return nil, &scim.Error{Type: scim.ErrTooMany, Detail: fmt.Sprintf("the filter would be evaluated against %d resources; narrow it down with an eq comparison on userName, id or displayName", total)}
+		}
+		all = append(all, items...)
+		if len(items) == 0 || int64(len(all)) >= total {
+			return all, nil
+		}
+	}
+}
+
+// applySCIMPatch applies the PATCH request in the body to current and
+// decodes the result into next.
+func applySCIMPatch(c *gin.Context, current any, next any) error {
+	var req dto.SCIMPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return &scim.Error{Type: scim.ErrInvalidSyntax, Detail: "invalid JSON body"}
+	}
+	if !containsSchema(req.Schemas, dto.SCIMPatchOpSchema) {
+		return &scim.Error{Type: scim.ErrInvalidSyntax, Detail: "schemas must contain " + dto.SCIMPatchOpSchema}
+	}
+	if len(req.Operations) == 0 {
+		return &scim.Error{Type: scim.ErrInvalidSyntax, Detail: "Operations must not be empty"}
+	}
+
+	doc, err := toDocument(current)
+	if err != nil {
+		return err
+	}
+	if err := scim.ApplyPatch(doc, req.Operations); err != nil {
+		return err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, next); err != nil {
+		return &scim.Error{Type: scim.ErrInvalidValue, Detail: "patched resource is invalid: " + err.Error()}
+	}
+	return nil
+}
+
+// toDocument turns a resource into the generic form filters and patches work on.
+func toDocument(res any) (map[string]any, error) {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func toSCIMUser(c *gin.Context, u *domain.User) dto.SCIMUser {
+	active := true
+	return dto.SCIMUser{
+		Schemas:     []string{dto.SCIMUserSchema, dto.SCIMUserExtensionSchema},
+		ID:          strconv.FormatUint(uint64(u.ID), 10),
+		UserName:    u.Email,
+		Name:        &dto.SCIMName{Formatted: u.Name},
+		DisplayName: u.Name,
+		Emails:      []dto.SCIMEmail{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Extension:   &dto.SCIMUserExtension{Gender: u.Gender, Role: string(u.Role)},
+		Meta: &dto.SCIMMeta{
+			ResourceType: "User",
+			Created:      u.CreatedAt.UTC().Format(time.RFC3339),
+			LastModified: u.UpdatedAt.UTC().Format(time.RFC3339),
+			Location:     scimLocation(c, "Users", u.ID),
+			Version:      userETag(u),
+		},
+	}
+}
+
+// scimUserFields maps res onto user fields and validates them. Identity
+// providers spell the name several ways; the first of name.formatted,
+// name.givenName + name.familyName and displayName that differs from
+// currentName wins, so whichever one a PATCH changed takes effect.
+func scimUserFields(res dto.SCIMUser, currentName string) (dto.SCIMUserFields, error) {
+	fields := dto.SCIMUserFields{Email: res.UserName, Name: currentName, Password: res.Password}
+	candidates := []string{res.DisplayName}
+	if res.Name != nil {
+		given := strings.TrimSpace(res.Name.GivenName + " " + res.Name.FamilyName)
+		candidates = []string{res.Name.Formatted, given, res.DisplayName}
+	}
+	for _, name := range candidates {
+		if name = strings.TrimSpace(name); name != "" && name != currentName {
+			fields.Name = name
+			break
+		}
+	}
+	if res.Extension != nil {
+		fields.Gender = res.Extension.Gender
+		fields.Role = res.Extension.Role
+	}
+
+	if err := validator.Validate(fields); err != nil {
+		return fields, domain.NewValidationError("validation failed", validator.ErrorMap(err))
+	}
+	return fields, nil
+}
+
+func toSCIMGroup(c *gin.Context, g *domain.Group) dto.SCIMGroup {
+	members := make([]dto.SCIMMember, 0, len(g.MemberIDs)+len(g.SubgroupIDs))
+	for _, id := range g.MemberIDs {
+		members = append(members, dto.SCIMMember{
+			Value: strconv.FormatUint(uint64(id), 10),
+			Ref:   scimLocation(c, "Users", id),
+			Type:  "User",
+		})
+	}
+	for _, id := range g.SubgroupIDs {
+		members = append(members, dto.SCIMMember{
+			Value: strconv.FormatUint(uint64(id), 10),
+			Ref:   scimLocation(c, "Groups", id),
+			Type:  "Group",
+		})
+	}
+	return dto.SCIMGroup{
+		Schemas:     []string{dto.SCIMGroupSchema},
+		ID:          strconv.FormatUint(uint64(g.ID), 10),
+		DisplayName: g.Name,
+		Members:     members,
+		Meta: &dto.SCIMMeta{
+			ResourceType: "Group",
+			Created:      g.CreatedAt.UTC().Format(time.RFC3339),
+			LastModified: g.UpdatedAt.UTC().Format(time.RFC3339),
+			Location:     scimLocation(c, "Groups", g.ID),
+		},
+	}
+}
+
+func applySCIMGroup(g *domain.Group, res dto.SCIMGroup) error {
+	g.Name = res.DisplayName
+	g.MemberIDs = make([]uint, 0, len(res.Members))
+	g.SubgroupIDs = []uint{}
+	for _, m := range res.Members {
+		id, err := strconv.ParseUint(m.Value, 10, 64)
+		if err != nil || id == 0 {
+			return &scim.Error{Type: scim.ErrInvalidValue, Detail: fmt.Sprintf("invalid member %q", m.Value)}
+		}
+		switch m.Type {
+		case "", "User":
+			g.MemberIDs = append(g.MemberIDs, uint(id))
+		case "Group":
+			g.SubgroupIDs = append(g.SubgroupIDs, uint(id))
+		default:
+			return &scim.Error{Type: scim.ErrInvalidValue, Detail: fmt.Sprintf("invalid member type %q", m.Type)}
+		}
+	}
+	return nil
+}
+
+func scimID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		scimFail(c, domain.NewNotFound("resource not found"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// scimLocation is the absolute URL of a resource.
+func scimLocation(c *gin.Context, endpoint string, id uint) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/scim/v2/%s/%d", scheme, c.Request.Host, endpoint, id)
+}
+
+func scimListResponse(resources []any, total int64, startIndex int) dto.SCIMListResponse {
+	return dto.SCIMListResponse{
+		Schemas:      []string{dto.SCIMListResponseSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+func containsSchema(schemas []string, schema string) bool {
+	for _, s := range schemas {
+		if strings.EqualFold(s, schema) {
+			return true
+		}
+	}
+	return false
+}
+
+func scimJSON(c *gin.Context, status int, v any) {
+	c.Header("Content-Type", SCIMMediaType+"; charset=utf-8")
+	c.JSON(status, v)
+}
+
+// scimFail writes err as a SCIM error (RFC 7644 section 3.12).
+func scimFail(c *gin.Context, err error) {
+	status, scimType, detail := http.StatusInternalServerError, "", "internal error"
+
+	var scimErr *scim.Error
+	var appErr *domain.AppError
+	switch {
+	case errors.As(err, &scimErr):
+		status, scimType, detail = http.StatusBadRequest, scimErr.Type, scimErr.Detail
+	case errors.As(err, &appErr):
+		detail = appErr.Message
+		switch appErr.Code {
+		case domain.CodeValidation:
+			status, scimType = http.StatusBadRequest, scim.ErrInvalidValue
+			detail = withFieldErrors(detail, appErr.Fields)
+		case domain.CodeNotFound:
+			status = http.StatusNotFound
+		case domain.CodeConflict:
+			status, scimType = http.StatusConflict, scim.ErrUniqueness
+		case domain.CodeUnauthorized:
+			status = http.StatusUnauthorized
+		case domain.CodeForbidden:
+			status = http.StatusForbidden
+		case domain.CodePreconditionFailed:
+			status = http.StatusPreconditionFailed
+		case domain.CodePreconditionRequired:
+			status = http.StatusPreconditionRequired
+		}
+	default:
+		log.Printf("scim: %v", err)
+	}
+
+	scimJSON(c, status, dto.SCIMErrorResponse{
+		Schemas:  []string{dto.SCIMErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func withFieldErrors(detail string, fields map[string]string) string {
+	if len(fields) == 0 {
+		return detail
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+" "+fields[name])
+	}
+	return detail + ": " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceProviderConfig handles GET /scim/v2/ServiceProviderConfig
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	scimJSON(c, http.StatusOK, dto.SCIMServiceProviderConfig{
+		Schemas:        []string{dto.SCIMServiceProviderConfigSchema},
+		Patch:          dto.SCIMSupported{Supported: true},
+		Bulk:           dto.SCIMBulkSupport{},
+		Filter:         dto.SCIMFilterSupport{Supported: true, MaxResults: scimMaxResults},
+		ChangePassword: dto.SCIMSupported{},
+		Sort:           dto.SCIMSupported{},
+		ETag:           dto.SCIMSupported{Supported: true},
+		AuthenticationSchemes: []dto.SCIMAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "API key",
+			Description: "A userHub API key with the " + string(domain.ScopeUsersRead) + " and " + string(domain.ScopeUsersWrite) + " scopes, sent as a bearer token",
+			Primary:     true,
+		}},
+		Meta: dto.SCIMMeta{ResourceType: "ServiceProviderConfig", Location: "/scim/v2/ServiceProviderConfig"},
+	})
+}
+
+// ResourceTypes handles GET /scim/v2/ResourceTypes
+func (h *SCIMHandler) ResourceTypes(c *gin.Context) {
+	types := h.resourceTypes()
+	resources := make([]any, 0, len(types))
+	for _, t := range types {
+		resources = append(resources, t)
+	}
+	scimJSON(c, http.StatusOK, scimListResponse(resources, int64(len(resources)), 1))
+}
+
+// ResourceType handles GET /scim/v2/ResourceTypes/:id
+func (h *SCIMHandler) ResourceType(c *gin.Context) {
+	for _, t := range h.resourceTypes() {
+		if t.ID == c.Param("id") {
+			scimJSON(c, http.StatusOK, t)
+			return
+		}
+	}
+	scimFail(c, domain.NewNotFound("resource type not found"))
+}
+
+// Schemas handles GET /scim/v2/Schemas
+func (h *SCIMHandler) Schemas(c *gin.Context) {
+	schemas := h.schemas()
+	resources := make([]any, 0, len(schemas))
+	for _, s := range schemas {
+		resources = append(resources, s)
+	}
+	scimJSON(c, http.StatusOK, scimListResponse(resources, int64(len(resources)), 1))
+}
+
+// Schema handles GET /scim/v2/Schemas/:id
+func (h *SCIMHandler) Schema(c *gin.Context) {
+	for _, s := range h.schemas() {
+		if s.ID == c.Param("id") {
+			scimJSON(c, http.StatusOK, s)
+			return
+		}
+	}
+	scimFail(c, domain.NewNotFound("schema not found"))
+}
+
+func (h *SCIMHandler) resourceTypes() []dto.SCIMResourceType {
+	types := []dto.SCIMResourceType{{
+		Schemas:          []string{dto.SCIMResourceTypeSchema},
+		ID:               "User",
+		Name:             "User",
+		Endpoint:         "/Users",
+		Description:      "User Account",
+		Schema:           dto.SCIMUserSchema,
+		SchemaExtensions: []dto.SCIMSchemaExtension{{Schema: dto.SCIMUserExtensionSchema}},
+		Meta:             dto.SCIMMeta{ResourceType: "ResourceType", Location: "/scim/v2/ResourceTypes/User"},
+	}}
+	if h.groups != nil {
+		types = append(types, dto.SCIMResourceType{
+			Schemas:     []string{dto.SCIMResourceTypeSchema},
+			ID:          "Group",
+			Name:        "Group",
+			Endpoint:    "/Groups",
+			Description: "Group",
+			Schema:      dto.SCIMGroupSchema,
+			Meta:        dto.SCIMMeta{ResourceType: "ResourceType", Location: "/scim/v2/ResourceTypes/Group"},
+		})
+	}
+	return types
+}
+
+func (h *SCIMHandler) schemas() []dto.SCIMSchema {
+	schemas := []dto.SCIMSchema{
+		{
+			ID:          dto.SCIMUserSchema,
+			Name:        "User",
+			Description: "User Account",
+			Attributes: []dto.SCIMAttribute{
+				scimAttr("userName", "string", "The user's email address, unique within the organization.", true, "readWrite", "server"),
+				scimComplex("name", "The components of the user's name.", false,
+					scimAttr("formatted", "string", "The full name.", false, "readWrite", "none"),
+					scimAttr("givenName", "string", "The given name; joined with familyName when formatted is absent.", false, "writeOnly", "none"),
+					scimAttr("familyName", "string", "The family name.", false, "writeOnly", "none"),
+				),
+				scimAttr("displayName", "string", "The name displayed for the user.", false, "readWrite", "none"),
+				scimComplex("emails", "The user's email address, the same as userName.", true,
+					scimAttr("value", "string", "The email address.", false, "readOnly", "none"),
+					scimAttr("type", "string", "Always work.", false, "readOnly", "none"),
+					scimAttr("primary", "boolean", "Always true.", false, "readOnly", "none"),
+				),
+				scimAttr("active", "boolean", "Setting it to false deactivates (soft-deletes) the user.", false, "readWrite", "none"),
+				scimAttr("password", "string", "The initial password, checked against the password policy.", false, "writeOnly", "none"),
+			},
+		},
+		{
+			ID:          dto.SCIMUserExtensionSchema,
+			Name:        "userHub User",
+			Description: "userHub attributes of a user",
+			Attributes: []dto.SCIMAttribute{
+				scimAttr("gender", "string", "male or female.", false, "readWrite", "none"),
+				scimAttr("role", "string", "admin, manager or member; only admins may assign roles.", false, "readWrite", "none"),
+			},
+		},
+	}
+	if h.groups != nil {
+		schemas = append(schemas, dto.SCIMSchema{
+			ID:          dto.SCIMGroupSchema,
+			Name:        "Group",
+			Description: "Group",
+			Attributes: []dto.SCIMAttribute{
+				scimAttr("displayName", "string", "The group name, unique within the organization.", true, "readWrite", "server"),
+				scimComplex("members", "The users and groups in the group.", true,
+					scimAttr("value", "string", "The id of the user or group.", false, "immutable", "none"),
+					dto.SCIMAttribute{Name: "$ref", Type: "reference", ReferenceTypes: []string{"User", "Group"}, Description: "The URI of the user or group.", Mutability: "immutable", Returned: "default", Uniqueness: "none"},
+					scimAttr("type", "string", "User or Group; User when absent.", false, "immutable", "none"),
+				),
+			},
+		})
+	}
+	for i := range schemas {
+		schemas[i].Schemas = []string{dto.SCIMSchemaSchema}
+		schemas[i].Meta = dto.SCIMMeta{ResourceType: "Schema", Location: "/scim/v2/Schemas/" + schemas[i].ID}
+	}
+	return schemas
+}
+
+func scimAttr(name, typ, description string, required bool, mutability, uniqueness string) dto.SCIMAttribute {
+	returned := "default"
+	if mutability == "writeOnly" {
+		returned = "never"
+	}
+	return dto.SCIMAttribute{
+		Name:        name,
+		Type:        typ,
+		Description: description,
+		Required:    required,
+		Mutability:  mutability,
+		Returned:    returned,
+		Uniqueness:  uniqueness,
+	}
+}
+
+func scimComplex(name, description string, multiValued bool, sub ...dto.SCIMAttribute) dto.SCIMAttribute {
+	return dto.SCIMAttribute{
+		Name:          name,
+		Type:          "complex",
+		MultiValued:   multiValued,
+		Description:   description,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: sub,
+	}
+}
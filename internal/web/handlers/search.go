@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"userHub/internal/domain"
+	"userHub/internal/web/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSearchLength caps the length of a search query, in characters.
+const maxSearchLength = 200
+
+// UserSearchHandler holds dependencies for user search HTTP handlers
+type UserSearchHandler struct {
+	search domain.UserSearchService
+}
+
+// NewUserSearchHandler creates a new UserSearchHandler
+func NewUserSearchHandler(search domain.UserSearchService) *UserSearchHandler {
+	return &UserSearchHandler{search: search}
+}
+
+// SearchUsers handles GET /users/search?q=...&limit=...
+func (h *UserSearchHandler) SearchUsers(c *gin.Context) {
+	fields := make(map[string]string)
+	q := strings.TrimSpace(c.Query("q"))
+	switch {
+	case q == "":
+		fields["q"] = "is required"
+	case utf8.RuneCountInString(q) > maxSearchLength:
+		fields["q"] = "must be at most " + strconv.Itoa(maxSearchLength) + " characters"
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		fields["limit"] = "must be a positive integer"
+	}
+	if len(fields) > 0 {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid search", fields)
+		return
+	}
+
+	results, err := h.search.Search(c.Request.Context(), q, limit)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	resp := dto.UserSearchResponse{Data: make([]dto.UserSearchHitResponse, 0, len(results))}
+	for _, r := range results {
+		resp.Data = append(resp.Data, dto.UserSearchHitResponse{
+			User:       toUserResponse(r.User),
+			Score:      r.Score,
+			Highlights: r.Highlights,
+		})
+	}
+	resp.Meta.Query = q
+	Success(c, http.StatusOK, resp)
+}
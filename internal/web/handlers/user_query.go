@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"userHub/internal/domain"
+	"userHub/pkg/filter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxIDFilter caps the number of IDs an id=in: filter may list.
+const maxIDFilter = 100
+
+// parseUserQuery reads the filters, sort and paging of GET /users:
+//
+//	q=smith                  name or email contains smith
+//	verified=true            email is (not) verified
+//	gender=female            male or female
+//	email_domain=example.com email ends in @example.com
+//	id=in:1,2,3              one of the IDs (id=7 for a single one)
+//	created_after=2025-01-01 created after this date or RFC 3339 time
+//	created_before=...       likewise, created before it
+//	filter=name co "smith"   an expression over domain.UserFilterFields
+//	sort=-name,email         order by these fields, - for descending
+//
+// Only whitelisted fields can be filtered and sorted by. Invalid values are
+// reported per parameter; a malformed filter names the position at fault.
+func parseUserQuery(c *gin.Context) (domain.UserQuery, map[string]string) {
+	fields := make(map[string]string)
+	query := domain.UserQuery{
+		Search:      c.Query("q"),
+		Gender:      strings.ToLower(c.Query("gender")),
+		EmailDomain: strings.ToLower(strings.TrimPrefix(c.Query("email_domain"), "@")),
+	}
+
+	query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	query.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if v := c.Query("verified"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			fields["verified"] = "must be true or false"
+		}
+		query.Verified = &b
+	}
+	if query.Gender != "" && query.Gender != "male" && query.Gender != "female" {
+		fields["gender"] = "must be male or female"
+	}
+	if _, ok := c.GetQuery("email_domain"); ok && (query.EmailDomain == "" || strings.ContainsAny(query.EmailDomain, "@ ")) {
+		fields["email_domain"] = "must be a domain such as example.com"
+	}
+	if v := c.Query("id"); v != "" {
+		ids, msg := parseIDFilter(v)
+		if msg != "" {
+			fields["id"] = msg
+		}
+		query.IDs = ids
+	}
+	for name, bound := range map[string]*time.Time{"created_after": &query.CreatedAfter, "created_before": &query.CreatedBefore} {
+		if v := c.Query(name); v != "" {
+			t, ok := parseTimeBound(v)
+			if !ok {
+				fields[name] = "must be an RFC 3339 timestamp or a date like 2025-01-01"
+			}
+			*bound = t
+		}
+	}
+	if v := c.Query("filter"); v != "" {
+		expr, err := filter.Parse(v, domain.UserFilterFields)
+		if err != nil {
+			fields["filter"] = err.Error()
+		}
+		query.Filter = expr
+	}
+	if v := c.Query("sort"); v != "" {
+		sorts, msg := parseUserSort(v)
+		if msg != "" {
+			fields["sort"] = msg
+		}
+		query.Sort = sorts
+	}
+
+	return query, fields
+}
+
+// parseIDFilter reads "7", "eq:7" or "in:1,2,3".
+func parseIDFilter(v string) ([]uint, string) {
+	const usage = "must be an ID or in: followed by comma-separated IDs"
+	op, list, ok := strings.Cut(v, ":")
+	if !ok {
+		op, list = "eq", v
+	}
+	if op != "eq" && op != "in" {
+		return nil, usage
+	}
+
+	parts := strings.Split(list, ",")
+	if (op == "eq" && len(parts) != 1) || len(parts) > maxIDFilter {
+		return nil, usage
+	}
+	ids := make([]uint, 0, len(parts))
+	for _, p := range parts {
+		id, err := strconv.ParseUint(strings.TrimSpace(p), 10, 64)
+		if err != nil || id == 0 {
+			return nil, usage
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, ""
+}
+
+// parseTimeBound reads an RFC 3339 timestamp or a date, which stands for
+// its midnight UTC.
+func parseTimeBound(v string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), true
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// parseUserSort reads a comma-separated list of fields, each optionally
+// prefixed with - for descending order.
+func parseUserSort(v string) ([]domain.UserSort, string) {
+	var sorts []domain.UserSort
+	seen := make(map[domain.UserSortField]bool)
+	for _, part := range strings.Split(v, ",") {
+		s := domain.UserSort{Field: domain.UserSortField(strings.TrimSpace(part))}
+		if rest, ok := strings.CutPrefix(string(s.Field), "-"); ok {
+			s.Field, s.Desc = domain.UserSortField(rest), true
+		}
+		if !s.Field.Valid() {
+			return nil, "can only sort by " + sortableFields()
+		}
+		if seen[s.Field] {
+			return nil, "sorts by " + string(s.Field) + " twice"
+		}
+		seen[s.Field] = true
+		sorts = append(sorts, s)
+	}
+	return sorts, ""
+}
+
+func sortableFields() string {
+	names := make([]string, 0, len(domain.UserSortFields))
+	for _, f := range domain.UserSortFields {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
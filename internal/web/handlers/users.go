@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -13,12 +15,15 @@ import (
 
 // UserHandler holds dependencies for user-related HTTP handlers
 type UserHandler struct {
-	userService domain.UserService
+	userService    domain.UserService
+	passwords      domain.PasswordService
+	requireIfMatch bool
 }
 
-// NewUserHandler creates a new UserHandler
-func NewUserHandler(svc domain.UserService) *UserHandler {
-	return &UserHandler{userService: svc}
+// NewUserHandler creates a new UserHandler. With requireIfMatch set, PUT and
+// DELETE on a user are rejected unless they carry an If-Match header.
+func NewUserHandler(svc domain.UserService, passwords domain.PasswordService, requireIfMatch bool) *UserHandler {
+	return &UserHandler{userService: svc, passwords: passwords, requireIfMatch: requireIfMatch}
 }
 
 // CreateUser handles POST /users
@@ -33,22 +38,28 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	created, err := h.userService.Create(c.Request.Context(), &domain.User{
+	user := &domain.User{
 		Name:   req.Name,
 		Email:  req.Email,
 		Gender: req.Gender,
-	})
+	}
+	if req.Password != "" {
+		cred, err := h.passwords.NewCredential(req.Password)
+		if err != nil {
+			FailFromError(c, err)
+			return
+		}
+		user.Credential = cred
+	}
+
+	created, err := h.userService.Create(c.Request.Context(), user)
 	if err != nil {
 		FailFromError(c, err)
 		return
 	}
 
-	Success(c, http.StatusCreated, dto.UserResponse{
-		ID:     created.ID,
-		Name:   created.Name,
-		Email:  created.Email,
-		Gender: created.Gender,
-	})
+	setUserETag(c, created)
+	Success(c, http.StatusCreated, toUserResponse(created))
 }
 
 // GetUser handles GET /users/:id
@@ -65,12 +76,26 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	Success(c, http.StatusOK, dto.UserResponse{
-		ID:     user.ID,
-		Name:   user.Name,
-		Email:  user.Email,
-		Gender: user.Gender,
-	})
+	setUserETag(c, user)
+	Success(c, http.StatusOK, toUserResponse(user))
+}
+
+// GetCurrentUser handles GET /users/me
+func (h *UserHandler) GetCurrentUser(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		FailFromError(c, domain.NewUnauthorized("authentication required"))
+		return
+	}
+
+	user, err := h.userService.GetByID(c.Request.Context(), principal.UserID)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	setUserETag(c, user)
+	Success(c, http.StatusOK, toUserResponse(user))
 }
 
 // UpdateUser handles PUT /users/:id
@@ -81,6 +106,11 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	expected, ok := ifMatchVersion(c, h.requireIfMatch)
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
@@ -98,12 +128,119 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// The store compares this version on write, so a stale If-Match, or a
+	// concurrent update since the read above, fails instead of clobbering.
+	if expected != 0 {
+		existing.Version = expected
+	}
+
 	if req.Name != nil {
 		existing.Name = *req.Name
 	}
 	if req.Gender != nil {
 		existing.Gender = *req.Gender
 	}
+	if req.Role != nil {
+		existing.Role = domain.Role(*req.Role)
+	}
+
+	updated, err := h.userService.Update(c.Request.Context(), existing)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	setUserETag(c, updated)
+	Success(c, http.StatusOK, toUserResponse(updated))
+}
+
+// PatchUser handles PATCH /users/:id with a JSON Merge Patch or a JSON Patch
+// applied to the user's current representation.
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	expected, ok := ifMatchVersion(c, h.requireIfMatch)
+	if !ok {
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid request body", nil)
+		return
+	}
+
+	existing, err := h.userService.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+	current := toUserResponse(existing)
+	doc, err := json.Marshal(current)
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	patched, err := applyPatch(c.ContentType(), doc, patch)
+	if errors.Is(err, errUnsupportedPatch) {
+		c.Header("Accept-Patch", MergePatchMediaType+", "+JSONPatchMediaType)
+		Fail(c, http.StatusUnsupportedMediaType, domain.CodeValidation, "Content-Type must be "+MergePatchMediaType+" or "+JSONPatchMediaType, nil)
+		return
+	}
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	var next dto.PatchUserDocument
+	if err := decodeStrict(patched, &next); err != nil {
+		FailFromError(c, err)
+		return
+	}
+	readOnly := map[string]string{}
+	if next.ID != current.ID {
+		readOnly["ID"] = "is read-only"
+	}
+	if next.Email != current.Email {
+		readOnly["Email"] = "is read-only"
+	}
+	if next.EmailVerified != current.EmailVerified {
+		readOnly["EmailVerified"] = "is read-only"
+	}
+	if !next.CreatedAt.Equal(current.CreatedAt) {
+		readOnly["CreatedAt"] = "is read-only"
+	}
+	if !next.UpdatedAt.Equal(current.UpdatedAt) {
+		readOnly["UpdatedAt"] = "is read-only"
+	}
+	if next.CreatedBy != current.CreatedBy {
+		readOnly["CreatedBy"] = "is read-only"
+	}
+	if next.UpdatedBy != current.UpdatedBy {
+		readOnly["UpdatedBy"] = "is read-only"
+	}
+	if len(readOnly) > 0 {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", readOnly)
+		return
+	}
+	if err := validator.Validate(next); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	// Persisting against the version the patch was computed from makes the
+	// whole read-patch-write cycle atomic.
+	if expected != 0 {
+		existing.Version = expected
+	}
+	existing.Name = next.Name
+	existing.Gender = next.Gender
+	existing.Role = domain.Role(next.Role)
 
 	updated, err := h.userService.Update(c.Request.Context(), existing)
 	if err != nil {
@@ -111,12 +248,34 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	Success(c, http.StatusOK, dto.UserResponse{
-		ID:     updated.ID,
-		Name:   updated.Name,
-		Email:  updated.Email,
-		Gender: updated.Gender,
-	})
+	setUserETag(c, updated)
+	Success(c, http.StatusOK, toUserResponse(updated))
+}
+
+// ChangePassword handles PUT /users/me/password
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		FailFromError(c, domain.NewUnauthorized("authentication required"))
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid JSON body", nil)
+		return
+	}
+	if err := validator.Validate(req); err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "validation failed", validator.ErrorMap(err))
+		return
+	}
+
+	if err := h.passwords.ChangePassword(c.Request.Context(), principal.UserID, req.CurrentPassword, req.NewPassword); err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // DeleteUser handles DELETE /users/:id
@@ -127,7 +286,12 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.Delete(c.Request.Context(), uint(id)); err != nil {
+	expected, ok := ifMatchVersion(c, h.requireIfMatch)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.Delete(c.Request.Context(), uint(id), expected); err != nil {
 		FailFromError(c, err)
 		return
 	}
@@ -135,26 +299,95 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// RestoreUser handles POST /users/:id/restore
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user ID", nil)
+		return
+	}
+
+	user, err := h.userService.Restore(c.Request.Context(), uint(id))
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	setUserETag(c, user)
+	Success(c, http.StatusOK, toUserResponse(user))
+}
+
+// PurgeUsers handles POST /users/purge
+func (h *UserHandler) PurgeUsers(c *gin.Context) {
+	purged, err := h.userService.Purge(c.Request.Context())
+	if err != nil {
+		FailFromError(c, err)
+		return
+	}
+
+	Success(c, http.StatusOK, dto.PurgeUsersResponse{Purged: purged})
+}
+
 // ListUsers handles GET /users
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	q := c.Query("q")
+	query, fields := parseUserQuery(c)
+	if len(fields) > 0 {
+		Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid user filter", fields)
+		return
+	}
+
+	// A cursor parameter, even an empty one, selects keyset pagination.
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		result, err := h.userService.ListByCursor(c.Request.Context(), query, cursor)
+		if err != nil {
+			FailFromError(c, err)
+			return
+		}
 
-	users, total, err := h.userService.List(c.Request.Context(), page, limit, q)
+		resp := toListUsersResponse(result.Users)
+		resp.Meta.Limit = result.Limit
+		resp.Meta.Next = result.Next
+		resp.Meta.Prev = result.Prev
+		Success(c, http.StatusOK, resp)
+		return
+	}
+
+	users, total, err := h.userService.List(c.Request.Context(), query)
 	if err != nil {
 		FailFromError(c, err)
 		return
 	}
 
+	resp := toListUsersResponse(users)
+	resp.Meta.Page = query.Page
+	resp.Meta.Limit = query.Limit
+	resp.Meta.Total = &total
+
+	Success(c, http.StatusOK, resp)
+}
+
+func toListUsersResponse(users []*domain.User) dto.ListUsersResponse {
 	resp := dto.ListUsersResponse{}
 	resp.Data = make([]dto.UserResponse, 0, len(users))
 	for _, u := range users {
-		resp.Data = append(resp.Data, dto.UserResponse{ID: u.ID, Name: u.Name, Email: u.Email, Gender: u.Gender})
+		resp.Data = append(resp.Data, toUserResponse(u))
 	}
-	resp.Meta.Page = page
-	resp.Meta.Limit = limit
-	resp.Meta.Total = total
+	return resp
+}
 
-	Success(c, http.StatusOK, resp)
+func toUserResponse(u *domain.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:     u.ID,
+		Name:   u.Name,
+		Email:  u.Email,
+		Gender: u.Gender,
+		Role:   string(u.Role),
+
+		EmailVerified: u.EmailVerified(),
+
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		CreatedBy: u.CreatedBy,
+		UpdatedBy: u.UpdatedBy,
+	}
 }
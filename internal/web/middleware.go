@@ -1,9 +1,17 @@
 package web
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"log"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"userHub/internal/domain"
+	"userHub/internal/web/handlers"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,6 +28,32 @@ func LoggerMiddleware() gin.HandlerFunc {
 }
 
 
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestMetaMiddleware assigns every request an ID (reusing a sane incoming
+// X-Request-ID) and records it with the client IP for the audit trail.
+
+func RequestMetaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			b := make([]byte, 16)
+			_, _ = rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := domain.WithRequestMeta(c.Request.Context(), domain.RequestMeta{
+			RequestID: requestID,
+			ClientIP:  c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+
 // headers middleware to add headers to response
 
 func HeadersMiddleware() gin.HandlerFunc {
@@ -35,4 +69,169 @@ func HeadersMiddleware() gin.HandlerFunc {
 }
 
 
+// TenantHeader lets unauthenticated callers (sign-up, login) pick an organization.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantMiddleware scopes the request to the organization named by the
+// X-Tenant-ID header, or to the default organization when it is absent.
+
+func TenantMiddleware(orgs domain.OrganizationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := domain.DefaultTenantID
+
+		if header := c.GetHeader(TenantHeader); header != "" {
+			id, err := strconv.ParseUint(header, 10, 64)
+			if err != nil || id == 0 {
+				handlers.Fail(c, http.StatusBadRequest, domain.CodeValidation, "invalid X-Tenant-ID header", nil)
+				c.Abort()
+				return
+			}
+			exists, err := orgs.Exists(c.Request.Context(), uint(id))
+			if err != nil {
+				handlers.FailFromError(c, err)
+				c.Abort()
+				return
+			}
+			if !exists {
+				handlers.FailFromError(c, domain.NewNotFound("organization not found"))
+				c.Abort()
+				return
+			}
+			tenantID = uint(id)
+		}
+
+		c.Request = c.Request.WithContext(domain.WithTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
+
+
+// APIKeyHeader carries an API key for clients that cannot send it as a bearer token.
+const APIKeyHeader = "X-API-Key"
+
+// ImpersonatorHeader names the admin behind a response to an impersonation token.
+const ImpersonatorHeader = "X-Impersonator-ID"
+
+// AuthMiddleware requires a valid bearer token or API key and stores the
+// caller's principal in the request context. API keys are accepted in the
+// X-API-Key header or as a bearer token; keys may be nil to disable them.
+// Responses to impersonation tokens carry the X-Impersonator-ID header.
+
+func AuthMiddleware(auth domain.AuthService, keys domain.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimSpace(c.GetHeader(APIKeyHeader))
+		if token == "" {
+			header := c.GetHeader("Authorization")
+			scheme, bearer, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(bearer) == "" {
+				handlers.FailFromError(c, domain.NewUnauthorized("missing bearer token"))
+				c.Abort()
+				return
+			}
+			token = strings.TrimSpace(bearer)
+		}
+
+		var principal *domain.Principal
+		var err error
+		if strings.HasPrefix(token, domain.APIKeyPrefix) {
+			if keys == nil {
+				err = domain.NewUnauthorized("invalid API key")
+			} else {
+				principal, err = keys.Authenticate(c.Request.Context(), token)
+			}
+		} else {
+			principal, err = auth.Authenticate(c.Request.Context(), token)
+		}
+		if err != nil {
+			handlers.FailFromError(c, err)
+			c.Abort()
+			return
+		}
+
+		// A token is only valid for the tenant it was issued in.
+		if header := c.GetHeader(TenantHeader); header != "" && header != strconv.FormatUint(uint64(principal.TenantID), 10) {
+			handlers.FailFromError(c, domain.NewForbidden("token is not valid for this tenant"))
+			c.Abort()
+			return
+		}
+
+		if principal.Impersonated() {
+			c.Header(ImpersonatorHeader, strconv.FormatUint(uint64(principal.ImpersonatorID), 10))
+		}
+
+		ctx := domain.WithPrincipal(c.Request.Context(), principal)
+		ctx = domain.WithTenant(ctx, principal.TenantID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+
+// OptionalAuthMiddleware authenticates requests that carry a bearer token
+// or API key as AuthMiddleware does, and lets the others through without a
+// principal.
+func OptionalAuthMiddleware(auth domain.AuthService, keys domain.APIKeyService) gin.HandlerFunc {
+	required := AuthMiddleware(auth, keys)
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" && c.GetHeader(APIKeyHeader) == "" {
+			c.Next()
+			return
+		}
+		required(c)
+	}
+}
+
+// RequireScope rejects requests made with an API key that lacks scope.
+// Sessions are not scoped and always pass.
+
+func RequireScope(scope domain.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p, ok := domain.PrincipalFromContext(c.Request.Context()); ok && !p.HasScope(scope) {
+			handlers.FailFromError(c, domain.NewForbidden("API key lacks the "+string(scope)+" scope"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+
+// RequireSession rejects requests made with an API key, for endpoints that
+// manage credentials and must only be reached by the user themselves.
+
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if p, ok := domain.PrincipalFromContext(c.Request.Context()); ok && p.APIKeyID != 0 {
+			handlers.FailFromError(c, domain.NewForbidden("this endpoint cannot be used with an API key"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+
+// MFAPolicyMiddleware rejects state-changing requests from callers whose
+// role the organization requires to sign in with MFA, unless their session
+// did. Reads stay allowed so a user can still look around and enroll.
+
+func MFAPolicyMiddleware(mfa domain.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if mfa == nil {
+			c.Next()
+			return
+		}
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
 
+		if err := mfa.CheckPolicy(c.Request.Context()); err != nil {
+			handlers.FailFromError(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -10,12 +10,37 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Config groups the services the HTTP layer is built from.
+type Config struct {
+	Users         domain.UserService
+	Passwords     domain.PasswordService
+	Auth          domain.AuthService
+	Organizations domain.OrganizationService
+	Audit         domain.AuditService
+	EmailChanges  domain.EmailChangeService
+	Verifications domain.EmailVerificationService
+	Resets        domain.PasswordResetService
+	MFA           domain.MFAService
+	APIKeys       domain.APIKeyService
+	OAuth         domain.OAuthService
+	OIDC          domain.OIDCService
+	Groups        domain.GroupService
+	Invitations   domain.InvitationService
+	Impersonation domain.ImpersonationService
+	Search        domain.UserSearchService
+
+	// RequireIfMatch makes PUT and DELETE on /users/:id and /scim/v2/Users/:id
+	// fail with 428 unless the client sends the ETag it last saw in an If-Match header.
+	RequireIfMatch bool
+}
+
 // SetupRouter configures the gin engine with production-ready middleware and routes.
-func SetupRouter(userService domain.UserService) *gin.Engine {
+func SetupRouter(cfg Config) *gin.Engine {
 	r := gin.New()
 
 	// Standard production middleware
 	r.Use(HeadersMiddleware())
+	r.Use(RequestMetaMiddleware())
 	r.Use(LoggerMiddleware())
 	r.Use(gin.Recovery())
 
@@ -23,8 +48,8 @@ func SetupRouter(userService domain.UserService) *gin.Engine {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // Adjust for production environments
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match", TenantHeader, RequestIDHeader, APIKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "ETag", RequestIDHeader, ImpersonatorHeader},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -32,17 +57,177 @@ func SetupRouter(userService domain.UserService) *gin.Engine {
 	// Health check endpoint
 	r.GET("/health", handlers.HealthCheck)
 
-	// Create handler with dependencies
-	userHandler := handlers.NewUserHandler(userService)
+	// Create handlers with dependencies
+	userHandler := handlers.NewUserHandler(cfg.Users, cfg.Passwords, cfg.RequireIfMatch)
+	authHandler := handlers.NewAuthHandler(cfg.Auth, cfg.Resets)
+	orgHandler := handlers.NewOrganizationHandler(cfg.Organizations, cfg.Passwords)
+	auditHandler := handlers.NewAuditHandler(cfg.Audit)
+	emailHandler := handlers.NewEmailHandler(cfg.EmailChanges, cfg.Verifications)
+	mfaHandler := handlers.NewMFAHandler(cfg.MFA)
+	apiKeyHandler := handlers.NewAPIKeyHandler(cfg.APIKeys)
+	oauthHandler := handlers.NewOAuthHandler(cfg.OAuth, cfg.Auth)
+	invitationHandler := handlers.NewInvitationHandler(cfg.Invitations, cfg.Passwords)
+
+	// OAuth 2.0 authorization server for other applications. The user behind
+	// /oauth/authorize is identified by their access token or, in a browser,
+	// signs in and consents on the pages it serves; the other endpoints
+	// authenticate the client instead.
+	if cfg.OAuth != nil {
+		oauth := r.Group("/oauth")
+		oauth.GET("/authorize", OptionalAuthMiddleware(cfg.Auth, cfg.APIKeys), RequireSession(), oauthHandler.Authorize)
+		oauth.POST("/authorize", oauthHandler.AuthorizeForm)
+		oauth.POST("/token", oauthHandler.Token)
+		oauth.POST("/introspect", oauthHandler.Introspect)
+		oauth.POST("/revoke", oauthHandler.Revoke)
+	}
+
+	// OpenID Connect discovery, signing keys and claims, for the same clients.
+	if cfg.OIDC != nil {
+		oidcHandler := handlers.NewOIDCHandler(cfg.OIDC)
+		r.GET("/.well-known/openid-configuration", oidcHandler.Discovery)
+		r.GET("/jwks.json", oidcHandler.JWKS)
+		r.GET("/userinfo", oidcHandler.UserInfo)
+		r.POST("/userinfo", oidcHandler.UserInfo)
+	}
 
-	// API Versioning Group
-	v1 := r.Group("/api/v1")
+	// API Versioning Group, scoped to the caller's tenant
+	v1 := r.Group("/api/v1", TenantMiddleware(cfg.Organizations))
 	{
+		// Public endpoints: sign-up and session management.
+		v1.POST("/auth/login", authHandler.Login)
+		v1.POST("/auth/refresh", authHandler.Refresh)
+		v1.POST("/auth/logout", authHandler.Logout)
+		v1.POST("/auth/mfa/verify", authHandler.VerifyMFA)
+		v1.POST("/auth/password/forgot", authHandler.ForgotPassword)
+		v1.POST("/auth/password/reset", authHandler.ResetPassword)
 		v1.POST("/users", userHandler.CreateUser)
-		v1.GET("/users/:id", userHandler.GetUser)
-		v1.PUT("/users/:id", userHandler.UpdateUser)
-		v1.DELETE("/users/:id", userHandler.DeleteUser)
-		v1.GET("/users", userHandler.ListUsers)
+		v1.POST("/users/email/confirm", emailHandler.ConfirmEmailChange)
+		v1.POST("/users/verification/confirm", emailHandler.ConfirmVerification)
+		if cfg.Invitations != nil {
+			v1.POST("/invitations/accept", invitationHandler.AcceptInvitation)
+		}
+	}
+
+	// Second-factor management needs a session but not the MFA policy,
+	// so users it locks out can still enroll. The policy itself is not
+	// exempt: a password-only session must not be able to lift it.
+	if cfg.MFA != nil {
+		mfa := v1.Group("/auth/mfa", AuthMiddleware(cfg.Auth, cfg.APIKeys), RequireSession())
+		mfa.POST("/totp", mfaHandler.EnrollTOTP)
+		mfa.POST("/totp/confirm", mfaHandler.ConfirmTOTP)
+		mfa.POST("/disable", mfaHandler.DisableMFA)
+		mfa.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+
+		policy := v1.Group("/auth/mfa/policy", AuthMiddleware(cfg.Auth, cfg.APIKeys), RequireSession(), MFAPolicyMiddleware(cfg.MFA))
+		policy.GET("", mfaHandler.GetPolicy)
+		policy.PUT("", mfaHandler.UpdatePolicy)
+	}
+
+	// Everything else requires a valid access token or API key, and changes
+	// honor the organization's MFA policy. API keys are further limited to
+	// their scopes, and never reach endpoints that manage credentials.
+	usersRead := RequireScope(domain.ScopeUsersRead)
+	usersWrite := RequireScope(domain.ScopeUsersWrite)
+	auditRead := RequireScope(domain.ScopeAuditRead)
+	orgsRead := RequireScope(domain.ScopeOrgsRead)
+	orgsWrite := RequireScope(domain.ScopeOrgsWrite)
+	groupsRead := RequireScope(domain.ScopeGroupsRead)
+	groupsWrite := RequireScope(domain.ScopeGroupsWrite)
+	sessionOnly := RequireSession()
+
+	secured := v1.Group("", AuthMiddleware(cfg.Auth, cfg.APIKeys), MFAPolicyMiddleware(cfg.MFA))
+	{
+		secured.GET("/users/me", usersRead, userHandler.GetCurrentUser)
+		secured.PUT("/users/me/password", sessionOnly, userHandler.ChangePassword)
+		secured.GET("/users/:id", usersRead, userHandler.GetUser)
+		secured.PUT("/users/:id", usersWrite, userHandler.UpdateUser)
+		secured.PATCH("/users/:id", usersWrite, userHandler.PatchUser)
+		secured.DELETE("/users/:id", usersWrite, userHandler.DeleteUser)
+		secured.POST("/users/:id/restore", usersWrite, userHandler.RestoreUser)
+		secured.POST("/users/:id/email", sessionOnly, emailHandler.RequestEmailChange)
+		secured.POST("/users/:id/verification", usersWrite, emailHandler.ResendVerification)
+		secured.POST("/users/purge", usersWrite, userHandler.PurgeUsers)
+		secured.DELETE("/users/:id/sessions", usersWrite, authHandler.RevokeSessions)
+		secured.GET("/users/:id/audit", auditRead, auditHandler.ListUserAuditEvents)
+		secured.GET("/audit", auditRead, auditHandler.ListAuditEvents)
+
+		if cfg.APIKeys != nil {
+			secured.POST("/users/:id/api-keys", sessionOnly, apiKeyHandler.CreateAPIKey)
+			secured.GET("/users/:id/api-keys", sessionOnly, apiKeyHandler.ListAPIKeys)
+			secured.DELETE("/users/:id/api-keys/:keyId", sessionOnly, apiKeyHandler.RevokeAPIKey)
+		}
+
+		if cfg.OAuth != nil {
+			secured.POST("/oauth/clients", sessionOnly, oauthHandler.RegisterClient)
+			secured.GET("/oauth/clients", sessionOnly, oauthHandler.ListClients)
+			secured.DELETE("/oauth/clients/:clientId", sessionOnly, oauthHandler.DeleteClient)
+		}
+
+		if cfg.Groups != nil {
+			groupHandler := handlers.NewGroupHandler(cfg.Groups)
+			secured.POST("/groups", groupsWrite, groupHandler.CreateGroup)
+			secured.GET("/groups", groupsRead, groupHandler.ListGroups)
+			secured.GET("/groups/:id", groupsRead, groupHandler.GetGroup)
+			secured.PUT("/groups/:id", groupsWrite, groupHandler.UpdateGroup)
+			secured.DELETE("/groups/:id", groupsWrite, groupHandler.DeleteGroup)
+			secured.POST("/groups/:id/members", groupsWrite, groupHandler.AddMembers)
+			secured.DELETE("/groups/:id/members", groupsWrite, groupHandler.RemoveMembers)
+			secured.GET("/users/:id/groups", usersRead, groupHandler.ListUserGroups)
+		}
+
+		if cfg.Invitations != nil {
+			secured.POST("/invitations", usersWrite, invitationHandler.CreateInvitation)
+			secured.GET("/invitations", usersRead, invitationHandler.ListInvitations)
+			secured.POST("/invitations/:id/revoke", usersWrite, invitationHandler.RevokeInvitation)
+			secured.POST("/invitations/:id/resend", usersWrite, invitationHandler.ResendInvitation)
+		}
+
+		if cfg.Impersonation != nil {
+			impersonationHandler := handlers.NewImpersonationHandler(cfg.Impersonation)
+			secured.POST("/users/:id/impersonate", sessionOnly, impersonationHandler.StartImpersonation)
+			secured.POST("/impersonation/end", sessionOnly, impersonationHandler.EndImpersonation)
+		}
+
+		if cfg.Search != nil {
+			searchHandler := handlers.NewUserSearchHandler(cfg.Search)
+			secured.GET("/users/search", usersRead, searchHandler.SearchUsers)
+		}
+
+		secured.POST("/orgs", orgsWrite, orgHandler.CreateOrganization)
+		secured.GET("/orgs", orgsRead, orgHandler.ListOrganizations)
+		secured.GET("/orgs/:id", orgsRead, orgHandler.GetOrganization)
+		secured.GET("/users", usersRead, userHandler.ListUsers)
+	}
+
+	// SCIM 2.0 provisioning for identity providers, which authenticate with
+	// an API key. Discovery is public.
+	scimHandler := handlers.NewSCIMHandler(cfg.Users, cfg.Passwords, cfg.Groups, cfg.RequireIfMatch)
+	scim := r.Group("/scim/v2")
+	{
+		scim.GET("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+		scim.GET("/ResourceTypes", scimHandler.ResourceTypes)
+		scim.GET("/ResourceTypes/:id", scimHandler.ResourceType)
+		scim.GET("/Schemas", scimHandler.Schemas)
+		scim.GET("/Schemas/:id", scimHandler.Schema)
+	}
+
+	provisioning := scim.Group("", TenantMiddleware(cfg.Organizations), AuthMiddleware(cfg.Auth, cfg.APIKeys), MFAPolicyMiddleware(cfg.MFA))
+	{
+		provisioning.POST("/Users", usersWrite, scimHandler.CreateUser)
+		provisioning.GET("/Users", usersRead, scimHandler.ListUsers)
+		provisioning.GET("/Users/:id", usersRead, scimHandler.GetUser)
+		provisioning.PUT("/Users/:id", usersWrite, scimHandler.ReplaceUser)
+		provisioning.PATCH("/Users/:id", usersWrite, scimHandler.PatchUser)
+		provisioning.DELETE("/Users/:id", usersWrite, scimHandler.DeleteUser)
+
+		if cfg.Groups != nil {
+			provisioning.POST("/Groups", usersWrite, scimHandler.CreateGroup)
+			provisioning.GET("/Groups", usersRead, scimHandler.ListGroups)
+			provisioning.GET("/Groups/:id", usersRead, scimHandler.GetGroup)
+			provisioning.PUT("/Groups/:id", usersWrite, scimHandler.ReplaceGroup)
+			provisioning.PATCH("/Groups/:id", usersWrite, scimHandler.PatchGroup)
+			provisioning.DELETE("/Groups/:id", usersWrite, scimHandler.DeleteGroup)
+		}
 	}
 
 	return r
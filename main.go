@@ -1,27 +1,126 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
+	"userHub/internal/auth"
 	"userHub/internal/config"
+	"userHub/internal/mail"
 	apphttp "userHub/internal/web"
 	"userHub/internal/service"
 	"userHub/internal/store"
+	"userHub/pkg/password"
 	"userHub/pkg/validator"
 )
 
 func main() {
 	validator.Init()
+	validator.SetPasswordPolicy(config.LoadPasswordPolicy())
 
 	// Initialize database connection
 	db := config.InitDB()
 
+	// Setup token signing from the environment
+	authCfg := config.LoadAuthConfig()
+	tokens, err := auth.NewJWTManager(authCfg)
+	if err != nil {
+		log.Fatal("Failed to configure JWT:", err)
+	}
+
+	// Setup outgoing mail
+	mailer, err := mail.New(config.LoadMailConfig())
+	if err != nil {
+		log.Fatal("Failed to configure mail:", err)
+	}
+	baseURL := config.LoadAppBaseURL()
+
 	// Setup repository and service layers
 	userRepo := store.NewUserStore(db)
-	userService := service.NewUserService(userRepo)
+	auditRepo := store.NewAuditStore(db)
+	groupRepo := store.NewGroupStore(db)
+	authorizer := service.NewAuthorizer(groupRepo)
+	verificationService := service.NewEmailVerificationService(userRepo, store.NewEmailVerificationStore(db), authorizer, auditRepo, mailer, config.LoadEmailVerificationTTL(), baseURL)
+	userService := service.NewUserService(userRepo, authorizer, auditRepo, verificationService, config.LoadUserRetention(), config.LoadCursorSecret())
+	auditService := service.NewAuditService(auditRepo, authorizer)
+	passwordService := service.NewPasswordService(userRepo, password.NewHasher(config.LoadPasswordParams()))
+	refreshRepo := store.NewRefreshTokenStore(db)
+	orgRepo := store.NewOrganizationStore(db)
+	mfaService := service.NewMFAService(userRepo, store.NewRecoveryCodeStore(db), orgRepo, authorizer, auditRepo, config.LoadMFAIssuer())
+	apiKeyRepo := store.NewAPIKeyStore(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, authorizer, auditRepo, config.LoadAPIKeyMaxTTL())
+	impersonationRepo := store.NewImpersonationStore(db)
+	authService := service.NewAuthService(userRepo, authorizer, tokens, passwordService, refreshRepo, mfaService, impersonationRepo, authCfg.RefreshTTL)
+	oauthTokenRepo := store.NewOAuthTokenStore(db)
+	resetTTL, resetLimit := config.LoadPasswordReset()
+	resetService := service.NewPasswordResetService(userRepo, store.NewPasswordResetStore(db), passwordService, refreshRepo, oauthTokenRepo, apiKeyRepo, auditRepo, mailer, resetTTL, resetLimit, baseURL)
+	orgService := service.NewOrganizationService(orgRepo, userService, authorizer)
+	codeTTL, oauthAccessTTL, oauthRefreshTTL := config.LoadOAuthTTLs()
+	oidcCfg := config.LoadOIDC()
+	// A configured key signs on its own; otherwise keys are kept in the
+	// database, so restarts and replicas publish the same JWKS.
+	var keyRing *auth.KeyRing
+	if oidcCfg.SigningKeyPEM != nil {
+		keyRing, err = auth.NewKeyRing(oauthAccessTTL, oidcCfg.SigningKeyPEM)
+	} else {
+		keyRing, err = auth.NewStoredKeyRing(context.Background(), store.NewSigningKeyStore(db), oauthAccessTTL, oidcCfg.KeyRotation)
+	}
+	if err != nil {
+		log.Fatal("Failed to configure OIDC signing keys:", err)
+	}
+	oidcService := service.NewOIDCService(keyRing, oauthTokenRepo, userRepo, oidcCfg.Issuer, oauthAccessTTL)
+	oauthService := service.NewOAuthService(store.NewOAuthClientStore(db), store.NewOAuthCodeStore(db), oauthTokenRepo, userRepo, authorizer, auditRepo, oidcService, codeTTL, oauthAccessTTL, oauthRefreshTTL)
+	groupService := service.NewGroupService(groupRepo, userRepo, authorizer, auditRepo)
+	invitationSecret, invitationTTL := config.LoadInvitations()
+	invitationService := service.NewInvitationService(store.NewInvitationStore(db), userRepo, userService, authorizer, auditRepo, mailer, invitationSecret, invitationTTL, baseURL)
+	impersonationService := service.NewImpersonationService(impersonationRepo, userRepo, authorizer, auditRepo, tokens, config.LoadImpersonationTTL())
+	emailChangeService := service.NewEmailChangeService(userRepo, store.NewEmailChangeStore(db), auditRepo, mailer, config.LoadEmailChangeTTL(), baseURL)
+	searchService := service.NewUserSearchService(store.NewUserSearcher(db), authorizer)
+
+	// Seed the default organization and, if configured, its first administrator
+	if err := service.BootstrapOrganization(context.Background(), orgRepo); err != nil {
+		log.Fatal("Failed to bootstrap default organization:", err)
+	}
+	adminEmail, adminPassword := config.LoadBootstrapAdmin()
+	if err := service.BootstrapAdmin(context.Background(), userRepo, passwordService, adminEmail, adminPassword); err != nil {
+		log.Fatal("Failed to bootstrap admin:", err)
+	}
+
+	// Sync stored signing keys: rotate when due and pick up keys other
+	// instances generated; retired keys stay in the JWKS until every token
+	// they signed has expired.
+	if oidcCfg.SigningKeyPEM == nil {
+		go func() {
+			for range time.Tick(auth.KeySyncInterval) {
+				if err := keyRing.Sync(context.Background()); err != nil {
+					log.Printf("oidc: sync signing keys: %v", err)
+				}
+			}
+		}()
+	}
 
 	// Setup router with all dependencies
-	router := apphttp.SetupRouter(userService)
+	router := apphttp.SetupRouter(apphttp.Config{
+		Users:         userService,
+		Passwords:     passwordService,
+		Auth:          authService,
+		Organizations: orgService,
+		Audit:         auditService,
+		EmailChanges:  emailChangeService,
+		Verifications: verificationService,
+		Resets:        resetService,
+		MFA:           mfaService,
+		APIKeys:       apiKeyService,
+		OAuth:         oauthService,
+		OIDC:          oidcService,
+		Groups:        groupService,
+		Invitations:   invitationService,
+		Impersonation: impersonationService,
+		Search:        searchService,
+
+		RequireIfMatch: config.LoadRequireIfMatch(),
+	})
 
 	// Start the server
 	log.Println("Server starting on :8080")
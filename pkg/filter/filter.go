@@ -0,0 +1,222 @@
+// Package filter implements a small expression language for filtering
+// records, such as
+//
+//	name co "smith" and (gender eq "female" or id gt 100)
+//
+// Expressions are parsed against a Schema of the fields they may name into
+// an Expr tree, which can be evaluated in memory (Eval) or translated into a
+// parameterized SQL condition (SQL).
+//
+// The grammar follows SCIM filters (RFC 7644 section 3.4.2.2):
+//
+//	expr    = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = [ "not" ] ( "(" expr ")" | compare )
+//	compare = field op value
+//	op      = eq | ne | co | sw | ew | gt | ge | lt | le
+//	value   = "string" | number | true | false
+//
+// Keywords and operators ignore case; string comparisons ignore case too.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of a field's values.
+type Type int
+
+const (
+	String Type = iota
+	Number
+	Bool
+	// Time values are written as RFC 3339 strings or dates like "2025-01-01".
+	Time
+)
+
+func (t Type) String() string {
+	switch t {
+	case Number:
+		return "number"
+	case Bool:
+		return "boolean"
+	case Time:
+		return "timestamp"
+	}
+	return "string"
+}
+
+// Schema whitelists the fields an expression may name, with their types.
+type Schema map[string]Type
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	Eq Op = "eq"
+	Ne Op = "ne"
+	Co Op = "co"
+	Sw Op = "sw"
+	Ew Op = "ew"
+	Gt Op = "gt"
+	Ge Op = "ge"
+	Lt Op = "lt"
+	Le Op = "le"
+)
+
+// operators lists the operators and the field types they apply to.
+var operators = map[Op][]Type{
+	Eq: {String, Number, Bool, Time},
+	Ne: {String, Number, Bool, Time},
+	Co: {String},
+	Sw: {String},
+	Ew: {String},
+	Gt: {String, Number, Time},
+	Ge: {String, Number, Time},
+	Lt: {String, Number, Time},
+	Le: {String, Number, Time},
+}
+
+// Expr is a node of a parsed expression: And, Or, Not or Compare. Its
+// String form is a canonical, fully parenthesized expression.
+type Expr interface {
+	fmt.Stringer
+	expr()
+}
+
+// And matches when both sides do.
+type And struct{ Left, Right Expr }
+
+// Or matches when either side does.
+type Or struct{ Left, Right Expr }
+
+// Not matches when its operand does not.
+type Not struct{ Expr Expr }
+
+// Compare compares a field with a constant. Value is a string, float64,
+// bool or time.Time according to the field's Type.
+type Compare struct {
+	Field string
+	Type  Type
+	Op    Op
+	Value any
+}
+
+func (And) expr()     {}
+func (Or) expr()      {}
+func (Not) expr()     {}
+func (Compare) expr() {}
+
+func (e And) String() string { return "(" + e.Left.String() + " and " + e.Right.String() + ")" }
+func (e Or) String() string  { return "(" + e.Left.String() + " or " + e.Right.String() + ")" }
+func (e Not) String() string { return "not (" + e.Expr.String() + ")" }
+
+func (e Compare) String() string {
+	var value string
+	switch v := e.Value.(type) {
+	case string:
+		value = strconv.Quote(v)
+	case time.Time:
+		value = strconv.Quote(v.Format(time.RFC3339Nano))
+	default:
+		value = fmt.Sprint(v)
+	}
+	return e.Field + " " + string(e.Op) + " " + value
+}
+
+// Error is a malformed expression. Pos is the 1-based byte offset of the
+// offending token in the input.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+func errorAt(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Eval reports whether the record whose fields value returns satisfies e.
+// value must return values of the types the schema declared; nil matches
+// nothing but ne.
+func Eval(e Expr, value func(field string) any) bool {
+	switch e := e.(type) {
+	case And:
+		return Eval(e.Left, value) && Eval(e.Right, value)
+	case Or:
+		return Eval(e.Left, value) || Eval(e.Right, value)
+	case Not:
+		return !Eval(e.Expr, value)
+	case Compare:
+		if e.Op == Ne {
+			return !Eval(Compare{Field: e.Field, Type: e.Type, Op: Eq, Value: e.Value}, value)
+		}
+		return compare(value(e.Field), e.Op, e.Value)
+	}
+	return false
+}
+
+func compare(have any, op Op, want any) bool {
+	switch w := want.(type) {
+	case string:
+		h, ok := have.(string)
+		if !ok {
+			return false
+		}
+		h, w = strings.ToLower(h), strings.ToLower(w)
+		switch op {
+		case Co:
+			return strings.Contains(h, w)
+		case Sw:
+			return strings.HasPrefix(h, w)
+		case Ew:
+			return strings.HasSuffix(h, w)
+		}
+		return ordered(strings.Compare(h, w), op)
+	case float64:
+		h, ok := have.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case h < w:
+			return ordered(-1, op)
+		case h > w:
+			return ordered(1, op)
+		}
+		return ordered(0, op)
+	case bool:
+		h, ok := have.(bool)
+		return ok && op == Eq && h == w
+	case time.Time:
+		h, ok := have.(time.Time)
+		if !ok {
+			return false
+		}
+		return ordered(h.Compare(w), op)
+	}
+	return false
+}
+
+// ordered applies a comparison operator to the result of a three-way compare.
+func ordered(cmp int, op Op) bool {
+	switch op {
+	case Eq:
+		return cmp == 0
+	case Gt:
+		return cmp > 0
+	case Ge:
+		return cmp >= 0
+	case Lt:
+		return cmp < 0
+	case Le:
+		return cmp <= 0
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package filter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/pkg/filter"
+)
+
+var schema = filter.Schema{
+	"name":     filter.String,
+	"id":       filter.Number,
+	"verified": filter.Bool,
+	"created":  filter.Time,
+}
+
+func TestParse_Precedence(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{`id eq 1 or id eq 2 and id eq 3`, `(id eq 1 or (id eq 2 and id eq 3))`},
+		{`id eq 1 and id eq 2 or id eq 3`, `((id eq 1 and id eq 2) or id eq 3)`},
+		{`id eq 1 and (id eq 2 or id eq 3)`, `(id eq 1 and (id eq 2 or id eq 3))`},
+		{`id eq 1 or id eq 2 or id eq 3`, `((id eq 1 or id eq 2) or id eq 3)`},
+		{`not (id eq 1) and id eq 2`, `(not (id eq 1) and id eq 2)`},
+		{`not (id eq 1 or id eq 2)`, `not ((id eq 1 or id eq 2))`},
+		{`name CO "x" OR verified EQ true`, `(name co "x" or verified eq true)`},
+		{`((((name eq "x"))))`, `name eq "x"`},
+		{`created ge "2025-01-01"`, `created ge "2025-01-01T00:00:00Z"`},
+		{`created lt "2025-01-01T12:00:00+02:00"`, `created lt "2025-01-01T10:00:00Z"`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			e, err := filter.Parse(tc.in, schema)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, e.String())
+		})
+	}
+}
+
+func TestParse_ErrorPositions(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		pos int
+		msg string
+	}{
+		{``, 1, "expression is empty"},
+		{`  `, 1, "expression is empty"},
+		{`name eq`, 8, "unexpected end of expression"},
+		{`name is "x"`, 6, `unexpected "is"`},
+		{`email eq "x"`, 1, `unknown field "email"`},
+		{`id eq 1 and phone eq "x"`, 13, `unknown field "phone"`},
+		{`name eq "x`, 9, "unterminated string"},
+		{`name eq "\q"`, 9, "invalid string"},
+		{`name eq x`, 9, "expected a string value"},
+		{`id eq "1"`, 7, "expected a number value"},
+		{`verified eq yes`, 13, "expected a boolean value"},
+		{`created gt "yesterday"`, 12, `expected an RFC 3339 timestamp or a date like "2025-01-01"`},
+		{`id co 1`, 4, `operator co does not apply to number field "id"`},
+		{`verified gt true`, 10, `operator gt does not apply to boolean field "verified"`},
+		{`(name eq "x"`, 13, "unexpected end of expression"},
+		{`name eq "x")`, 12, `unexpected ")"`},
+		{`name eq "x" name eq "y"`, 13, `unexpected "name"`},
+		{`not name eq "x"`, 5, `unexpected "name"`},
+		{`and name eq "x"`, 1, `unknown field "and"`},
+		// Field names are case-sensitive, unlike keywords and operators.
+		{`id EQ 1 AND Name co "x"`, 13, `unknown field "Name"`},
+		{`id eq 1 or`, 11, "unexpected end of expression"},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := filter.Parse(tc.in, schema)
+			var ferr *filter.Error
+			require.True(t, errors.As(err, &ferr), "got %v", err)
+			assert.Equal(t, tc.pos, ferr.Pos)
+			assert.Equal(t, tc.msg, ferr.Msg)
+		})
+	}
+}
+
+func TestParse_Limits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + `id eq 1` + strings.Repeat(")", depth)
+	}
+	_, err := filter.Parse(nested(32), schema)
+	require.NoError(t, err)
+
+	_, err = filter.Parse(nested(33), schema)
+	var ferr *filter.Error
+	require.True(t, errors.As(err, &ferr), "got %v", err)
+	assert.Equal(t, 33, ferr.Pos)
+	assert.Equal(t, "expression is nested more than 32 levels deep", ferr.Msg)
+
+	// "not" needs parentheses, so it counts towards the depth too.
+	_, err = filter.Parse(strings.Repeat("not (", 33)+`id eq 1`+strings.Repeat(")", 33), schema)
+	require.True(t, errors.As(err, &ferr), "got %v", err)
+	assert.Contains(t, ferr.Msg, "nested")
+
+	// Siblings do not add up.
+	_, err = filter.Parse(strings.TrimSuffix(strings.Repeat(nested(20)+" or ", 5), " or "), schema)
+	require.NoError(t, err)
+
+	prefix := `name eq "`
+	longest := prefix + strings.Repeat("x", filter.MaxLength-len(prefix)-1) + `"`
+	require.Len(t, longest, filter.MaxLength)
+	_, err = filter.Parse(longest, schema)
+	require.NoError(t, err)
+
+	_, err = filter.Parse(longest+" ", schema)
+	require.True(t, errors.As(err, &ferr), "got %v", err)
+	assert.Equal(t, filter.MaxLength+1, ferr.Pos)
+	assert.Equal(t, "expression is longer than 1024 characters", ferr.Msg)
+}
+
+func TestSQL(t *testing.T) {
+	column := func(field string) string { return "t." + field }
+	for _, tc := range []struct {
+		in   string
+		sql  string
+		args []any
+	}{
+		{`name eq "Ann"`, `(t.name = ?)`, []any{"Ann"}},
+		{`name ne "Ann"`, `(t.name IS NULL OR t.name <> ?)`, []any{"Ann"}},
+		{`id gt 5 and id le 10`, `((t.id > ?) AND (t.id <= ?))`, []any{5.0, 10.0}},
+		{`id ge 5 or id lt 1`, `((t.id >= ?) OR (t.id < ?))`, []any{5.0, 1.0}},
+		{`not (verified eq true)`, `NOT (t.verified = ?)`, []any{true}},
+		{`name co "a_b"`, `(t.name LIKE ?)`, []any{`%a\_b%`}},
+		{`name sw "50%"`, `(t.name LIKE ?)`, []any{`50\%%`}},
+		{`name ew "\\"`, `(t.name LIKE ?)`, []any{`%\\`}},
+		{`id eq 1 or id eq 2 and name eq "x"`, `((t.id = ?) OR ((t.id = ?) AND (t.name = ?)))`, []any{1.0, 2.0, "x"}},
+		{`created ge "2025-01-01"`, `(t.created >= ?)`, []any{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		// Values are bound as parameters, never spliced into the SQL.
+		{`name eq "x') OR 1=1 --"`, `(t.name = ?)`, []any{"x') OR 1=1 --"}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			e, err := filter.Parse(tc.in, schema)
+			require.NoError(t, err)
+			sql, args := filter.SQL(e, column)
+			assert.Equal(t, tc.sql, sql)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	for in, want := range map[string]string{
+		"plain":    "plain",
+		"100%":     `100\%`,
+		"a_b":      `a\_b`,
+		`back\`:    `back\\`,
+		`\%_`:      `\\\%\_`,
+		"":         "",
+		"ünïcödé%": `ünïcödé\%`,
+	} {
+		assert.Equal(t, want, filter.EscapeLike(in), in)
+	}
+}
+
+func TestEval(t *testing.T) {
+	record := map[string]any{
+		"name":     "Ann Smith",
+		"id":       42.0,
+		"verified": true,
+		"created":  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	value := func(field string) any { return record[field] }
+	unset := func(string) any { return nil }
+
+	for _, tc := range []struct {
+		in   string
+		want bool
+	}{
+		{`name eq "ann smith"`, true},
+		{`name ne "ann smith"`, false},
+		{`name co "SMI"`, true},
+		{`name sw "ann"`, true},
+		{`name ew "ann"`, false},
+		{`name gt "Ann"`, true},
+		{`name lt "Ann"`, false},
+		{`name co "%"`, false},
+		{`id eq 42`, true},
+		{`id gt 41.5 and id lt 42.5`, true},
+		{`id ge 43`, false},
+		{`id le 42`, true},
+		{`verified eq true`, true},
+		{`verified ne true`, false},
+		{`created gt "2025-03-01"`, true},
+		{`created le "2025-03-01T12:00:00Z"`, true},
+		{`created lt "2025-03-01T13:00:00+02:00"`, false},
+		{`id eq 1 or id eq 42 and verified eq false`, false},
+		{`(id eq 1 or id eq 42) and verified eq true`, true},
+		{`not (id eq 1) and not (name co "bob")`, true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			e, err := filter.Parse(tc.in, schema)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, filter.Eval(e, value))
+		})
+	}
+
+	// Missing values match nothing but ne.
+	for in, want := range map[string]bool{
+		`name eq "x"`:       false,
+		`name ne "x"`:       true,
+		`id lt 100`:         false,
+		`verified eq false`: false,
+		`not (name co "x")`: true,
+	} {
+		e, err := filter.Parse(in, schema)
+		require.NoError(t, err)
+		assert.Equal(t, want, filter.Eval(e, unset), in)
+	}
+}
@@ -0,0 +1,270 @@
+package filter
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxLength bounds the size of an expression.
+	MaxLength = 1024
+	// maxDepth bounds nesting, so a hostile expression cannot exhaust the stack.
+	maxDepth = 32
+)
+
+// Parse parses s into an expression over the fields of schema. Unknown
+// fields, operators that do not apply to a field's type and values of the
+// wrong type are rejected with an *Error.
+func Parse(s string, schema Schema) (Expr, error) {
+	if len(s) > MaxLength {
+		return nil, errorAt(MaxLength+1, "expression is longer than %d characters", MaxLength)
+	}
+	p, err := newParser(s, schema)
+	if err != nil {
+		return nil, err
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.unexpected()
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based offset into the input
+}
+
+type parser struct {
+	input  string
+	schema Schema
+	tokens []token
+	i      int
+	depth  int
+}
+
+func newParser(s string, schema Schema) (*parser, error) {
+	p := &parser{input: s, schema: schema}
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{kind: tokLParen, text: "(", pos: i + 1})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{kind: tokRParen, text: ")", pos: i + 1})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, errorAt(i+1, "unterminated string")
+			}
+			var text string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &text); err != nil {
+				return nil, errorAt(i+1, "invalid string")
+			}
+			p.tokens = append(p.tokens, token{kind: tokString, text: text, pos: i + 1})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t\n\r()\"", rune(s[end])) {
+				end++
+			}
+			p.tokens = append(p.tokens, token{kind: tokWord, text: s[i:end], pos: i + 1})
+			i = end
+		}
+	}
+	if len(p.tokens) == 0 {
+		return nil, errorAt(1, "expression is empty")
+	}
+	return p, nil
+}
+
+func (p *parser) peek() token {
+	if p.i >= len(p.tokens) {
+		return token{kind: tokEOF, pos: len(p.input) + 1}
+	}
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) done() bool {
+	return p.peek().kind == tokEOF
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+func (p *parser) unexpected() *Error {
+	return p.unexpectedToken(p.peek())
+}
+
+func (p *parser) unexpectedToken(t token) *Error {
+	if t.kind == tokEOF {
+		return errorAt(t.pos, "unexpected end of expression")
+	}
+	return errorAt(t.pos, "unexpected %q", t.text)
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		p.next()
+		if p.peek().kind != tokLParen {
+			return nil, p.unexpected()
+		}
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: e}, nil
+	}
+
+	if open := p.peek(); open.kind == tokLParen {
+		if p.depth++; p.depth > maxDepth {
+			return nil, errorAt(open.pos, "expression is nested more than %d levels deep", maxDepth)
+		}
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.unexpectedToken(t)
+		}
+		p.depth--
+		return e, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (Expr, error) {
+	field := p.next()
+	if field.kind != tokWord {
+		return nil, p.unexpectedToken(field)
+	}
+	typ, ok := p.schema[field.text]
+	if !ok {
+		return nil, errorAt(field.pos, "unknown field %q", field.text)
+	}
+
+	opTok := p.next()
+	op := Op(strings.ToLower(opTok.text))
+	types, known := operators[op]
+	if opTok.kind != tokWord || !known {
+		return nil, p.unexpectedToken(opTok)
+	}
+	if !slices.Contains(types, typ) {
+		return nil, errorAt(opTok.pos, "operator %s does not apply to %s field %q", op, typ, field.text)
+	}
+
+	valTok := p.next()
+	value, err := p.value(valTok, typ)
+	if err != nil {
+		return nil, err
+	}
+	return Compare{Field: field.text, Type: typ, Op: op, Value: value}, nil
+}
+
+// value converts a literal to the type of the field it is compared with.
+func (p *parser) value(t token, typ Type) (any, error) {
+	if t.kind == tokEOF || (t.kind != tokString && t.kind != tokWord) {
+		return nil, p.unexpectedToken(t)
+	}
+	mismatch := errorAt(t.pos, "expected a %s value", typ)
+
+	switch typ {
+	case String:
+		if t.kind != tokString {
+			return nil, mismatch
+		}
+		return t.text, nil
+	case Number:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if t.kind != tokWord || err != nil {
+			return nil, mismatch
+		}
+		return n, nil
+	case Bool:
+		if t.kind != tokWord || (t.text != "true" && t.text != "false") {
+			return nil, mismatch
+		}
+		return t.text == "true", nil
+	case Time:
+		if t.kind != tokString {
+			return nil, mismatch
+		}
+		if ts, err := time.Parse(time.RFC3339, t.text); err == nil {
+			return ts.UTC(), nil
+		}
+		if ts, err := time.Parse(time.DateOnly, t.text); err == nil {
+			return ts, nil
+		}
+		return nil, errorAt(t.pos, "expected an RFC 3339 timestamp or a date like \"2025-01-01\"")
+	}
+	return nil, mismatch
+}
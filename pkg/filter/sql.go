@@ -0,0 +1,67 @@
+package filter
+
+import (
+	"strings"
+)
+
+// SQL translates e into a parameterized SQL condition. column maps each
+// field to the SQL expression it is stored in; field names never reach the
+// SQL themselves. Values are bound as parameters, and LIKE patterns escape
+// their wildcards with a backslash. String comparisons follow the column's
+// collation.
+func SQL(e Expr, column func(field string) string) (string, []any) {
+	var b strings.Builder
+	var args []any
+	writeSQL(&b, &args, e, column)
+	return b.String(), args
+}
+
+func writeSQL(b *strings.Builder, args *[]any, e Expr, column func(string) string) {
+	switch e := e.(type) {
+	case And:
+		b.WriteString("(")
+		writeSQL(b, args, e.Left, column)
+		b.WriteString(" AND ")
+		writeSQL(b, args, e.Right, column)
+		b.WriteString(")")
+	case Or:
+		b.WriteString("(")
+		writeSQL(b, args, e.Left, column)
+		b.WriteString(" OR ")
+		writeSQL(b, args, e.Right, column)
+		b.WriteString(")")
+	case Not:
+		b.WriteString("NOT ")
+		writeSQL(b, args, e.Expr, column)
+	case Compare:
+		col := column(e.Field)
+		switch e.Op {
+		case Co, Sw, Ew:
+			pattern := EscapeLike(e.Value.(string))
+			switch e.Op {
+			case Co:
+				pattern = "%" + pattern + "%"
+			case Sw:
+				pattern += "%"
+			case Ew:
+				pattern = "%" + pattern
+			}
+			b.WriteString("(" + col + " LIKE ?)")
+			*args = append(*args, pattern)
+		case Ne:
+			// NULL is unequal to every value, as in Eval.
+			b.WriteString("(" + col + " IS NULL OR " + col + " <> ?)")
+			*args = append(*args, e.Value)
+		default:
+			b.WriteString("(" + col + " " + sqlOperators[e.Op] + " ?)")
+			*args = append(*args, e.Value)
+		}
+	}
+}
+
+var sqlOperators = map[Op]string{Eq: "=", Gt: ">", Ge: ">=", Lt: "<", Le: "<="}
+
+// EscapeLike escapes the LIKE wildcards in s with a backslash.
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,460 @@
+// Package scim implements the parts of SCIM 2.0 (RFC 7644) that do not
+// depend on any particular resource: filter expressions, attribute paths and
+// PATCH operations, all evaluated against a resource's JSON representation
+// decoded into a map.
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error types (RFC 7644 section 3.12), reported as "scimType".
+const (
+	ErrInvalidFilter = "invalidFilter"
+	ErrInvalidPath   = "invalidPath"
+	ErrInvalidSyntax = "invalidSyntax"
+	ErrInvalidValue  = "invalidValue"
+	ErrNoTarget      = "noTarget"
+	ErrMutability    = "mutability"
+	ErrUniqueness    = "uniqueness"
+	ErrTooMany       = "tooMany"
+)
+
+// Error is a client error in a SCIM request.
+type Error struct {
+	Type   string
+	Detail string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("scim %s: %s", e.Type, e.Detail)
+}
+
+func errorf(typ, format string, args ...any) *Error {
+	return &Error{Type: typ, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Filter is a parsed filter expression.
+type Filter interface {
+	// Match reports whether the resource satisfies the filter.
+	Match(resource map[string]any) bool
+}
+
+// ParseFilter parses a filter such as `userName eq "bjensen" and active eq true`.
+// It supports every comparison operator, "pr", "and", "or", "not",
+// parentheses and value filters like `emails[type eq "work"]`. String
+// comparisons ignore case.
+func ParseFilter(s string) (Filter, error) {
+	p, err := newParser(s, ErrInvalidFilter)
+	if err != nil {
+		return nil, err
+	}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.unexpected()
+	}
+	return f, nil
+}
+
+// Equality is a comparison `Attr eq "Value"`.
+type Equality struct {
+	Attr  string
+	Value string
+}
+
+// Equalities returns the string equalities every resource matching f must
+// satisfy: f itself, or terms joined to the rest of f by "and". A server can
+// look resources up by them and match f against the candidates only.
+func Equalities(f Filter) []Equality {
+	switch f := f.(type) {
+	case comparison:
+		if s, ok := f.value.(string); ok && f.op == "eq" {
+			return []Equality{{Attr: f.attr, Value: s}}
+		}
+	case logical:
+		if !f.or {
+			return append(Equalities(f.left), Equalities(f.right)...)
+		}
+	}
+	return nil
+}
+
+// Path is a PATCH target: an attribute, optionally narrowed to the values of
+// a multi-valued attribute matching Filter, and to one of their sub-attributes.
+type Path struct {
+	Attr   string
+	Filter Filter
+	Sub    string
+}
+
+// ParsePath parses a PATCH path such as `name.givenName` or
+// `members[value eq "2"]`.
+func ParsePath(s string) (*Path, error) {
+	p, err := newParser(s, ErrInvalidPath)
+	if err != nil {
+		return nil, err
+	}
+	attr := p.next()
+	if attr.kind != tokWord {
+		return nil, p.unexpectedToken(attr)
+	}
+	path := &Path{Attr: attr.text}
+	if p.peek().kind == tokLBracket {
+		p.next()
+		if path.Filter, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRBracket {
+			return nil, p.unexpectedToken(t)
+		}
+		if t := p.peek(); t.kind == tokWord && strings.HasPrefix(t.text, ".") && len(t.text) > 1 {
+			p.next()
+			path.Sub = t.text[1:]
+		}
+	}
+	if !p.done() {
+		return nil, p.unexpected()
+	}
+	return path, nil
+}
+
+type logical struct {
+	or          bool
+	left, right Filter
+}
+
+func (f logical) Match(r map[string]any) bool {
+	if f.or {
+		return f.left.Match(r) || f.right.Match(r)
+	}
+	return f.left.Match(r) && f.right.Match(r)
+}
+
+type negation struct{ f Filter }
+
+func (f negation) Match(r map[string]any) bool { return !f.f.Match(r) }
+
+// valueFilter matches when some value of a multi-valued attribute does.
+type valueFilter struct {
+	attr string
+	f    Filter
+}
+
+func (f valueFilter) Match(r map[string]any) bool {
+	for _, v := range elements(r, f.attr) {
+		if m, ok := v.(map[string]any); ok && f.f.Match(m) {
+			return true
+		}
+	}
+	return false
+}
+
+type comparison struct {
+	attr  string
+	op    string
+	value any
+}
+
+func (f comparison) Match(r map[string]any) bool {
+	values := leaves(r, f.attr)
+	if f.op == "pr" {
+		for _, v := range values {
+			if v != nil && v != "" {
+				return true
+			}
+		}
+		return false
+	}
+	if f.op == "ne" {
+		return !comparison{attr: f.attr, op: "eq", value: f.value}.Match(r)
+	}
+	if f.value == nil && f.op == "eq" {
+		return len(values) == 0
+	}
+	for _, v := range values {
+		if compare(v, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(have any, op string, want any) bool {
+	switch w := want.(type) {
+	case string:
+		h, ok := have.(string)
+		if !ok {
+			return false
+		}
+		h, w = strings.ToLower(h), strings.ToLower(w)
+		switch op {
+		case "eq":
+			return h == w
+		case "co":
+			return strings.Contains(h, w)
+		case "sw":
+			return strings.HasPrefix(h, w)
+		case "ew":
+			return strings.HasSuffix(h, w)
+		case "gt":
+			return h > w
+		case "ge":
+			return h >= w
+		case "lt":
+			return h < w
+		case "le":
+			return h <= w
+		}
+	case float64:
+		h, ok := have.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return h == w
+		case "gt":
+			return h > w
+		case "ge":
+			return h >= w
+		case "lt":
+			return h < w
+		case "le":
+			return h <= w
+		}
+	case bool:
+		h, ok := have.(bool)
+		return ok && op == "eq" && h == w
+	}
+	return false
+}
+
+var operators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true, "pr": true,
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based offset into the input
+}
+
+type parser struct {
+	input   string
+	errType string
+	tokens  []token
+	i       int
+}
+
+func newParser(s, errType string) (*parser, error) {
+	p := &parser{input: s, errType: errType}
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			kind := map[byte]tokenKind{'(': tokLParen, ')': tokRParen, '[': tokLBracket, ']': tokRBracket}[c]
+			p.tokens = append(p.tokens, token{kind: kind, text: string(c), pos: i + 1})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, errorf(errType, "unterminated string at position %d", i+1)
+			}
+			var text string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &text); err != nil {
+				return nil, errorf(errType, "invalid string at position %d", i+1)
+			}
+			p.tokens = append(p.tokens, token{kind: tokString, text: text, pos: i + 1})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t\n\r()[]\"", rune(s[end])) {
+				end++
+			}
+			p.tokens = append(p.tokens, token{kind: tokWord, text: s[i:end], pos: i + 1})
+			i = end
+		}
+	}
+	if len(p.tokens) == 0 {
+		return nil, errorf(errType, "expression is empty")
+	}
+	return p, nil
+}
+
+func (p *parser) peek() token {
+	if p.i >= len(p.tokens) {
+		return token{kind: tokEOF, pos: len(p.input) + 1}
+	}
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) done() bool {
+	return p.peek().kind == tokEOF
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+func (p *parser) unexpected() *Error {
+	return p.unexpectedToken(p.peek())
+}
+
+func (p *parser) unexpectedToken(t token) *Error {
+	if t.kind == tokEOF {
+		return errorf(p.errType, "unexpected end of expression at position %d", t.pos)
+	}
+	return errorf(p.errType, "unexpected %q at position %d", t.text, t.pos)
+}
+
+func (p *parser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Filter, error) {
+	negate := false
+	if p.keyword("not") {
+		p.next()
+		negate = true
+		if p.peek().kind != tokLParen {
+			return nil, p.unexpected()
+		}
+	}
+
+	var f Filter
+	var err error
+	if p.peek().kind == tokLParen {
+		p.next()
+		if f, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.unexpectedToken(t)
+		}
+	} else if f, err = p.parseAttrExpr(); err != nil {
+		return nil, err
+	}
+
+	if negate {
+		return negation{f}, nil
+	}
+	return f, nil
+}
+
+func (p *parser) parseAttrExpr() (Filter, error) {
+	attr := p.next()
+	if attr.kind != tokWord {
+		return nil, p.unexpectedToken(attr)
+	}
+
+	if p.peek().kind == tokLBracket {
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRBracket {
+			return nil, p.unexpectedToken(t)
+		}
+		return valueFilter{attr: attr.text, f: f}, nil
+	}
+
+	opTok := p.next()
+	op := strings.ToLower(opTok.text)
+	if opTok.kind != tokWord || !operators[op] {
+		return nil, p.unexpectedToken(opTok)
+	}
+	if op == "pr" {
+		return comparison{attr: attr.text, op: op}, nil
+	}
+
+	valTok := p.next()
+	c := comparison{attr: attr.text, op: op}
+	switch {
+	case valTok.kind == tokString:
+		c.value = valTok.text
+	case valTok.kind == tokWord && valTok.text == "true":
+		c.value = true
+	case valTok.kind == tokWord && valTok.text == "false":
+		c.value = false
+	case valTok.kind == tokWord && valTok.text == "null":
+		if op != "eq" && op != "ne" {
+			return nil, errorf(p.errType, "null can only be compared with eq or ne at position %d", valTok.pos)
+		}
+	case valTok.kind == tokWord:
+		n, err := strconv.ParseFloat(valTok.text, 64)
+		if err != nil {
+			return nil, p.unexpectedToken(valTok)
+		}
+		c.value = n
+	default:
+		return nil, p.unexpectedToken(valTok)
+	}
+	if _, isBool := c.value.(bool); isBool && op != "eq" && op != "ne" {
+		return nil, errorf(p.errType, "booleans can only be compared with eq or ne at position %d", valTok.pos)
+	}
+	return c, nil
+}
@@ -0,0 +1,156 @@
+package scim_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/pkg/scim"
+)
+
+func testUser() map[string]any {
+	return map[string]any{
+		"schemas":     []any{"urn:ietf:params:scim:schemas:core:2.0:User"},
+		"userName":    "bjensen@example.com",
+		"displayName": `Babs "B" Jensen`,
+		"nickName":    `back\slash`,
+		"name":        map[string]any{"givenName": "Barbara", "familyName": "Jensen"},
+		"active":      true,
+		"loginCount":  42.0,
+		"emails": []any{
+			map[string]any{"value": "bjensen@example.com", "type": "work", "primary": true},
+			map[string]any{"value": "babs@home.test", "type": "home"},
+		},
+	}
+}
+
+func TestParseFilter_Match(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		want   bool
+	}{
+		// Comparisons; strings ignore case.
+		{`userName eq "BJENSEN@example.com"`, true},
+		{`userName ne "bjensen@example.com"`, false},
+		{`name.givenName sw "bar"`, true},
+		{`name.familyName ew "SEN"`, true},
+		{`name.familyName co "nse"`, true},
+		{`name.familyName gt "J"`, true},
+		{`name.familyName lt "J"`, false},
+		{`active eq true`, true},
+		{`active ne true`, false},
+		{`loginCount gt 41.5`, true},
+		{`loginCount le 41`, false},
+		{`loginCount eq "42"`, false},
+		{`userName pr`, true},
+		{`title pr`, false},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName sw "BJ"`, true},
+
+		// Multi-valued attributes and value filters.
+		{`emails eq "babs@home.test"`, true},
+		{`emails.type eq "home"`, true},
+		{`emails[type eq "work" and value co "example"]`, true},
+		{`emails[type eq "work" and value co "home"]`, false},
+		{`emails[primary eq true]`, true},
+
+		// Unknown attributes have no value.
+		{`title eq "boss"`, false},
+		{`title ne "boss"`, true},
+		{`title eq null`, true},
+		{`userName eq null`, false},
+		{`not (title pr)`, true},
+
+		// "and" binds tighter than "or"; "not" applies to one group.
+		{`userName eq "nobody" and active eq false or active eq true`, true},
+		{`active eq true or userName eq "nobody" and active eq false`, true},
+		{`userName eq "nobody" and (active eq false or active eq true)`, false},
+		{`not (active eq true) or name.givenName eq "barbara"`, true},
+		{`not (active eq true or name.givenName eq "barbara")`, false},
+		{`active EQ true AND userName PR`, true},
+
+		// String escapes follow JSON.
+		{`displayName co "\"B\""`, true},
+		{`nickName eq "back\\slash"`, true},
+		{`name.givenName eq "B\u0061rbara"`, true},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			f, err := scim.ParseFilter(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, f.Match(testUser()))
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		detail string
+	}{
+		{``, "expression is empty"},
+		{`   `, "expression is empty"},
+		{`userName eq`, "unexpected end of expression at position 12"},
+		{`userName foo "x"`, `unexpected "foo" at position 10`},
+		{`userName eq bjensen`, `unexpected "bjensen" at position 13`},
+		{`userName eq "x`, "unterminated string at position 13"},
+		{`userName eq "\x"`, "invalid string at position 13"},
+		{`(userName pr`, "unexpected end of expression at position 13"},
+		{`userName pr)`, `unexpected ")" at position 12`},
+		{`userName pr active pr`, `unexpected "active" at position 13`},
+		{`not userName pr`, `unexpected "userName" at position 5`},
+		{`emails[type eq "work"`, "unexpected end of expression at position 22"},
+		{`active gt true`, "booleans can only be compared with eq or ne at position 11"},
+		{`userName co null`, "null can only be compared with eq or ne at position 13"},
+		{`and userName pr`, `unexpected "userName" at position 5`},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			_, err := scim.ParseFilter(tc.filter)
+			var serr *scim.Error
+			require.True(t, errors.As(err, &serr), "got %v", err)
+			assert.Equal(t, scim.ErrInvalidFilter, serr.Type)
+			assert.Equal(t, tc.detail, serr.Detail)
+		})
+	}
+}
+
+func TestEqualities(t *testing.T) {
+	for _, tc := range []struct {
+		filter string
+		want   []scim.Equality
+	}{
+		{`userName eq "bjensen"`, []scim.Equality{{"userName", "bjensen"}}},
+		{`id eq "1" and (active eq true and displayName eq "Babs")`, []scim.Equality{{"id", "1"}, {"displayName", "Babs"}}},
+		{`userName eq "a" or userName eq "b"`, nil},
+		{`not (userName eq "a")`, nil},
+		{`userName ne "a"`, nil},
+		{`userName co "a"`, nil},
+		{`active eq true`, nil},
+		{`title eq null`, nil},
+		{`emails[value eq "a"]`, nil},
+	} {
+		f, err := scim.ParseFilter(tc.filter)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, scim.Equalities(f), tc.filter)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	path, err := scim.ParsePath("name.givenName")
+	require.NoError(t, err)
+	assert.Equal(t, "name.givenName", path.Attr)
+	assert.Nil(t, path.Filter)
+
+	path, err = scim.ParsePath(`emails[type eq "work"].value`)
+	require.NoError(t, err)
+	assert.Equal(t, "emails", path.Attr)
+	assert.Equal(t, "value", path.Sub)
+	assert.True(t, path.Filter.Match(map[string]any{"type": "Work"}))
+
+	for _, bad := range []string{``, `"emails"`, `members[value eq "2"`, `members[value eq "2"] extra`} {
+		_, err := scim.ParsePath(bad)
+		var serr *scim.Error
+		require.True(t, errors.As(err, &serr), "%q: got %v", bad, err)
+		assert.Equal(t, scim.ErrInvalidPath, serr.Type, bad)
+	}
+}
@@ -0,0 +1,271 @@
+package scim
+
+import (
+	"strings"
+)
+
+// PatchOp is one operation of a PATCH request (RFC 7644 section 3.5.2).
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// ApplyPatch applies ops, in order, to a resource's JSON representation.
+// Operation names are case-insensitive, as some identity providers send
+// "Replace" or "Add".
+func ApplyPatch(resource map[string]any, ops []PatchOp) error {
+	for _, op := range ops {
+		name := strings.ToLower(op.Op)
+		switch name {
+		case "add", "replace":
+			if op.Path == "" {
+				values, ok := op.Value.(map[string]any)
+				if !ok {
+					return errorf(ErrInvalidValue, "%s without a path needs an object value", name)
+				}
+				for attr, v := range values {
+					if err := patchAttr(resource, name, &Path{Attr: attr}, v); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		case "remove":
+			if op.Path == "" {
+				return errorf(ErrNoTarget, "remove needs a path")
+			}
+		default:
+			return errorf(ErrInvalidSyntax, "unknown operation %q", op.Op)
+		}
+
+		path, err := ParsePath(op.Path)
+		if err != nil {
+			return err
+		}
+		if err := patchAttr(resource, name, path, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchAttr(resource map[string]any, op string, path *Path, value any) error {
+	parent, segs := locate(resource, path.Attr)
+
+	// Walk to the object holding the last segment, creating it for add/replace.
+	for _, seg := range segs[:len(segs)-1] {
+		child, ok := getKey(parent, seg)
+		m, isMap := child.(map[string]any)
+		if !ok || !isMap {
+			if op == "remove" {
+				return nil
+			}
+			m = map[string]any{}
+			setKey(parent, seg, m)
+		}
+		parent = m
+	}
+	name := segs[len(segs)-1]
+
+	if path.Filter != nil {
+		return patchValues(parent, name, op, path, value)
+	}
+
+	current, exists := getKey(parent, name)
+	switch op {
+	case "remove":
+		// Some providers name the values to remove instead of filtering for them.
+		if list, ok := current.([]any); ok && value != nil {
+			setKey(parent, name, without(list, value))
+			return nil
+		}
+		deleteKey(parent, name)
+	case "add":
+		if list, ok := current.([]any); ok && exists {
+			if more, ok := value.([]any); ok {
+				setKey(parent, name, append(list, more...))
+			} else {
+				setKey(parent, name, append(list, value))
+			}
+			return nil
+		}
+		fallthrough
+	case "replace":
+		if cm, ok := current.(map[string]any); ok && exists {
+			if vm, ok := value.(map[string]any); ok {
+				for k, v := range vm {
+					setKey(cm, k, v)
+				}
+				return nil
+			}
+		}
+		setKey(parent, name, value)
+	}
+	return nil
+}
+
+// patchValues applies op to the values of a multi-valued attribute that
+// match path.Filter, or to their path.Sub sub-attribute.
+func patchValues(parent map[string]any, name, op string, path *Path, value any) error {
+	current, _ := getKey(parent, name)
+	list, _ := current.([]any)
+
+	kept := make([]any, 0, len(list))
+	matched := false
+	for _, v := range list {
+		m, ok := v.(map[string]any)
+		if !ok || !path.Filter.Match(m) {
+			kept = append(kept, v)
+			continue
+		}
+		matched = true
+		switch {
+		case op == "remove" && path.Sub == "":
+			continue
+		case op == "remove":
+			deleteKey(m, path.Sub)
+		case path.Sub != "":
+			setKey(m, path.Sub, value)
+		default:
+			vm, ok := value.(map[string]any)
+			if !ok {
+				return errorf(ErrInvalidValue, "%s of %s values needs an object value", op, name)
+			}
+			for k, val := range vm {
+				setKey(m, k, val)
+			}
+		}
+		kept = append(kept, m)
+	}
+	if !matched {
+		if op == "remove" {
+			return nil
+		}
+		return errorf(ErrNoTarget, "no %s value matches the filter", name)
+	}
+	setKey(parent, name, kept)
+	return nil
+}
+
+// without returns list minus the values in remove, compared by their
+// "value" sub-attribute.
+func without(list []any, remove any) []any {
+	drop := map[any]bool{}
+	for _, v := range collect(remove, nil) {
+		drop[v] = true
+	}
+	kept := make([]any, 0, len(list))
+	for _, v := range list {
+		values := collect(v, nil)
+		if len(values) == 1 && drop[values[0]] {
+			continue
+		}
+		kept = append(kept, v)
+	}
+	return kept
+}
+
+// locate resolves an attribute path to the object it is relative to and its
+// dot-separated name segments. A schema URN prefix selects the extension
+// object of that schema, when the resource has one, and is otherwise dropped.
+func locate(resource map[string]any, attr string) (map[string]any, []string) {
+	if _, ok := getKey(resource, attr); ok {
+		return resource, []string{attr}
+	}
+	if strings.HasPrefix(strings.ToLower(attr), "urn:") {
+		i := strings.LastIndex(attr, ":")
+		urn, rest := attr[:i], attr[i+1:]
+		if ext, ok := getKey(resource, urn); ok {
+			if m, ok := ext.(map[string]any); ok {
+				return m, strings.Split(rest, ".")
+			}
+		}
+		attr = rest
+	}
+	return resource, strings.Split(attr, ".")
+}
+
+// elements returns the values of attr, one per value of a multi-valued attribute.
+func elements(resource map[string]any, attr string) []any {
+	parent, segs := locate(resource, attr)
+	var v any = parent
+	for _, seg := range segs {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if v, ok = getKey(m, seg); !ok {
+			return nil
+		}
+	}
+	if list, ok := v.([]any); ok {
+		return list
+	}
+	return []any{v}
+}
+
+// leaves returns the simple values of attr. Sub-attributes of multi-valued
+// attributes are collected from every value, and a complex value stands for
+// its "value" sub-attribute, so `emails eq "x"` means `emails.value eq "x"`.
+func leaves(resource map[string]any, attr string) []any {
+	parent, segs := locate(resource, attr)
+	return collect(parent, segs)
+}
+
+func collect(v any, segs []string) []any {
+	switch t := v.(type) {
+	case []any:
+		var out []any
+		for _, e := range t {
+			out = append(out, collect(e, segs)...)
+		}
+		return out
+	case map[string]any:
+		if len(segs) == 0 {
+			inner, ok := getKey(t, "value")
+			if !ok {
+				return nil
+			}
+			return collect(inner, nil)
+		}
+		child, ok := getKey(t, segs[0])
+		if !ok {
+			return nil
+		}
+		return collect(child, segs[1:])
+	case nil:
+		return nil
+	}
+	if len(segs) > 0 {
+		return nil
+	}
+	return []any{v}
+}
+
+// Attribute names are case-insensitive (RFC 7643 section 2.1).
+
+func getKey(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func setKey(m map[string]any, key string, value any) {
+	deleteKey(m, key)
+	m[key] = value
+}
+
+func deleteKey(m map[string]any, key string) {
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			delete(m, k)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Highlight returns text, HTML-escaped, with every word that matches one of
+// the query terms wrapped in <em> tags, and whether any word did. Terms
+// must already be normalized, as Terms returns them.
+func Highlight(text string, terms []string) (string, bool) {
+	var b strings.Builder
+	found := false
+	inWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) }
+
+	for text != "" {
+		// Split off the next run of word or non-word runes.
+		first, _ := utf8.DecodeRuneInString(text)
+		word := inWord(first)
+		end := strings.IndexFunc(text, func(r rune) bool { return inWord(r) != word })
+		if end < 0 {
+			end = len(text)
+		}
+		run := text[:end]
+		text = text[end:]
+
+		if word && matchesAny(Terms(run), terms) {
+			found = true
+			b.WriteString("<em>" + html.EscapeString(run) + "</em>")
+			continue
+		}
+		b.WriteString(html.EscapeString(run))
+	}
+	return b.String(), found
+}
+
+// matchesAny reports whether any of words matches any of terms.
+func matchesAny(words, terms []string) bool {
+	for _, t := range terms {
+		if bestMatch(t, words) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"slices"
+	"sort"
+)
+
+// Hit is a document matching a query.
+type Hit struct {
+	ID uint
+	// Score is the mean over the query terms of how well the document's
+	// best word matches each, between 0 and 1.
+	Score float64
+}
+
+// Index is an inverted index from trigrams to the documents containing
+// them. It is not safe for concurrent use.
+type Index struct {
+	postings map[string]map[uint]struct{}
+	docs     map[uint][]string
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[uint]struct{}),
+		docs:     make(map[uint][]string),
+	}
+}
+
+// Add indexes the terms of fields under id, replacing whatever id held.
+func (ix *Index) Add(id uint, fields ...string) {
+	ix.Remove(id)
+
+	var words []string
+	for _, f := range fields {
+		for _, t := range Terms(f) {
+			if !slices.Contains(words, t) {
+				words = append(words, t)
+			}
+		}
+	}
+	if len(words) == 0 {
+		return
+	}
+
+	ix.docs[id] = words
+	for _, w := range words {
+		for g := range trigrams(w) {
+			docs, ok := ix.postings[g]
+			if !ok {
+				docs = make(map[uint]struct{})
+				ix.postings[g] = docs
+			}
+			docs[id] = struct{}{}
+		}
+	}
+}
+
+// Remove drops id from the index.
+func (ix *Index) Remove(id uint) {
+	words, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	delete(ix.docs, id)
+	for _, w := range words {
+		for g := range trigrams(w) {
+			delete(ix.postings[g], id)
+			if len(ix.postings[g]) == 0 {
+				delete(ix.postings, g)
+			}
+		}
+	}
+}
+
+// Search returns the documents matching at least one term of query, best
+// first and by ID among equals.
+func (ix *Index) Search(query string) []Hit {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	scores := make(map[uint]float64)
+	for _, t := range terms {
+		// Any word close enough to match shares a trigram with the term.
+		candidates := make(map[uint]struct{})
+		for g := range trigrams(t) {
+			for id := range ix.postings[g] {
+				candidates[id] = struct{}{}
+			}
+		}
+		for id := range candidates {
+			if s := bestMatch(t, ix.docs[id]); s > 0 {
+				scores[id] += s
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, Hit{ID: id, Score: s / float64(len(terms))})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits
+}
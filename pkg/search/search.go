@@ -0,0 +1,92 @@
+// Package search implements fuzzy full-text matching of short fields such
+// as names and email addresses.
+//
+// Text is normalized before it is matched: it is decomposed, stripped of
+// accents and lower-cased, so "José" and "jose" are the same term. Terms
+// are compared by the trigrams they share, which tolerates typos: "jhon"
+// still finds "john". Index is an inverted index over those trigrams, and
+// Highlight marks the words of a text that match a query.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MinSimilarity is the share of trigrams a word must have in common with a
+// query term to match it.
+const MinSimilarity = 0.3
+
+// Normalize decomposes s, drops its combining marks and lower-cases it.
+func Normalize(s string) string {
+	t := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		out = s
+	}
+	return strings.ToLower(out)
+}
+
+// Terms splits s into normalized words: runs of letters and digits. An
+// email address yields its local part and domain labels.
+func Terms(s string) []string {
+	return strings.FieldsFunc(Normalize(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams returns the set of three-rune windows of term, padded so that
+// its start and end count as well: "jo" yields "  j", " jo" and "jo ".
+func trigrams(term string) map[string]struct{} {
+	r := []rune("  " + term + " ")
+	grams := make(map[string]struct{}, len(r)-2)
+	for i := 0; i+3 <= len(r); i++ {
+		grams[string(r[i:i+3])] = struct{}{}
+	}
+	return grams
+}
+
+// similarity is the Jaccard index of the trigram sets of a and b.
+func similarity(a, b string) float64 {
+	ga, gb := trigrams(a), trigrams(b)
+	shared := 0
+	for g := range ga {
+		if _, ok := gb[g]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ga)+len(gb)-shared)
+}
+
+// match scores how well word matches the query term, from 1 for the same
+// word down to MinSimilarity, or 0 if it does not match. A word the term is
+// a prefix of always matches and outranks a merely similar one, so results
+// keep up with a query that is still being typed.
+func match(term, word string) float64 {
+	if term == word {
+		return 1
+	}
+	sim := similarity(term, word)
+	if strings.HasPrefix(word, term) {
+		return 0.5 + sim/2
+	}
+	if sim < MinSimilarity {
+		return 0
+	}
+	return sim
+}
+
+// bestMatch is the best score of any of words against term.
+func bestMatch(term string, words []string) float64 {
+	best := 0.0
+	for _, w := range words {
+		if s := match(term, w); s > best {
+			best = s
+		}
+	}
+	return best
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, "jose nunez", Normalize("José Ñúñez"))
+	assert.Equal(t, "file", Normalize("ﬁle"), "compatibility forms are folded")
+	assert.Equal(t, []string{"jose", "nunez"}, Terms("  José,Ñúñez! "))
+	assert.Equal(t, []string{"john", "smith", "example", "com"}, Terms("John.Smith@Example.com"))
+	assert.Empty(t, Terms(" -- "))
+}
+
+func TestTrigrams(t *testing.T) {
+	assert.Equal(t, map[string]struct{}{"  j": {}, " jo": {}, "jo ": {}}, trigrams("jo"))
+	assert.Len(t, trigrams("john"), 5)
+	assert.Len(t, trigrams("aaaa"), 4, "repeated windows count once")
+	assert.Contains(t, trigrams("josé"), "sé ", "windows are runes, not bytes")
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, similarity("smith", "smith"))
+	assert.Equal(t, 0.0, similarity("abc", "xyz"))
+	assert.Equal(t, similarity("smith", "smiht"), similarity("smiht", "smith"))
+	assert.InDelta(t, 3.0/9, similarity("smith", "smiht"), 1e-9)
+	assert.InDelta(t, 3.0/7, similarity("john", "johm"), 1e-9)
+}
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		term, word string
+		min, max   float64
+	}{
+		{"john", "john", 1, 1},
+		{"jo", "john", 0.5, 1}, // prefix
+		{"johm", "john", MinSimilarity, 0.5},
+		{"smiht", "smith", MinSimilarity, 0.5},
+		{"jhon", "john", 0, 0},             // too far apart
+		{"john", "jo", MinSimilarity, 0.5}, // only similar: a word is no prefix of the term
+		{"x", "smith", 0, 0},
+	} {
+		got := match(tc.term, tc.word)
+		assert.True(t, got >= tc.min && got <= tc.max, "match(%q, %q) = %v, want [%v, %v]", tc.term, tc.word, got, tc.min, tc.max)
+	}
+	// A prefix outranks a merely similar word.
+	assert.Greater(t, match("smi", "smithers"), match("smiht", "smith"))
+}
+
+func TestIndex_Search(t *testing.T) {
+	ix := NewIndex()
+	ix.Add(1, "John Smith", "john@example.com")
+	ix.Add(2, "Johanna Smithers", "jo@example.com")
+	ix.Add(3, "José Núñez", "jose@example.org")
+	ix.Add(4, "Mary Major", "mary@example.net")
+
+	ids := func(hits []Hit) []uint {
+		var out []uint
+		for _, h := range hits {
+			out = append(out, h.ID)
+		}
+		return out
+	}
+
+	hits := ix.Search("john smith")
+	require.NotEmpty(t, hits)
+	assert.Equal(t, uint(1), hits[0].ID)
+	assert.Equal(t, 1.0, hits[0].Score)
+	assert.Equal(t, []uint{1, 2}, ids(hits))
+	assert.Less(t, hits[1].Score, hits[0].Score)
+
+	// Typos, accents and case are forgiven.
+	hits = ix.Search("JOHM SMIHT")
+	require.NotEmpty(t, hits)
+	assert.Equal(t, uint(1), hits[0].ID)
+	assert.Equal(t, []uint{3}, ids(ix.Search("nunez")))
+	assert.Equal(t, []uint{3}, ids(ix.Search("Núñez")))
+
+	// Prefixes match while a query is typed.
+	assert.Equal(t, []uint{1, 2}, ids(ix.Search("smi")))
+
+	// Equal scores come out by ID.
+	hits = ix.Search("example")
+	assert.Equal(t, []uint{1, 2, 3, 4}, ids(hits))
+	assert.Equal(t, hits[0].Score, hits[3].Score)
+
+	// Documents matching only some terms score lower.
+	hits = ix.Search("mary smith")
+	assert.Equal(t, []uint{1, 4, 2}, ids(hits))
+	assert.InDelta(t, 0.5, hits[0].Score, 1e-9)
+	assert.InDelta(t, 0.5, hits[1].Score, 1e-9)
+
+	assert.Nil(t, ix.Search(""))
+	assert.Nil(t, ix.Search("  !? "))
+	assert.Empty(t, ix.Search("zzz"))
+}
+
+func TestIndex_AddReplacesAndRemove(t *testing.T) {
+	ix := NewIndex()
+	ix.Add(1, "John Smith")
+	ix.Add(1, "Jane Doe")
+	assert.Empty(t, ix.Search("john"))
+	assert.Len(t, ix.Search("jane"), 1)
+
+	ix.Add(2, "Jane Roe")
+	ix.Remove(1)
+	ix.Remove(99) // unknown IDs are ignored
+	hits := ix.Search("jane")
+	require.Len(t, hits, 1)
+	assert.Equal(t, uint(2), hits[0].ID)
+
+	// Removing the last document leaves no postings behind.
+	ix.Remove(2)
+	assert.Empty(t, ix.postings)
+	assert.Empty(t, ix.docs)
+
+	// A document without terms is not indexed.
+	ix.Add(3, " - ", "")
+	assert.Empty(t, ix.docs)
+}
+
+func TestHighlight(t *testing.T) {
+	for _, tc := range []struct {
+		text, query string
+		want        string
+		found       bool
+	}{
+		{"John Smith", "smith", "John <em>Smith</em>", true},
+		{"John Smith", "jo smiht", "<em>John</em> <em>Smith</em>", true},
+		{"José Núñez", "jose", "<em>José</em> Núñez", true},
+		{"john@example.com", "john", "<em>john</em>@example.com", true},
+		{"Mary Major", "smith", "Mary Major", false},
+		{"<b>Ann</b> & Co", "ann", "&lt;b&gt;<em>Ann</em>&lt;/b&gt; &amp; Co", true},
+		{"", "ann", "", false},
+	} {
+		got, found := Highlight(tc.text, Terms(tc.query))
+		assert.Equal(t, tc.want, got, "%q / %q", tc.text, tc.query)
+		assert.Equal(t, tc.found, found, "%q / %q", tc.text, tc.query)
+	}
+}
@@ -0,0 +1,110 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/pkg/totp"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 appendix B test vectors.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// rfcVectors are the appendix B SHA1 values, cut to six digits.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCode_RFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		code, err := totp.Code(rfcSecret, time.Unix(v.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, v.code, code, "T=%d", v.unix)
+	}
+}
+
+func TestValidate_RFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		at := time.Unix(v.unix, 0)
+		step, ok := totp.Validate(rfcSecret, v.code, at, 0)
+		assert.True(t, ok, "T=%d", v.unix)
+		assert.Equal(t, totp.Step(at), step)
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totp.Step(now)
+	codeAt := func(offset int64) string {
+		code, err := totp.Code(rfcSecret, now.Add(time.Duration(offset*totp.Period)*time.Second))
+		require.NoError(t, err)
+		return code
+	}
+
+	for _, tc := range []struct {
+		offset int64
+		skew   int
+		ok     bool
+	}{
+		{0, 0, true},
+		{-1, 0, false},
+		{1, 0, false},
+		{-1, 1, true},
+		{1, 1, true},
+		{-2, 1, false},
+		{2, 1, false},
+		{-2, 2, true},
+	} {
+		step, ok := totp.Validate(rfcSecret, codeAt(tc.offset), now, tc.skew)
+		assert.Equal(t, tc.ok, ok, "offset %d, skew %d", tc.offset, tc.skew)
+		if tc.ok {
+			// The step of the code is returned, not the current one.
+			assert.Equal(t, current+tc.offset, step, "offset %d, skew %d", tc.offset, tc.skew)
+		}
+	}
+}
+
+func TestValidate_RejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	for name, tc := range map[string]struct{ secret, code string }{
+		"short code":     {rfcSecret, "28708"},
+		"8-digit code":   {rfcSecret, "94287082"},
+		"wrong code":     {rfcSecret, "287083"},
+		"invalid secret": {"not base32!", "287082"},
+		"empty secret":   {"", "287082"},
+	} {
+		_, ok := totp.Validate(tc.secret, tc.code, now, 1)
+		assert.False(t, ok, name)
+	}
+
+	// Secrets are accepted in lower case and with padding, as apps show them.
+	_, ok := totp.Validate(strings.ToLower(rfcSecret)+"====", "287082", now, 0)
+	assert.True(t, ok)
+
+	_, err := totp.Code("not base32!", now)
+	assert.ErrorIs(t, err, totp.ErrInvalidSecret)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	b, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, a, 32, "160 bits in unpadded base32")
+	assert.NotEqual(t, a, b)
+	_, err = totp.Code(a, time.Now())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// PasswordPolicy describes what the "password" validation tag accepts.
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// DefaultPasswordPolicy is used until SetPasswordPolicy is called.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:    8,
+	MaxLength:    128,
+	RequireUpper: true,
+	RequireLower: true,
+	RequireDigit: true,
+}
+
+var passwordPolicy = DefaultPasswordPolicy
+
+// SetPasswordPolicy replaces the policy enforced by the "password" tag.
+func SetPasswordPolicy(p PasswordPolicy) {
+	passwordPolicy = p
+}
+
+func validatePassword(fl validator.FieldLevel) bool {
+	return passwordPolicy.allows(fl.Field().String())
+}
+
+func (p PasswordPolicy) allows(password string) bool {
+	n := len([]rune(password))
+	if n < p.MinLength || (p.MaxLength > 0 && n > p.MaxLength) {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	return (!p.RequireUpper || upper) &&
+		(!p.RequireLower || lower) &&
+		(!p.RequireDigit || digit) &&
+		(!p.RequireSymbol || symbol)
+}
+
+// describe renders the policy as a validation message.
+func (p PasswordPolicy) describe() string {
+	msg := fmt.Sprintf("must be at least %d characters", p.MinLength)
+	if p.MaxLength > 0 {
+		msg = fmt.Sprintf("must be %d to %d characters", p.MinLength, p.MaxLength)
+	}
+
+	var needs []string
+	if p.RequireUpper {
+		needs = append(needs, "an uppercase letter")
+	}
+	if p.RequireLower {
+		needs = append(needs, "a lowercase letter")
+	}
+	if p.RequireDigit {
+		needs = append(needs, "a digit")
+	}
+	if p.RequireSymbol {
+		needs = append(needs, "a symbol")
+	}
+
+	switch len(needs) {
+	case 0:
+		return msg
+	case 1:
+		return msg + " and contain " + needs[0]
+	default:
+		return msg + " and contain " + strings.Join(needs[:len(needs)-1], ", ") + " and " + needs[len(needs)-1]
+	}
+}
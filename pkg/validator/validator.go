@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -14,6 +15,9 @@ func Init() {
 	validate = validator.New()
 	// Register custom validation for gender
 	validate.RegisterValidation("gender", validateGender)
+	validate.RegisterValidation("password", validatePassword)
+	validate.RegisterValidation("role", validateRole)
+	validate.RegisterValidation("slug", validateSlug)
 }
 
 func Validate(s interface{}) error {
@@ -28,6 +32,21 @@ func validateGender(fl validator.FieldLevel) bool {
 	return gender == "male" || gender == "female"
 }
 
+func validateRole(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case "admin", "manager", "member":
+		return true
+	}
+	return false
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func validateSlug(fl validator.FieldLevel) bool {
+	s := fl.Field().String()
+	return len(s) >= 2 && len(s) <= 64 && slugPattern.MatchString(s)
+}
+
 // ErrorMap converts validation errors into a map
 func ErrorMap(err error) map[string]string {
 	if err == nil {
@@ -55,6 +74,12 @@ func ErrorMap(err error) map[string]string {
 			msg = fmt.Sprintf("must be at most %v characters", e.Param())
 		case "gender":
 			msg = "must be male or female"
+		case "slug":
+			msg = "must be 2-64 lowercase letters, digits or hyphens"
+		case "role":
+			msg = "must be admin, manager or member"
+		case "password":
+			msg = passwordPolicy.describe()
 		}
 
 		fieldErrors[field] = msg
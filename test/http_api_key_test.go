@@ -0,0 +1,162 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type apiKeyResponse struct {
+	ID         uint       `json:"id"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// createAPIKey issues a key for userID with the given scopes.
+func (a *testApp) createAPIKey(t *testing.T, token string, userID uint, scopes ...string) apiKeyResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"name": "batch job", "scopes": scopes})
+	w := a.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/api-keys", userID), string(body), token)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var key apiKeyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+	return key
+}
+
+func (a *testApp) withAPIKey(method, path, body, key string) int {
+	return a.doWithHeaders(method, path, body, "", map[string]string{"X-API-Key": key}).Code
+}
+
+func TestAPIKey_AuthenticatesWithinScopes(t *testing.T) {
+	app := newTestApp(t)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+	session := app.tokenFor(t, me.ID)
+
+	key := app.createAPIKey(t, session, me.ID, "users:read")
+	require.True(t, strings.HasPrefix(key.Key, domain.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(key.Key, key.Prefix))
+	assert.Equal(t, []string{"users:read"}, key.Scopes)
+
+	path := fmt.Sprintf("/api/v1/users/%d", me.ID)
+	assert.Equal(t, http.StatusOK, app.withAPIKey(http.MethodGet, path, "", key.Key))
+	assert.Equal(t, http.StatusOK, app.do(http.MethodGet, path, "", key.Key).Code, "bearer API key")
+
+	// Out of scope.
+	assert.Equal(t, http.StatusForbidden, app.withAPIKey(http.MethodPut, path, `{"name":"Bot"}`, key.Key))
+	assert.Equal(t, http.StatusForbidden, app.withAPIKey(http.MethodGet, "/api/v1/audit", "", key.Key))
+
+	// Scopes never widen the owner's role.
+	writer := app.createAPIKey(t, session, me.ID, "users:read", "users:write")
+	assert.Equal(t, http.StatusOK, app.withAPIKey(http.MethodPut, path, `{"name":"Bot"}`, writer.Key))
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+	assert.Equal(t, http.StatusForbidden, app.withAPIKey(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", other.ID), `{"name":"Bot"}`, writer.Key))
+
+	// Keys cannot manage credentials.
+	assert.Equal(t, http.StatusForbidden, app.withAPIKey(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/api-keys", me.ID), `{"name":"x","scopes":["users:read"]}`, writer.Key))
+	assert.Equal(t, http.StatusForbidden, app.withAPIKey(http.MethodPut, "/api/v1/users/me/password", `{"current_password":"x","new_password":"y"}`, writer.Key))
+
+	assert.Equal(t, http.StatusUnauthorized, app.withAPIKey(http.MethodGet, path, "", domain.APIKeyPrefix+"not-a-key"))
+}
+
+func TestAPIKey_ListShowsUsageButNotSecret(t *testing.T) {
+	app := newTestApp(t)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+	session := app.tokenFor(t, me.ID)
+	key := app.createAPIKey(t, session, me.ID, "users:read")
+	require.Equal(t, http.StatusOK, app.withAPIKey(http.MethodGet, "/api/v1/users/me", "", key.Key))
+
+	w := app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/api-keys", me.ID), "", session)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), key.Key)
+
+	var list struct {
+		Data []apiKeyResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, key.Prefix, list.Data[0].Prefix)
+	assert.NotNil(t, list.Data[0].LastUsedAt)
+
+	// Other members cannot see the keys; admins can.
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/api-keys", me.ID), "", app.tokenFor(t, other.ID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/api-keys", me.ID), "", app.tokenForRole(t, other.ID, domain.RoleAdmin))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAPIKey_Revoke(t *testing.T) {
+	app := newTestApp(t)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+	session := app.tokenFor(t, me.ID)
+	key := app.createAPIKey(t, session, me.ID, "users:read")
+
+	w := app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d/api-keys/%d", me.ID, key.ID), "", session)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, app.withAPIKey(http.MethodGet, "/api/v1/users/me", "", key.Key))
+
+	// A key of someone else is not found under this user.
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+	otherKey := app.createAPIKey(t, app.tokenFor(t, other.ID), other.ID, "users:read")
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d/api-keys/%d", me.ID, otherKey.ID), "", session)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAPIKey_AdminCannotCreateKeysForOthers(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+	adminSession := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	path := fmt.Sprintf("/api/v1/users/%d/api-keys", other.ID)
+
+	w := app.do(http.MethodPost, path, `{"name":"x","scopes":["users:read"]}`, adminSession)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Keys the user made themselves can still be listed and revoked.
+	key := app.createAPIKey(t, app.tokenFor(t, other.ID), other.ID, "users:read")
+	w = app.do(http.MethodGet, path, "", adminSession)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), key.Prefix)
+	w = app.do(http.MethodDelete, fmt.Sprintf("%s/%d", path, key.ID), "", adminSession)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestAPIKey_CreateValidation(t *testing.T) {
+	app := newTestApp(t)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+	session := app.tokenFor(t, me.ID)
+	path := fmt.Sprintf("/api/v1/users/%d/api-keys", me.ID)
+
+	w := app.do(http.MethodPost, path, `{"name":"x","scopes":["users:everything"]}`, session)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = app.do(http.MethodPost, path, `{"name":"x","scopes":[]}`, session)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = app.do(http.MethodPost, path, `{"name":"x","scopes":["users:read"],"expires_at":"2000-01-01T00:00:00Z"}`, session)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	tooLate := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	w = app.do(http.MethodPost, path, fmt.Sprintf(`{"name":"x","scopes":["users:read"],"expires_at":%q}`, tooLate), session)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAPIKey_StopsWorkingWhenOwnerIsDeleted(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+	key := app.createAPIKey(t, app.tokenFor(t, me.ID), me.ID, "users:read")
+
+	w := app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", me.ID), "", app.tokenForRole(t, admin.ID, domain.RoleAdmin))
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, app.withAPIKey(http.MethodGet, "/api/v1/users/me", "", key.Key))
+}
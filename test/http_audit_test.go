@@ -0,0 +1,95 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type auditList struct {
+	Data []struct {
+		ActorID   uint   `json:"actor_id"`
+		TargetID  uint   `json:"target_id"`
+		Action    string `json:"action"`
+		RequestID string `json:"request_id"`
+		ClientIP  string `json:"client_ip"`
+		Changes   map[string]struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"changes"`
+	} `json:"data"`
+	Meta struct {
+		Total int64 `json:"total"`
+	} `json:"meta"`
+}
+
+func TestUserMutationsAreAudited(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	id := app.signUp(t, "Jane", "jane@example.com", "Sup3rSecret")
+
+	w := app.doWithHeaders(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", id), `{"name":"Janet"}`, token, map[string]string{"X-Request-ID": "req-42"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-42", w.Header().Get("X-Request-ID"))
+
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", id), "", token)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/audit", id), "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp auditList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 3)
+
+	// Newest first: delete, update, create.
+	assert.Equal(t, "user.deleted", resp.Data[0].Action)
+	assert.Equal(t, "user.created", resp.Data[2].Action)
+	assert.Zero(t, resp.Data[2].ActorID, "self sign-up has no actor")
+
+	update := resp.Data[1]
+	assert.Equal(t, "user.updated", update.Action)
+	assert.Equal(t, admin.ID, update.ActorID)
+	assert.Equal(t, id, update.TargetID)
+	assert.Equal(t, "req-42", update.RequestID)
+	assert.NotEmpty(t, update.ClientIP)
+	assert.Equal(t, "Jane", update.Changes["name"].From)
+	assert.Equal(t, "Janet", update.Changes["name"].To)
+	assert.NotContains(t, update.Changes, "email")
+}
+
+func TestGlobalAuditFilters(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	a := app.signUp(t, "Anna", "anna@example.com", "Sup3rSecret")
+	app.signUp(t, "Bert", "bert@example.com", "Sup3rSecret")
+
+	w := app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", a), `{"gender":"male"}`, token)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = app.do(http.MethodGet, "/api/v1/audit?action=user.created", "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp auditList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.EqualValues(t, 2, resp.Meta.Total)
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/audit?actor_id=%d", admin.ID), "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = auditList{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "user.updated", resp.Data[0].Action)
+
+	w = app.do(http.MethodGet, "/api/v1/audit?from=yesterday", "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = app.do(http.MethodGet, "/api/v1/audit", "", app.tokenFor(t, a))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
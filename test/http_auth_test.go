@@ -0,0 +1,83 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	app := newTestApp(t)
+
+	w := app.do(http.MethodGet, "/api/v1/users", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), string(domain.CodeUnauthorized))
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestValidTokenExposesCurrentUser(t *testing.T) {
+	app := newTestApp(t)
+	u, err := app.users.Create(context.Background(), &domain.User{Name: "Jane", Email: "jane@example.com", Gender: "female"})
+	require.NoError(t, err)
+
+	w := app.do(http.MethodGet, "/api/v1/users/me", "", app.tokenFor(t, u.ID))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "jane@example.com", resp["email"])
+}
+
+func TestRejectsTamperedAndExpiredTokens(t *testing.T) {
+	app := newTestApp(t)
+	// Swap the payload of one token into another: the signature no longer matches.
+	first := strings.Split(app.tokenFor(t, 1), ".")
+	second := strings.Split(app.tokenFor(t, 2), ".")
+	tampered := strings.Join([]string{first[0], second[1], first[2]}, ".")
+
+	w := app.do(http.MethodGet, "/api/v1/users/me", "", tampered)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "1",
+		Issuer:    "userhub-test",
+		IssuedAt:  jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+
+	w = app.do(http.MethodGet, "/api/v1/users/me", "", expired)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "expired")
+}
+
+func TestLoginIssuesUsableToken(t *testing.T) {
+	app := newTestApp(t)
+	w := app.do(http.MethodPost, "/api/v1/users", `{"name":"Jane","email":"jane@example.com","gender":"female","password":"Sup3rSecret"}`, "")
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/login", `{"email":"jane@example.com","password":"nope"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/login", `{"email":"jane@example.com","password":"Sup3rSecret"}`, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+	assert.True(t, strings.EqualFold(tok.TokenType, "bearer"))
+
+	w = app.do(http.MethodGet, "/api/v1/users/me", "", tok.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
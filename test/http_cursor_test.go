@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type cursorPage struct {
+	Data []struct {
+		ID    uint   `json:"id"`
+		Email string `json:"email"`
+	} `json:"data"`
+	Meta struct {
+		Page  int    `json:"page"`
+		Limit int    `json:"limit"`
+		Total *int64 `json:"total"`
+		Next  string `json:"next"`
+		Prev  string `json:"prev"`
+	} `json:"meta"`
+}
+
+func (p cursorPage) ids() []uint {
+	ids := make([]uint, 0, len(p.Data))
+	for _, u := range p.Data {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func (a *testApp) listByCursor(t *testing.T, token, query string) cursorPage {
+	t.Helper()
+	w := a.do(http.MethodGet, "/api/v1/users?"+query, "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var page cursorPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	return page
+}
+
+func TestCursorPagination_WalksBothWays(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	ids := []uint{admin.ID}
+	for i := 0; i < 6; i++ {
+		ids = append(ids, seedUser(t, app, fmt.Sprintf("user%d@example.com", i), domain.RoleMember).ID)
+	}
+
+	first := app.listByCursor(t, token, "cursor=&limit=3")
+	assert.Equal(t, ids[0:3], first.ids())
+	assert.Equal(t, 3, first.Meta.Limit)
+	assert.Nil(t, first.Meta.Total)
+	assert.Empty(t, first.Meta.Prev)
+	require.NotEmpty(t, first.Meta.Next)
+
+	// Users created mid-scan show up at the end instead of shifting pages.
+	late := seedUser(t, app, "late@example.com", domain.RoleMember)
+	ids = append(ids, late.ID)
+
+	second := app.listByCursor(t, token, "limit=3&cursor="+url.QueryEscape(first.Meta.Next))
+	assert.Equal(t, ids[3:6], second.ids())
+	require.NotEmpty(t, second.Meta.Next)
+
+	last := app.listByCursor(t, token, "limit=3&cursor="+url.QueryEscape(second.Meta.Next))
+	assert.Equal(t, ids[6:8], last.ids())
+	assert.Empty(t, last.Meta.Next)
+	require.NotEmpty(t, last.Meta.Prev)
+
+	back := app.listByCursor(t, token, "limit=3&cursor="+url.QueryEscape(last.Meta.Prev))
+	assert.Equal(t, ids[3:6], back.ids())
+	back = app.listByCursor(t, token, "limit=3&cursor="+url.QueryEscape(back.Meta.Prev))
+	assert.Equal(t, ids[0:3], back.ids())
+	assert.Empty(t, back.Meta.Prev)
+	assert.NotEmpty(t, back.Meta.Next)
+}
+
+func TestCursorPagination_RejectsForeignCursors(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	for i := 0; i < 3; i++ {
+		seedUser(t, app, fmt.Sprintf("user%d@example.com", i), domain.RoleMember)
+	}
+
+	page := app.listByCursor(t, token, "cursor=&limit=2&q=user")
+	require.NotEmpty(t, page.Meta.Next)
+	next := url.QueryEscape(page.Meta.Next)
+
+	w := app.do(http.MethodGet, "/api/v1/users?limit=2&q=user&cursor="+next, "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = app.do(http.MethodGet, "/api/v1/users?limit=2&q=admin&cursor="+next, "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "cursors are bound to their query")
+	assert.Contains(t, w.Body.String(), domain.CodeValidation)
+
+	w = app.do(http.MethodGet, "/api/v1/users?limit=2&q=user&cursor="+next+"x", "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "cursors are signed")
+}
+
+func TestCursorPagination_PageModeStillWorks(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	seedUser(t, app, "user@example.com", domain.RoleMember)
+
+	page := app.listByCursor(t, token, "page=2&limit=1")
+	assert.Equal(t, 2, page.Meta.Page)
+	require.NotNil(t, page.Meta.Total)
+	assert.EqualValues(t, 2, *page.Meta.Total)
+	assert.Empty(t, page.Meta.Next)
+	assert.Len(t, page.Data, 1)
+}
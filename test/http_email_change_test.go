@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func TestEmailChangeIsConfirmedByToken(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	token := app.tokenFor(t, id)
+
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", id), `{"email":"ada@new.example"}`, token)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	// Nothing changes until the new address confirms.
+	w = app.do(http.MethodGet, "/api/v1/users/me", "", token)
+	assert.Contains(t, w.Body.String(), "ada@example.com")
+
+	confirm := app.tokenMailedTo(t, "ada@new.example")
+	w = app.do(http.MethodPost, "/api/v1/users/email/confirm", fmt.Sprintf(`{"token":%q}`, confirm), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"email":"ada@new.example"`)
+
+	notice, ok := app.outbox.Last("ada@example.com")
+	require.True(t, ok, "old address is notified")
+	assert.Contains(t, notice.Body, "ada@new.example")
+
+	// Tokens are single-use.
+	w = app.do(http.MethodPost, "/api/v1/users/email/confirm", fmt.Sprintf(`{"token":%q}`, confirm), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	app.login(t, "ada@new.example", "Sup3rSecret")
+}
+
+func TestEmailChangeEnforcesUniquenessAtConfirm(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", id), `{"email":"taken@example.com"}`, app.tokenFor(t, id))
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	confirm := app.tokenMailedTo(t, "taken@example.com")
+
+	// The address is claimed before the link is clicked.
+	app.signUp(t, "Bob", "taken@example.com", "Sup3rSecret")
+
+	w = app.do(http.MethodPost, "/api/v1/users/email/confirm", fmt.Sprintf(`{"token":%q}`, confirm), "")
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	// Requesting an address that is already in use fails straight away.
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", id), `{"email":"taken@example.com"}`, app.tokenFor(t, id))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestNewEmailChangeSupersedesPendingOne(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	token := app.tokenFor(t, id)
+
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", id), `{"email":"first@example.com"}`, token)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	first := app.tokenMailedTo(t, "first@example.com")
+
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", id), `{"email":"second@example.com"}`, token)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/users/email/confirm", fmt.Sprintf(`{"token":%q}`, first), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEmailChangeOfOtherUserIsForbidden(t *testing.T) {
+	app := newTestApp(t)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", other.ID), `{"email":"mine@example.com"}`, app.tokenFor(t, me.ID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, app.outbox.Messages())
+}
+
+func TestManagerCannotChangeAnAdminsEmail(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	manager := seedUser(t, app, "manager@example.com", domain.RoleManager)
+
+	// The link would go to a mailbox the manager controls, and with it the
+	// password reset of the admin account.
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", admin.ID), `{"email":"takeover@example.com"}`, app.tokenForRole(t, manager.ID, domain.RoleManager))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, app.outbox.Messages())
+
+	stored, err := app.users.GetByID(context.Background(), admin.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "admin@example.com", stored.Email)
+}
@@ -0,0 +1,72 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func TestGetUserReturnsStrongETag(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+
+	w := app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", id), "", app.tokenFor(t, id))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, `"1"`, w.Header().Get("ETag"))
+}
+
+func TestUpdateWithMatchingETagBumpsVersion(t *testing.T) {
+	app := newTestApp(t, withRequireIfMatch())
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	token := app.tokenFor(t, id)
+
+	w := app.doWithHeaders(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", id), `{"name":"Ada L"}`, token,
+		map[string]string{"If-Match": `"1"`})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, `"2"`, w.Header().Get("ETag"))
+}
+
+func TestConcurrentUpdateWithStaleETagFails(t *testing.T) {
+	app := newTestApp(t, withRequireIfMatch())
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	token := app.tokenFor(t, id)
+	stale := map[string]string{"If-Match": `"1"`}
+
+	w := app.doWithHeaders(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", id), `{"name":"First"}`, token, stale)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = app.doWithHeaders(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", id), `{"name":"Second"}`, token, stale)
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), string(domain.CodePreconditionFailed))
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", id), "", token)
+	require.Contains(t, w.Body.String(), `"First"`)
+}
+
+func TestMissingIfMatchIsRejectedWhenRequired(t *testing.T) {
+	app := newTestApp(t, withRequireIfMatch())
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	admin := app.tokenForRole(t, 999, domain.RoleAdmin)
+
+	w := app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", id), `{"name":"Ada L"}`, app.tokenFor(t, id))
+	require.Equal(t, http.StatusPreconditionRequired, w.Code, w.Body.String())
+
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", id), "", admin)
+	require.Equal(t, http.StatusPreconditionRequired, w.Code, w.Body.String())
+}
+
+func TestDeleteHonoursIfMatch(t *testing.T) {
+	app := newTestApp(t, withRequireIfMatch())
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	admin := app.tokenForRole(t, 999, domain.RoleAdmin)
+
+	w := app.doWithHeaders(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", id), "", admin, map[string]string{"If-Match": `"7"`})
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body.String())
+
+	w = app.doWithHeaders(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", id), "", admin, map[string]string{"If-Match": `"1"`})
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+}
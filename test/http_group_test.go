@@ -0,0 +1,199 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type groupResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Role        string `json:"role"`
+	MemberIDs   []uint `json:"member_ids"`
+	SubgroupIDs []uint `json:"subgroup_ids"`
+	Direct      bool   `json:"direct"`
+}
+
+func decodeGroup(t *testing.T, w *httptest.ResponseRecorder) groupResponse {
+	t.Helper()
+	var g groupResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &g), w.Body.String())
+	return g
+}
+
+// createGroup creates a group through the API and returns it.
+func (a *testApp) createGroup(t *testing.T, token, body string) groupResponse {
+	t.Helper()
+	w := a.do(http.MethodPost, "/api/v1/groups", body, token)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	return decodeGroup(t, w)
+}
+
+func TestGroups_CRUD(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	alice := seedUser(t, app, "alice@example.com", domain.RoleMember)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	g := app.createGroup(t, token, fmt.Sprintf(`{"name":" Engineering ","member_ids":[%d,%d]}`, alice.ID, alice.ID))
+	assert.Equal(t, "Engineering", g.Name)
+	assert.Equal(t, []uint{alice.ID}, g.MemberIDs)
+	assert.Empty(t, g.SubgroupIDs)
+
+	w := app.do(http.MethodPost, "/api/v1/groups", `{"name":"engineering"}`, token)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	w = app.do(http.MethodPost, "/api/v1/groups", `{"name":"Ops","member_ids":[999]}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "user 999 does not exist")
+	w = app.do(http.MethodPost, "/api/v1/groups", `{"name":"Ops","role":"owner"}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	path := fmt.Sprintf("/api/v1/groups/%d", g.ID)
+	w = app.do(http.MethodPut, path, `{"name":"Platform","role":"manager"}`, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	g = decodeGroup(t, w)
+	assert.Equal(t, "Platform", g.Name)
+	assert.Equal(t, "manager", g.Role)
+	assert.Equal(t, []uint{alice.ID}, g.MemberIDs, "members are kept")
+
+	w = app.do(http.MethodGet, "/api/v1/groups?page=1&limit=10", "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Data []groupResponse `json:"data"`
+		Meta struct {
+			Total int64 `json:"total"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, int64(1), list.Meta.Total)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "Platform", list.Data[0].Name)
+
+	// Members cannot manage groups or read them, unless a group makes them
+	// managers, as Platform does for Alice.
+	assert.Equal(t, http.StatusOK, app.do(http.MethodGet, path, "", app.tokenFor(t, alice.ID)).Code)
+	member := app.tokenFor(t, seedUser(t, app, "bob@example.com", domain.RoleMember).ID)
+	assert.Equal(t, http.StatusForbidden, app.do(http.MethodGet, path, "", member).Code)
+	assert.Equal(t, http.StatusForbidden, app.do(http.MethodDelete, path, "", member).Code)
+
+	assert.Equal(t, http.StatusNoContent, app.do(http.MethodDelete, path, "", token).Code)
+	assert.Equal(t, http.StatusNotFound, app.do(http.MethodGet, path, "", token).Code)
+	assert.Equal(t, http.StatusBadRequest, app.do(http.MethodGet, "/api/v1/groups/abc", "", token).Code)
+}
+
+func TestGroups_BulkMembersAndNesting(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	alice := seedUser(t, app, "alice@example.com", domain.RoleMember)
+	bob := seedUser(t, app, "bob@example.com", domain.RoleMember)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	eng := app.createGroup(t, token, `{"name":"Engineering"}`)
+	backend := app.createGroup(t, token, `{"name":"Backend"}`)
+	db := app.createGroup(t, token, `{"name":"Databases"}`)
+	members := fmt.Sprintf("/api/v1/groups/%d/members", eng.ID)
+
+	w := app.do(http.MethodPost, members, fmt.Sprintf(`{"user_ids":[%d,%d],"subgroup_ids":[%d]}`, alice.ID, bob.ID, backend.ID), token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	g := decodeGroup(t, w)
+	assert.Equal(t, []uint{alice.ID, bob.ID}, g.MemberIDs)
+	assert.Equal(t, []uint{backend.ID}, g.SubgroupIDs)
+
+	// Adding again changes nothing.
+	w = app.do(http.MethodPost, members, fmt.Sprintf(`{"user_ids":[%d]}`, alice.ID), token)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []uint{alice.ID, bob.ID}, decodeGroup(t, w).MemberIDs)
+
+	w = app.do(http.MethodDelete, members, fmt.Sprintf(`{"user_ids":[%d,999]}`, alice.ID), token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []uint{bob.ID}, decodeGroup(t, w).MemberIDs)
+
+	w = app.do(http.MethodPost, members, `{}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = app.do(http.MethodPost, members, `{"subgroup_ids":[999]}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "group 999 does not exist")
+
+	// Engineering > Backend > Databases: nothing above Databases can go under it.
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/groups/%d/members", backend.ID), fmt.Sprintf(`{"subgroup_ids":[%d]}`, db.ID), token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	for _, id := range []uint{db.ID, backend.ID, eng.ID} {
+		w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/groups/%d/members", db.ID), fmt.Sprintf(`{"subgroup_ids":[%d]}`, id), token)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "would contain itself")
+	}
+
+	// Deleting a subgroup takes it out of its parents.
+	require.Equal(t, http.StatusNoContent, app.do(http.MethodDelete, fmt.Sprintf("/api/v1/groups/%d", backend.ID), "", token).Code)
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/groups/%d", eng.ID), "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, decodeGroup(t, w).SubgroupIDs)
+}
+
+func TestGroups_UserGroupsAndRoleInheritance(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	alice := seedUser(t, app, "alice@example.com", domain.RoleMember)
+	bob := seedUser(t, app, "bob@example.com", domain.RoleMember)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	aliceToken := app.tokenFor(t, alice.ID)
+	bobPath := fmt.Sprintf("/api/v1/users/%d", bob.ID)
+
+	require.Equal(t, http.StatusForbidden, app.do(http.MethodGet, bobPath, "", aliceToken).Code)
+
+	leads := app.createGroup(t, token, `{"name":"Leads","role":"manager"}`)
+	team := app.createGroup(t, token, fmt.Sprintf(`{"name":"Team","member_ids":[%d]}`, alice.ID))
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/groups/%d/members", leads.ID), fmt.Sprintf(`{"subgroup_ids":[%d]}`, team.ID), token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Alice is a manager through Team, with the token she already had.
+	assert.Equal(t, http.StatusOK, app.do(http.MethodGet, bobPath, "", aliceToken).Code)
+	assert.Equal(t, http.StatusForbidden, app.do(http.MethodDelete, bobPath, "", aliceToken).Code)
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/groups", alice.ID), "", aliceToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data          []groupResponse `json:"data"`
+		EffectiveRole string          `json:"effective_role"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "manager", resp.EffectiveRole)
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "Leads", resp.Data[0].Name)
+	assert.False(t, resp.Data[0].Direct)
+	assert.Equal(t, "Team", resp.Data[1].Name)
+	assert.True(t, resp.Data[1].Direct)
+
+	// Bob is in no group and cannot look at Alice's.
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/groups", bob.ID), "", app.tokenFor(t, bob.ID))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"data":[]`)
+	assert.Contains(t, w.Body.String(), `"effective_role":"member"`)
+	assert.Equal(t, http.StatusForbidden, app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/groups", alice.ID), "", app.tokenFor(t, bob.ID)).Code)
+
+	// Leaving the team takes the role away.
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/groups/%d/members", team.ID), fmt.Sprintf(`{"user_ids":[%d]}`, alice.ID), token)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusForbidden, app.do(http.MethodGet, bobPath, "", aliceToken).Code)
+}
+
+func TestGroups_APIKeyScopes(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	session := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	readOnly := app.createAPIKey(t, session, admin.ID, "groups:read")
+	assert.Equal(t, http.StatusOK, app.withAPIKey(http.MethodGet, "/api/v1/groups", "", readOnly.Key))
+	assert.Equal(t, http.StatusForbidden, app.withAPIKey(http.MethodPost, "/api/v1/groups", `{"name":"Bots"}`, readOnly.Key))
+
+	writer := app.createAPIKey(t, session, admin.ID, "groups:write")
+	assert.Equal(t, http.StatusCreated, app.withAPIKey(http.MethodPost, "/api/v1/groups", `{"name":"Bots"}`, writer.Key))
+}
@@ -0,0 +1,149 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type impersonationResponse struct {
+	ID             uint   `json:"id"`
+	UserID         uint   `json:"user_id"`
+	ImpersonatorID uint   `json:"impersonator_id"`
+	Reason         string `json:"reason"`
+	EndedAt        string `json:"ended_at"`
+	Token          struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	} `json:"token"`
+}
+
+func (a *testApp) impersonate(t *testing.T, token string, userID uint) impersonationResponse {
+	t.Helper()
+	w := a.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/impersonate", userID), `{"reason":"ticket 42"}`, token)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var imp impersonationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &imp))
+	return imp
+}
+
+func TestImpersonation_ActsAsUserUntilEnded(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	alice := seedUser(t, app, "alice@example.com", domain.RoleMember)
+	bob := seedUser(t, app, "bob@example.com", domain.RoleMember)
+	adminToken := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	imp := app.impersonate(t, adminToken, alice.ID)
+	assert.Equal(t, alice.ID, imp.UserID)
+	assert.Equal(t, admin.ID, imp.ImpersonatorID)
+	assert.Equal(t, "ticket 42", imp.Reason)
+	assert.Empty(t, imp.Token.RefreshToken, "impersonation tokens cannot be refreshed")
+	token := imp.Token.AccessToken
+
+	// The API looks exactly as it does to alice.
+	w := app.do(http.MethodGet, "/api/v1/users/me", "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "alice@example.com")
+	assert.Equal(t, strconv.FormatUint(uint64(admin.ID), 10), w.Header().Get("X-Impersonator-ID"))
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", bob.ID), "", token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", alice.ID), `{"name":"Alice B"}`, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, admin.ID, decodeUserMetadata(t, w.Body.Bytes()).UpdatedBy, "changes are stamped with the impersonator")
+
+	w = app.do(http.MethodPost, "/api/v1/impersonation/end", "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var ended impersonationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ended))
+	assert.NotEmpty(t, ended.EndedAt)
+
+	w = app.do(http.MethodGet, "/api/v1/users/me", "", token)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "ending the impersonation revokes its token")
+
+	// Every record made while impersonating names both identities.
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/audit?impersonator_id=%d", admin.ID), "", adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var audit struct {
+		Data []struct {
+			ActorID        uint   `json:"actor_id"`
+			ImpersonatorID uint   `json:"impersonator_id"`
+			Action         string `json:"action"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &audit))
+	require.Len(t, audit.Data, 2)
+	for _, e := range audit.Data {
+		assert.Equal(t, alice.ID, e.ActorID)
+		assert.Equal(t, admin.ID, e.ImpersonatorID)
+	}
+	assert.ElementsMatch(t, []string{"user.updated", "impersonation.ended"}, []string{audit.Data[0].Action, audit.Data[1].Action})
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/audit?action=impersonation.started&actor_id=%d", admin.ID), "", adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var started auditList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	require.Len(t, started.Data, 1)
+	assert.Equal(t, alice.ID, started.Data[0].TargetID)
+	assert.Equal(t, "ticket 42", started.Data[0].Changes["reason"].To)
+}
+
+func TestImpersonation_BlocksSensitiveOperations(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	other := seedUser(t, app, "other@example.com", domain.RoleAdmin)
+	target := seedUser(t, app, "target@example.com", domain.RoleAdmin)
+	token := app.impersonate(t, app.tokenForRole(t, admin.ID, domain.RoleAdmin), target.ID).Token.AccessToken
+
+	for _, tc := range []struct {
+		method, path, body string
+	}{
+		{http.MethodPut, "/api/v1/users/me/password", `{"current_password":"whatever","new_password":"N3wSecret!"}`},
+		{http.MethodPost, "/api/v1/auth/mfa/totp", ""},
+		{http.MethodPost, "/api/v1/auth/mfa/disable", `{"code":"123456"}`},
+		{http.MethodPut, "/api/v1/auth/mfa/policy", `{"roles":["admin"]}`},
+		{http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", target.ID), ""},
+		{http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", target.ID), `{"email":"new@example.com"}`},
+		{http.MethodPost, fmt.Sprintf("/api/v1/users/%d/api-keys", target.ID), `{"name":"ci","scopes":["users:read"]}`},
+		{http.MethodPost, fmt.Sprintf("/api/v1/users/%d/impersonate", other.ID), `{"reason":"nested"}`},
+	} {
+		w := app.do(tc.method, tc.path, tc.body, token)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s: %s", tc.method, tc.path, w.Body.String())
+	}
+
+	// Everything else the target may do still works.
+	w := app.do(http.MethodGet, "/api/v1/users", "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestImpersonation_RequiresAdmin(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	manager := seedUser(t, app, "manager@example.com", domain.RoleManager)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	adminToken := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/impersonate", member.ID), `{"reason":"help"}`, app.tokenForRole(t, manager.ID, domain.RoleManager))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/impersonate", admin.ID), `{"reason":"help"}`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "admins cannot impersonate themselves")
+
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/impersonate", member.ID), `{}`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a reason is required")
+
+	w = app.do(http.MethodPost, "/api/v1/users/999/impersonate", `{"reason":"help"}`, adminToken)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/impersonation/end", "", adminToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "not impersonating anyone")
+}
@@ -0,0 +1,137 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type invitationResponse struct {
+	ID        uint   `json:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Status    string `json:"status"`
+	InviterID uint   `json:"inviter_id"`
+	UserID    uint   `json:"user_id"`
+}
+
+func (a *testApp) invite(t *testing.T, token, email, role string) invitationResponse {
+	t.Helper()
+	w := a.do(http.MethodPost, "/api/v1/invitations", fmt.Sprintf(`{"email":%q,"role":%q}`, email, role), token)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var inv invitationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &inv))
+	return inv
+}
+
+func acceptBody(token string) string {
+	return fmt.Sprintf(`{"token":%q,"name":"Grace","gender":"female","password":"Sup3rSecret"}`, token)
+}
+
+func TestInvitation_AcceptCreatesVerifiedUser(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	inv := app.invite(t, token, "grace@example.com", "manager")
+	assert.Equal(t, "pending", inv.Status)
+	assert.Equal(t, "manager", inv.Role)
+	assert.Equal(t, admin.ID, inv.InviterID)
+	invite := app.tokenMailedTo(t, "grace@example.com")
+
+	// The rest of the profile is checked before the invitation is used.
+	w := app.do(http.MethodPost, "/api/v1/invitations/accept", fmt.Sprintf(`{"token":%q,"name":"Grace","gender":"female","password":"weak"}`, invite), "")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/invitations/accept", acceptBody(invite), "")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var user struct {
+		ID            uint   `json:"id"`
+		Email         string `json:"email"`
+		Role          string `json:"role"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	assert.Equal(t, "grace@example.com", user.Email)
+	assert.Equal(t, "manager", user.Role)
+	assert.True(t, user.EmailVerified)
+	app.login(t, "grace@example.com", "Sup3rSecret")
+
+	w = app.do(http.MethodPost, "/api/v1/invitations/accept", acceptBody(invite), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, "invitations are single-use")
+
+	w = app.do(http.MethodGet, "/api/v1/invitations", "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Data []invitationResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "accepted", list.Data[0].Status)
+	assert.Equal(t, user.ID, list.Data[0].UserID)
+
+	// The user was created by the inviter, and the invitation accepted by the user.
+	events := app.do(http.MethodGet, fmt.Sprintf("/api/v1/audit?target_id=%d", user.ID), "", token).Body.String()
+	assert.Contains(t, events, `"invitation.accepted"`)
+	assert.Contains(t, events, fmt.Sprintf(`"actor_id":%d,"target_id":%d,"action":"user.created"`, admin.ID, user.ID))
+}
+
+func TestInvitation_RevokeAndResend(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	inv := app.invite(t, token, "grace@example.com", "")
+	assert.Equal(t, "member", inv.Role)
+	first := app.tokenMailedTo(t, "grace@example.com")
+
+	w := app.do(http.MethodPost, "/api/v1/invitations", `{"email":"grace@example.com"}`, token)
+	assert.Equal(t, http.StatusConflict, w.Code, "one pending invitation per address")
+	w = app.do(http.MethodPost, "/api/v1/invitations", `{"email":"admin@example.com"}`, token)
+	assert.Equal(t, http.StatusConflict, w.Code, "already a user")
+
+	// Resending replaces the link.
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/invitations/%d/resend", inv.ID), "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	second := app.tokenMailedTo(t, "grace@example.com")
+	require.NotEqual(t, first, second)
+	assert.Equal(t, http.StatusBadRequest, app.do(http.MethodPost, "/api/v1/invitations/accept", acceptBody(first), "").Code)
+
+	// A tampered token fails its signature check.
+	payload, _, _ := strings.Cut(second, ".")
+	assert.Equal(t, http.StatusBadRequest, app.do(http.MethodPost, "/api/v1/invitations/accept", acceptBody(payload+".AAAA"), "").Code)
+
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/invitations/%d/revoke", inv.ID), "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"revoked"`)
+	assert.Equal(t, http.StatusBadRequest, app.do(http.MethodPost, "/api/v1/invitations/accept", acceptBody(second), "").Code)
+
+	assert.Equal(t, http.StatusConflict, app.do(http.MethodPost, fmt.Sprintf("/api/v1/invitations/%d/revoke", inv.ID), "", token).Code)
+	assert.Equal(t, http.StatusConflict, app.do(http.MethodPost, fmt.Sprintf("/api/v1/invitations/%d/resend", inv.ID), "", token).Code)
+	assert.Equal(t, http.StatusNotFound, app.do(http.MethodPost, "/api/v1/invitations/999/revoke", "", token).Code)
+
+	// With the old one revoked, the address can be invited again.
+	app.invite(t, token, "grace@example.com", "member")
+}
+
+func TestInvitation_RequiresAdmin(t *testing.T) {
+	app := newTestApp(t)
+	manager := seedUser(t, app, "manager@example.com", domain.RoleManager)
+	token := app.tokenForRole(t, manager.ID, domain.RoleManager)
+
+	w := app.do(http.MethodPost, "/api/v1/invitations", `{"email":"grace@example.com"}`, token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, http.StatusForbidden, app.do(http.MethodGet, "/api/v1/invitations", "", token).Code)
+
+	w = app.do(http.MethodPost, "/api/v1/invitations", `{"email":"not-an-email"}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, app.do(http.MethodPost, "/api/v1/invitations", `{"email":"grace@example.com"}`, "").Code)
+}
@@ -0,0 +1,224 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+	"userHub/pkg/totp"
+)
+
+type mfaChallenge struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+// enrollMFA turns on TOTP for the user behind token and returns the secret
+// and the recovery codes.
+func (a *testApp) enrollMFA(t *testing.T, token string) (string, []string) {
+	t.Helper()
+	w := a.do(http.MethodPost, "/api/v1/auth/mfa/totp", "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var enrollment struct {
+		Secret string `json:"secret"`
+		URI    string `json:"otpauth_uri"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &enrollment))
+	require.Contains(t, enrollment.URI, "otpauth://totp/")
+
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	w = a.do(http.MethodPost, "/api/v1/auth/mfa/totp/confirm", fmt.Sprintf(`{"code":%q}`, code), token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var recovery struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &recovery))
+	return enrollment.Secret, recovery.RecoveryCodes
+}
+
+// challenge logs in with a password and expects to be asked for a second factor.
+func (a *testApp) challenge(t *testing.T, email, pass string) string {
+	t.Helper()
+	w := a.do(http.MethodPost, "/api/v1/auth/login", fmt.Sprintf(`{"email":%q,"password":%q}`, email, pass), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var c mfaChallenge
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &c))
+	require.True(t, c.MFARequired)
+	require.NotEmpty(t, c.MFAToken)
+	return c.MFAToken
+}
+
+func (a *testApp) verifyMFA(challenge, code string) *httptest.ResponseRecorder {
+	return a.do(http.MethodPost, "/api/v1/auth/mfa/verify", fmt.Sprintf(`{"mfa_token":%q,"code":%q}`, challenge, code), "")
+}
+
+func TestMFA_LoginRequiresSecondFactor(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Ada", "ada@example.com", "Sup3r-secret")
+	session := app.login(t, "ada@example.com", "Sup3r-secret")
+	secret, recovery := app.enrollMFA(t, session.AccessToken)
+	require.Len(t, recovery, 10)
+
+	challenge := app.challenge(t, "ada@example.com", "Sup3r-secret")
+
+	// The challenge is not an access token.
+	w := app.do(http.MethodGet, "/api/v1/users/me", "", challenge)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = app.verifyMFA(challenge, "000000")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// The code used to confirm the enrollment is spent; take the next one.
+	code, err := totp.Code(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+	w = app.verifyMFA(challenge, code)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tok tokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+	p, err := app.tokens.Verify(tok.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, p.MFA)
+
+	// The same code cannot be replayed.
+	w = app.verifyMFA(app.challenge(t, "ada@example.com", "Sup3r-secret"), code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Refreshed sessions keep their second factor.
+	w = app.do(http.MethodPost, "/api/v1/auth/refresh", fmt.Sprintf(`{"refresh_token":%q}`, tok.RefreshToken), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+	p, err = app.tokens.Verify(tok.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, p.MFA)
+}
+
+func TestMFA_RecoveryCodesAreSingleUse(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Ada", "ada@example.com", "Sup3r-secret")
+	_, recovery := app.enrollMFA(t, app.login(t, "ada@example.com", "Sup3r-secret").AccessToken)
+
+	w := app.verifyMFA(app.challenge(t, "ada@example.com", "Sup3r-secret"), recovery[0])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = app.verifyMFA(app.challenge(t, "ada@example.com", "Sup3r-secret"), recovery[0])
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Regenerating requires a valid code and invalidates the old set.
+	var tok tokenPair
+	w = app.verifyMFA(app.challenge(t, "ada@example.com", "Sup3r-secret"), recovery[1])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+
+	w = app.do(http.MethodPost, "/api/v1/auth/mfa/recovery-codes", `{"code":"nope-nope"}`, tok.AccessToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = app.do(http.MethodPost, "/api/v1/auth/mfa/recovery-codes", fmt.Sprintf(`{"code":%q}`, recovery[2]), tok.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = app.verifyMFA(app.challenge(t, "ada@example.com", "Sup3r-secret"), recovery[3])
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestMFA_LocksAfterRepeatedFailures(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Ada", "ada@example.com", "Sup3r-secret")
+	_, recovery := app.enrollMFA(t, app.login(t, "ada@example.com", "Sup3r-secret").AccessToken)
+
+	before, err := app.users.GetByEmail(context.Background(), "ada@example.com")
+	require.NoError(t, err)
+
+	challenge := app.challenge(t, "ada@example.com", "Sup3r-secret")
+	for i := 0; i < 5; i++ {
+		w := app.verifyMFA(challenge, "000000")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	// Even a valid code is refused while locked.
+	w := app.verifyMFA(challenge, recovery[0])
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "too many")
+
+	// Counting failures is not an edit of the user.
+	after, err := app.users.GetByEmail(context.Background(), "ada@example.com")
+	require.NoError(t, err)
+	assert.NotNil(t, after.Credential.MFALockedUntil)
+	assert.Equal(t, before.Version, after.Version)
+	assert.Equal(t, before.UpdatedAt, after.UpdatedAt)
+
+	// Nor can a profile edit based on an earlier read lift the lock.
+	before.Name = "Ada L."
+	_, err = app.users.Update(context.Background(), before)
+	require.NoError(t, err)
+	after, err = app.users.GetByEmail(context.Background(), "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada L.", after.Name)
+	assert.NotNil(t, after.Credential.MFALockedUntil)
+}
+
+func TestMFA_Disable(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Ada", "ada@example.com", "Sup3r-secret")
+	session := app.login(t, "ada@example.com", "Sup3r-secret")
+	_, recovery := app.enrollMFA(t, session.AccessToken)
+
+	w := app.do(http.MethodPost, "/api/v1/auth/mfa/totp", "", session.AccessToken)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/mfa/disable", `{"code":"123456"}`, session.AccessToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/mfa/disable", fmt.Sprintf(`{"code":%q}`, recovery[0]), session.AccessToken)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	// Password alone is enough again.
+	app.login(t, "ada@example.com", "Sup3r-secret")
+}
+
+func TestMFA_PolicyBlocksChangesWithoutSecondFactor(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	adminToken := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	w := app.do(http.MethodPut, "/api/v1/auth/mfa/policy", `{"roles":["admin"]}`, app.tokenFor(t, member.ID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = app.do(http.MethodPut, "/api/v1/auth/mfa/policy", `{"roles":["owner"]}`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = app.do(http.MethodPut, "/api/v1/auth/mfa/policy", `{"roles":["admin","admin"]}`, adminToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"roles":["admin"]}`, w.Body.String())
+
+	// Reads still work, changes need an MFA session.
+	path := fmt.Sprintf("/api/v1/users/%d", member.ID)
+	w = app.do(http.MethodGet, path, "", adminToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = app.do(http.MethodPut, path, `{"name":"Renamed"}`, adminToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	tok, err := app.tokens.Issue(&domain.Principal{UserID: admin.ID, TenantID: domain.DefaultTenantID, Role: domain.RoleAdmin, MFA: true})
+	require.NoError(t, err)
+	w = app.do(http.MethodPut, path, `{"name":"Renamed"}`, tok.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Nor can a password-only session lift the policy that binds it.
+	w = app.do(http.MethodPut, "/api/v1/auth/mfa/policy", `{"roles":[]}`, adminToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = app.do(http.MethodGet, "/api/v1/auth/mfa/policy", "", adminToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = app.do(http.MethodPut, "/api/v1/auth/mfa/policy", `{"roles":["admin"]}`, tok.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Roles outside the policy are unaffected.
+	w = app.do(http.MethodPut, path, `{"name":"Mine"}`, app.tokenFor(t, member.ID))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -0,0 +1,407 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+const (
+	oauthRedirect = "https://app.example/callback"
+	oauthVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+type oauthClient struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type oauthTokens struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+	Error        string `json:"error"`
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// registerClient registers an OAuth client as an admin of the default tenant.
+func (a *testApp) registerClient(t *testing.T, adminToken, body string) oauthClient {
+	t.Helper()
+	w := a.do(http.MethodPost, "/api/v1/oauth/clients", body, adminToken)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var c oauthClient
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &c))
+	return c
+}
+
+// postForm sends a form to an OAuth endpoint, authenticating the client with
+// HTTP Basic when a secret is given.
+func (a *testApp) postForm(path string, form url.Values, client oauthClient) *httptest.ResponseRecorder {
+	if client.ClientSecret == "" && client.ClientID != "" {
+		form.Set("client_id", client.ClientID)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if client.ClientSecret != "" {
+		req.SetBasicAuth(client.ClientID, client.ClientSecret)
+	}
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, req)
+	return w
+}
+
+// authorize runs /oauth/authorize for the user behind token and returns the redirect.
+func (a *testApp) authorize(t *testing.T, token string, params url.Values) *url.URL {
+	t.Helper()
+	w := a.do(http.MethodGet, "/oauth/authorize?"+params.Encode(), "", token)
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	loc, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	return loc
+}
+
+func authorizeParams(clientID string) url.Values {
+	return url.Values{
+		"client_id":             {clientID},
+		"redirect_uri":          {oauthRedirect},
+		"response_type":         {"code"},
+		"scope":                 {"profile"},
+		"state":                 {"xyz"},
+		"code_challenge":        {pkceChallenge(oauthVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+}
+
+func decodeTokens(t *testing.T, w *httptest.ResponseRecorder) oauthTokens {
+	t.Helper()
+	var tok oauthTokens
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+	return tok
+}
+
+// oauthSetup registers a confidential web client and returns it with a member's access token.
+func oauthSetup(t *testing.T, app *testApp) (oauthClient, *domain.User, string) {
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	client := app.registerClient(t, app.tokenForRole(t, admin.ID, domain.RoleAdmin), fmt.Sprintf(
+		`{"name":"Wiki","redirect_uris":[%q],"grant_types":["authorization_code","refresh_token"],"scopes":["profile","email"]}`, oauthRedirect))
+	require.NotEmpty(t, client.ClientSecret)
+	return client, member, app.tokenFor(t, member.ID)
+}
+
+func TestOAuth_AuthorizationCodeWithPKCE(t *testing.T) {
+	app := newTestApp(t)
+	client, member, token := oauthSetup(t, app)
+
+	loc := app.authorize(t, token, authorizeParams(client.ClientID))
+	assert.Equal(t, "app.example", loc.Host)
+	assert.Equal(t, "xyz", loc.Query().Get("state"))
+	code := loc.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	exchange := url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {oauthRedirect}}
+
+	exchange.Set("code_verifier", strings.Repeat("x", 43))
+	w := app.postForm("/oauth/token", exchange, client)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalid_grant", decodeTokens(t, w).Error)
+
+	exchange.Set("code_verifier", oauthVerifier)
+	w = app.postForm("/oauth/token", exchange, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	tok := decodeTokens(t, w)
+	assert.Equal(t, "Bearer", tok.TokenType)
+	assert.Equal(t, "profile", tok.Scope)
+	require.NotEmpty(t, tok.RefreshToken)
+
+	w = app.postForm("/oauth/introspect", url.Values{"token": {tok.AccessToken}}, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, fmt.Sprintf(`{"active":true,"scope":"profile","client_id":%q,"username":"member@example.com","token_type":"Bearer","sub":"%d"}`, client.ClientID, member.ID),
+		withoutTimes(t, w.Body.Bytes()))
+
+	// Replaying the code fails and revokes what it was exchanged for.
+	w = app.postForm("/oauth/token", exchange, client)
+	assert.Equal(t, "invalid_grant", decodeTokens(t, w).Error)
+	w = app.postForm("/oauth/introspect", url.Values{"token": {tok.AccessToken}}, client)
+	assert.JSONEq(t, `{"active":false}`, w.Body.String())
+}
+
+func TestOAuth_AuthorizeErrors(t *testing.T) {
+	app := newTestApp(t)
+	client, _, token := oauthSetup(t, app)
+
+	// Untrusted client or redirect URI: answered here, never redirected.
+	params := authorizeParams("nope")
+	w := app.do(http.MethodGet, "/oauth/authorize?"+params.Encode(), "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	params = authorizeParams(client.ClientID)
+	params.Set("redirect_uri", "https://evil.example/cb")
+	w = app.do(http.MethodGet, "/oauth/authorize?"+params.Encode(), "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Without a token, a browser is asked to sign in.
+	w = app.do(http.MethodGet, "/oauth/authorize?"+authorizeParams(client.ClientID).Encode(), "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), `name="password"`)
+
+	// Everything else goes back to the client.
+	params = authorizeParams(client.ClientID)
+	params.Del("code_challenge")
+	loc := app.authorize(t, token, params)
+	assert.Equal(t, "invalid_request", loc.Query().Get("error"))
+	assert.Equal(t, "xyz", loc.Query().Get("state"))
+
+	params = authorizeParams(client.ClientID)
+	params.Set("code_challenge_method", "plain")
+	assert.Equal(t, "invalid_request", app.authorize(t, token, params).Query().Get("error"))
+
+	params = authorizeParams(client.ClientID)
+	params.Set("scope", "profile admin")
+	assert.Equal(t, "invalid_scope", app.authorize(t, token, params).Query().Get("error"))
+}
+
+// browser follows the authorization pages like a browser would: it keeps
+// the cookies it is given and posts the forms back.
+type browser struct {
+	app     *testApp
+	cookies map[string]*http.Cookie
+}
+
+var csrfField = regexp.MustCompile(`name="csrf" value="([^"]+)"`)
+
+func (b *browser) send(req *http.Request) *httptest.ResponseRecorder {
+	for _, c := range b.cookies {
+		req.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	b.app.router.ServeHTTP(w, req)
+	for _, c := range w.Result().Cookies() {
+		b.cookies[c.Name] = c
+	}
+	return w
+}
+
+func (b *browser) get(path string) *httptest.ResponseRecorder {
+	return b.send(httptest.NewRequest(http.MethodGet, path, nil))
+}
+
+// submit posts the form on page with the given fields filled in.
+func (b *browser) submit(t *testing.T, page *httptest.ResponseRecorder, params url.Values, fields map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	form := url.Values{}
+	for k, v := range params {
+		form[k] = v
+	}
+	if m := csrfField.FindStringSubmatch(page.Body.String()); m != nil {
+		form.Set("csrf", m[1])
+	}
+	for k, v := range fields {
+		form.Set(k, v)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return b.send(req)
+}
+
+func TestOAuth_BrowserLoginAndConsent(t *testing.T) {
+	app := newTestApp(t)
+	client, _, _ := oauthSetup(t, app)
+	userID := app.signUp(t, "Ada", "ada@example.com", "Sup3r-secret")
+	params := authorizeParams(client.ClientID)
+	b := &browser{app: app, cookies: map[string]*http.Cookie{}}
+
+	page := b.get("/oauth/authorize?" + params.Encode())
+	require.Equal(t, http.StatusOK, page.Code)
+	assert.Contains(t, page.Body.String(), "Sign in to continue to Wiki")
+
+	// Forms must come from a page this server rendered.
+	w := b.submit(t, &httptest.ResponseRecorder{Body: new(bytes.Buffer)}, params, map[string]string{"action": "login", "email": "ada@example.com", "password": "Sup3r-secret"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = b.submit(t, page, params, map[string]string{"action": "login", "email": "ada@example.com", "password": "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Incorrect email or password")
+
+	consent := b.submit(t, page, params, map[string]string{"action": "login", "email": "ada@example.com", "password": "Sup3r-secret"})
+	require.Equal(t, http.StatusOK, consent.Code, consent.Body.String())
+	assert.Contains(t, consent.Body.String(), "Authorize Wiki")
+	assert.Contains(t, consent.Body.String(), "<li>profile</li>")
+	// The consent page may not be framed by another site.
+	assert.Equal(t, "DENY", consent.Header().Get("X-Frame-Options"))
+	assert.Contains(t, consent.Header().Get("Content-Security-Policy"), "frame-ancestors 'none'")
+	session := b.cookies["userhub_oauth_session"]
+	require.NotNil(t, session)
+	assert.True(t, session.HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, session.SameSite)
+
+	w = b.submit(t, consent, params, map[string]string{"action": "approve"})
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+	loc, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", loc.Query().Get("state"))
+
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}, "code": {loc.Query().Get("code")}, "redirect_uri": {oauthRedirect}, "code_verifier": {oauthVerifier}}, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = app.postForm("/oauth/introspect", url.Values{"token": {decodeTokens(t, w).AccessToken}}, client)
+	assert.Contains(t, w.Body.String(), fmt.Sprintf(`"sub":"%d"`, userID))
+
+	// The next request skips the login; denying it tells the client so.
+	consent = b.get("/oauth/authorize?" + params.Encode())
+	assert.Contains(t, consent.Body.String(), "Authorize Wiki")
+	w = b.submit(t, consent, params, map[string]string{"action": "deny"})
+	require.Equal(t, http.StatusFound, w.Code)
+	loc, err = url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "access_denied", loc.Query().Get("error"))
+
+	// Approving needs the session cookie, not just the form.
+	delete(b.cookies, "userhub_oauth_session")
+	w = b.submit(t, consent, params, map[string]string{"action": "approve"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOAuth_RefreshTokenRotation(t *testing.T) {
+	app := newTestApp(t)
+	client, _, token := oauthSetup(t, app)
+
+	code := app.authorize(t, token, authorizeParams(client.ClientID)).Query().Get("code")
+	w := app.postForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {oauthRedirect}, "code_verifier": {oauthVerifier}}, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	first := decodeTokens(t, w)
+
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {first.RefreshToken}}, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	second := decodeTokens(t, w)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	// A refresh cannot widen the grant.
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {second.RefreshToken}, "scope": {"profile email"}}, client)
+	assert.Equal(t, "invalid_scope", decodeTokens(t, w).Error)
+
+	// Reusing a rotated refresh token ends the whole grant.
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {first.RefreshToken}}, client)
+	assert.Equal(t, "invalid_grant", decodeTokens(t, w).Error)
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {second.RefreshToken}}, client)
+	assert.Equal(t, "invalid_grant", decodeTokens(t, w).Error)
+	w = app.postForm("/oauth/introspect", url.Values{"token": {second.AccessToken}}, client)
+	assert.JSONEq(t, `{"active":false}`, w.Body.String())
+}
+
+func TestOAuth_ClientCredentialsAndRevocation(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	adminToken := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	service := app.registerClient(t, adminToken, `{"name":"Batch","grant_types":["client_credentials"],"scopes":["reports"]}`)
+	other := app.registerClient(t, adminToken, `{"name":"Other","grant_types":["client_credentials"],"scopes":["reports"]}`)
+
+	w := app.postForm("/oauth/token", url.Values{"grant_type": {"client_credentials"}}, oauthClient{ClientID: service.ClientID, ClientSecret: "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "invalid_client", decodeTokens(t, w).Error)
+
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}}, service)
+	assert.Equal(t, "unauthorized_client", decodeTokens(t, w).Error)
+
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"client_credentials"}}, service)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	tok := decodeTokens(t, w)
+	assert.Empty(t, tok.RefreshToken)
+	assert.Equal(t, "reports", tok.Scope)
+
+	// Only the client a token was issued to can revoke it; unknown tokens are fine.
+	w = app.postForm("/oauth/revoke", url.Values{"token": {tok.AccessToken}}, other)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = app.postForm("/oauth/introspect", url.Values{"token": {tok.AccessToken}}, other)
+	assert.Contains(t, w.Body.String(), `"active":true`)
+
+	w = app.postForm("/oauth/revoke", url.Values{"token": {tok.AccessToken}}, service)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = app.postForm("/oauth/introspect", url.Values{"token": {tok.AccessToken}}, service)
+	assert.JSONEq(t, `{"active":false}`, w.Body.String())
+	w = app.postForm("/oauth/revoke", url.Values{"token": {"unknown"}}, service)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestOAuth_PublicClient(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	adminToken := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	w := app.do(http.MethodPost, "/api/v1/oauth/clients", `{"name":"SPA","public":true,"grant_types":["client_credentials"]}`, adminToken)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	spa := app.registerClient(t, adminToken, fmt.Sprintf(`{"name":"SPA","public":true,"redirect_uris":[%q],"grant_types":["authorization_code"],"scopes":["profile"]}`, oauthRedirect))
+	assert.Empty(t, spa.ClientSecret)
+
+	code := app.authorize(t, app.tokenFor(t, member.ID), authorizeParams(spa.ClientID)).Query().Get("code")
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {oauthRedirect}, "code_verifier": {oauthVerifier}}, spa)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	tok := decodeTokens(t, w)
+	assert.Empty(t, tok.RefreshToken, "client did not register for refresh tokens")
+
+	// Public clients cannot introspect.
+	w = app.postForm("/oauth/introspect", url.Values{"token": {tok.AccessToken}}, spa)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOAuth_ClientManagement(t *testing.T) {
+	app := newTestApp(t)
+	client, member, token := oauthSetup(t, app)
+	admin, err := app.users.GetByEmail(context.Background(), "admin@example.com")
+	require.NoError(t, err)
+	adminToken := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	w := app.do(http.MethodGet, "/api/v1/oauth/clients", "", app.tokenFor(t, member.ID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = app.do(http.MethodGet, "/api/v1/oauth/clients", "", adminToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), client.ClientID)
+	assert.NotContains(t, w.Body.String(), client.ClientSecret)
+
+	code := app.authorize(t, token, authorizeParams(client.ClientID)).Query().Get("code")
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {oauthRedirect}, "code_verifier": {oauthVerifier}}, client)
+	require.Equal(t, http.StatusOK, w.Code)
+	tok := decodeTokens(t, w)
+
+	w = app.do(http.MethodDelete, "/api/v1/oauth/clients/"+client.ClientID, "", adminToken)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	// The client and everything issued to it are gone.
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {tok.RefreshToken}}, client)
+	assert.Equal(t, "invalid_client", decodeTokens(t, w).Error)
+}
+
+// withoutTimes drops the exp and iat members of an introspection response.
+func withoutTimes(t *testing.T, body []byte) string {
+	t.Helper()
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(body, &m))
+	assert.NotZero(t, m["exp"])
+	assert.NotZero(t, m["iat"])
+	delete(m, "exp")
+	delete(m, "iat")
+	out, _ := json.Marshal(m)
+	return string(out)
+}
@@ -0,0 +1,221 @@
+package http_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+	"userHub/internal/store/memory"
+)
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (a *testApp) jwks(t *testing.T) jwks {
+	t.Helper()
+	w := a.do(http.MethodGet, "/jwks.json", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var set jwks
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	return set
+}
+
+// verifyIDToken checks an ID token against the published keys and returns its claims.
+func (a *testApp) verifyIDToken(t *testing.T, raw string) (jwt.MapClaims, string) {
+	t.Helper()
+	set := a.jwks(t)
+	claims := jwt.MapClaims{}
+	tok, err := jwt.ParseWithClaims(raw, claims, func(tok *jwt.Token) (any, error) {
+		for _, k := range set.Keys {
+			if k.Kid == tok.Header["kid"] {
+				n, _ := base64.RawURLEncoding.DecodeString(k.N)
+				e, _ := base64.RawURLEncoding.DecodeString(k.E)
+				return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown kid %v", tok.Header["kid"])
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	return claims, tok.Header["kid"].(string)
+}
+
+type oidcTokens struct {
+	oauthTokens
+	IDToken string `json:"id_token"`
+}
+
+// oidcLogin runs the code flow for token's user with the given scope.
+func (a *testApp) oidcLogin(t *testing.T, client oauthClient, token, scope string) oidcTokens {
+	t.Helper()
+	params := authorizeParams(client.ClientID)
+	params.Set("scope", scope)
+	params.Set("nonce", "n-0S6_WzA2Mj")
+	code := a.authorize(t, token, params).Query().Get("code")
+	w := a.postForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {oauthRedirect}, "code_verifier": {oauthVerifier}}, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tok oidcTokens
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+	return tok
+}
+
+// oidcSetup registers a client allowed every OpenID scope.
+func oidcSetup(t *testing.T, app *testApp) (oauthClient, *domain.User, string) {
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	client := app.registerClient(t, app.tokenForRole(t, admin.ID, domain.RoleAdmin), fmt.Sprintf(
+		`{"name":"Wiki","redirect_uris":[%q],"grant_types":["authorization_code","refresh_token"],"scopes":["openid","profile","email"]}`, oauthRedirect))
+	return client, member, app.tokenFor(t, member.ID)
+}
+
+func TestOIDC_Discovery(t *testing.T) {
+	app := newTestApp(t)
+
+	w := app.do(http.MethodGet, "/.well-known/openid-configuration", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "https://id.test", doc["issuer"])
+	assert.Equal(t, "https://id.test/oauth/authorize", doc["authorization_endpoint"])
+	assert.Equal(t, "https://id.test/oauth/token", doc["token_endpoint"])
+	assert.Equal(t, "https://id.test/userinfo", doc["userinfo_endpoint"])
+	assert.Equal(t, "https://id.test/jwks.json", doc["jwks_uri"])
+	assert.Equal(t, []any{"RS256"}, doc["id_token_signing_alg_values_supported"])
+	assert.Equal(t, []any{"S256"}, doc["code_challenge_methods_supported"])
+	assert.Contains(t, doc["scopes_supported"], "openid")
+
+	set := app.jwks(t)
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "RSA", set.Keys[0].Kty)
+	assert.Equal(t, "RS256", set.Keys[0].Alg)
+	assert.NotEmpty(t, set.Keys[0].Kid)
+}
+
+func TestOIDC_IDToken(t *testing.T) {
+	app := newTestApp(t)
+	client, member, token := oidcSetup(t, app)
+
+	tok := app.oidcLogin(t, client, token, "openid profile email")
+	require.NotEmpty(t, tok.IDToken)
+	claims, _ := app.verifyIDToken(t, tok.IDToken)
+	assert.Equal(t, strconv.FormatUint(uint64(member.ID), 10), claims["sub"])
+	assert.Equal(t, "https://id.test", claims["iss"])
+	assert.Equal(t, client.ClientID, claims["aud"])
+	assert.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+	assert.Equal(t, "Seed", claims["name"])
+	assert.Equal(t, "male", claims["gender"])
+	assert.Equal(t, "member@example.com", claims["email"])
+	assert.Equal(t, false, claims["email_verified"])
+
+	// Refreshing issues a fresh ID token, without the nonce.
+	w := app.postForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {tok.RefreshToken}}, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var refreshed oidcTokens
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	claims, _ = app.verifyIDToken(t, refreshed.IDToken)
+	assert.Equal(t, client.ClientID, claims["aud"])
+	assert.NotContains(t, claims, "nonce")
+
+	// Without openid this is plain OAuth.
+	tok = app.oidcLogin(t, client, token, "profile")
+	assert.Empty(t, tok.IDToken)
+}
+
+func TestOIDC_UserInfo(t *testing.T) {
+	app := newTestApp(t)
+	client, member, token := oidcSetup(t, app)
+	sub := strconv.FormatUint(uint64(member.ID), 10)
+
+	tok := app.oidcLogin(t, client, token, "openid email")
+	w := app.do(http.MethodGet, "/userinfo", "", tok.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, fmt.Sprintf(`{"sub":%q,"email":"member@example.com","email_verified":false}`, sub), w.Body.String())
+
+	tok = app.oidcLogin(t, client, token, "openid")
+	w = app.do(http.MethodPost, "/userinfo", "", tok.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, fmt.Sprintf(`{"sub":%q}`, sub), w.Body.String())
+
+	tok = app.oidcLogin(t, client, token, "profile")
+	w = app.do(http.MethodGet, "/userinfo", "", tok.AccessToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "insufficient_scope")
+
+	w = app.do(http.MethodGet, "/userinfo", "", "not-a-token")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "invalid_token")
+	w = app.do(http.MethodGet, "/userinfo", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOIDC_KeyRotation(t *testing.T) {
+	app := newTestApp(t)
+	client, _, token := oidcSetup(t, app)
+
+	before := app.oidcLogin(t, client, token, "openid")
+	_, oldKid := app.verifyIDToken(t, before.IDToken)
+
+	require.NoError(t, app.keys.Rotate())
+	set := app.jwks(t)
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, oldKid, set.Keys[1].Kid)
+
+	// Tokens signed before the rotation still verify; new ones use the new key.
+	app.verifyIDToken(t, before.IDToken)
+	after := app.oidcLogin(t, client, token, "openid")
+	_, newKid := app.verifyIDToken(t, after.IDToken)
+	assert.Equal(t, set.Keys[0].Kid, newKid)
+	assert.NotEqual(t, oldKid, newKid)
+}
+
+func TestOIDC_StoredKeysAreShared(t *testing.T) {
+	ctx := context.Background()
+	keys := memory.NewSigningKeyStore()
+	now := time.Now().UTC()
+	require.NoError(t, keys.Create(ctx, &domain.SigningKey{ID: "expired", PEM: signingKeyPEM(t), CreatedAt: now.Add(-5 * time.Hour)}))
+	require.NoError(t, keys.Create(ctx, &domain.SigningKey{ID: "current", PEM: signingKeyPEM(t), CreatedAt: now.Add(-3 * time.Hour)}))
+
+	// The current key is due for rotation: a new one is stored and published,
+	// but the current key keeps signing until every instance has seen it.
+	// The key it replaced was retired long enough ago to be dropped.
+	first, err := auth.NewStoredKeyRing(ctx, keys, time.Hour, 2*time.Hour)
+	require.NoError(t, err)
+	published := first.PublicKeys()
+	require.Len(t, published, 2)
+	assert.Equal(t, "current", published[1].Kid)
+	stored, err := keys.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, published[0].Kid, stored[0].ID)
+
+	// Another instance, or this one after a restart, uses the same keys.
+	second, err := auth.NewStoredKeyRing(ctx, keys, time.Hour, 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, published, second.PublicKeys())
+
+	signed, err := second.Sign(map[string]any{"sub": "1"})
+	require.NoError(t, err)
+	tok, _, err := jwt.NewParser().ParseUnverified(signed, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "current", tok.Header["kid"])
+}
@@ -0,0 +1,111 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+// forgotPassword requests a reset for each address and waits until the
+// background worker has processed them: it handles requests in order, so
+// once a marker user's link arrives, the others are done too.
+func (a *testApp) forgotPassword(t *testing.T, emails ...string) {
+	t.Helper()
+	marker := fmt.Sprintf("marker-%d@example.test", time.Now().UnixNano())
+	seedUser(t, a, marker, domain.RoleMember)
+	for _, email := range append(emails, marker) {
+		w := a.do(http.MethodPost, "/api/v1/auth/password/forgot", fmt.Sprintf(`{"email":%q}`, email), "")
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	}
+	require.Eventually(t, func() bool {
+		_, ok := a.outbox.Last(marker)
+		return ok
+	}, 5*time.Second, time.Millisecond)
+}
+
+// mailsTo counts the messages sent to the given address.
+func (a *testApp) mailsTo(to string) int {
+	n := 0
+	for _, msg := range a.outbox.Messages() {
+		if msg.To == to {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPasswordResetReplacesPasswordAndEndsSessions(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	session := app.login(t, "ada@example.com", "Sup3rSecret")
+
+	app.forgotPassword(t, "ada@example.com")
+	reset := app.tokenMailedTo(t, "ada@example.com")
+
+	// The new password must satisfy the policy.
+	w := app.do(http.MethodPost, "/api/v1/auth/password/reset", fmt.Sprintf(`{"token":%q,"new_password":"weak"}`, reset), "")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/password/reset", fmt.Sprintf(`{"token":%q,"new_password":"N3wSecret!"}`, reset), "")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	w = app.do(http.MethodPost, "/api/v1/auth/refresh", fmt.Sprintf(`{"refresh_token":%q}`, session.RefreshToken), "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "existing sessions are revoked")
+
+	w = app.do(http.MethodPost, "/api/v1/auth/login", `{"email":"ada@example.com","password":"Sup3rSecret"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	app.login(t, "ada@example.com", "N3wSecret!")
+
+	// Tokens are single-use.
+	w = app.do(http.MethodPost, "/api/v1/auth/password/reset", fmt.Sprintf(`{"token":%q,"new_password":"An0therOne"}`, reset), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPasswordResetRevokesOAuthTokensAndAPIKeys(t *testing.T) {
+	app := newTestApp(t)
+	client, member, session := oauthSetup(t, app)
+	key := app.createAPIKey(t, session, member.ID, "users:read")
+	code := app.authorize(t, session, authorizeParams(client.ClientID)).Query().Get("code")
+	w := app.postForm("/oauth/token", url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {oauthRedirect}, "code_verifier": {oauthVerifier}}, client)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	granted := decodeTokens(t, w)
+
+	app.forgotPassword(t, member.Email)
+	reset := app.tokenMailedTo(t, member.Email)
+	w = app.do(http.MethodPost, "/api/v1/auth/password/reset", fmt.Sprintf(`{"token":%q,"new_password":"N3wSecret!"}`, reset), "")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	w = app.postForm("/oauth/introspect", url.Values{"token": {granted.AccessToken}}, client)
+	assert.JSONEq(t, `{"active":false}`, w.Body.String())
+	w = app.postForm("/oauth/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {granted.RefreshToken}}, client)
+	assert.Equal(t, "invalid_grant", decodeTokens(t, w).Error)
+	assert.Equal(t, http.StatusUnauthorized, app.withAPIKey(http.MethodGet, "/api/v1/users/me", "", key.Key))
+}
+
+func TestForgotPasswordDoesNotRevealAccounts(t *testing.T) {
+	app := newTestApp(t)
+
+	app.forgotPassword(t, "nobody@example.com")
+	assert.Zero(t, app.mailsTo("nobody@example.com"))
+}
+
+func TestForgotPasswordIsRateLimited(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+
+	verification := app.mailsTo("ada@example.com")
+	app.forgotPassword(t, "ada@example.com", "ada@example.com", "ada@example.com", "ada@example.com", "ada@example.com")
+	assert.Equal(t, 3, app.mailsTo("ada@example.com")-verification)
+
+	// The last link mailed still works.
+	latest := app.tokenMailedTo(t, "ada@example.com")
+	w := app.do(http.MethodPost, "/api/v1/auth/password/reset", fmt.Sprintf(`{"token":%q,"new_password":"N3wSecret!"}`, latest), "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
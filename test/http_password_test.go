@@ -0,0 +1,97 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/pkg/password"
+)
+
+func TestCreateUserEnforcesPasswordPolicy(t *testing.T) {
+	app := newTestApp(t)
+
+	w := app.do(http.MethodPost, "/api/v1/users", `{"name":"Jane","email":"jane@example.com","gender":"female","password":"short"}`, "")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp struct {
+		Error struct {
+			Fields map[string]string `json:"fields"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Error.Fields["Password"], "8 to 128 characters")
+}
+
+func TestPasswordHashIsNeverExposed(t *testing.T) {
+	app := newTestApp(t)
+
+	w := app.do(http.MethodPost, "/api/v1/users", `{"name":"Jane","email":"jane@example.com","gender":"female","password":"Sup3rSecret"}`, "")
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, strings.ToLower(w.Body.String()), "password")
+	assert.NotContains(t, w.Body.String(), "argon2id")
+
+	u, err := app.users.GetByEmail(context.Background(), "jane@example.com")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u.Credential.PasswordHash, "$argon2id$"))
+	assert.NotNil(t, u.Credential.PasswordChangedAt)
+}
+
+func TestLoginRehashesOutdatedParameters(t *testing.T) {
+	app := newTestApp(t)
+	w := app.do(http.MethodPost, "/api/v1/users", `{"name":"Jane","email":"jane@example.com","gender":"female"}`, "")
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// Store a hash made with older, different parameters.
+	old := testHashParams
+	old.Iterations = 2
+	hash, err := password.NewHasher(old).Hash("Sup3rSecret")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	u, err := app.users.GetByEmail(ctx, "jane@example.com")
+	require.NoError(t, err)
+	next := u.Credential
+	next.PasswordHash = hash
+	require.NoError(t, app.users.UpdateCredential(ctx, u.ID, u.Credential, next))
+	before, err := app.users.GetByID(ctx, u.ID)
+	require.NoError(t, err)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/login", `{"email":"jane@example.com","password":"Sup3rSecret"}`, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	u, err = app.users.GetByEmail(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, u.Credential.PasswordHash)
+	assert.Contains(t, u.Credential.PasswordHash, "m=1024,t=1,p=1")
+	// A rehash is not an edit: ETags and update stamps stay as they were.
+	assert.Equal(t, before.Version, u.Version)
+	assert.Equal(t, before.UpdatedAt, u.UpdatedAt)
+	assert.Equal(t, before.UpdatedBy, u.UpdatedBy)
+}
+
+func TestChangePassword(t *testing.T) {
+	app := newTestApp(t)
+	w := app.do(http.MethodPost, "/api/v1/users", `{"name":"Jane","email":"jane@example.com","gender":"female","password":"Sup3rSecret"}`, "")
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		ID uint `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	token := app.tokenFor(t, created.ID)
+
+	w = app.do(http.MethodPut, "/api/v1/users/me/password", `{"current_password":"wrong","new_password":"N3wSecret!"}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = app.do(http.MethodPut, "/api/v1/users/me/password", `{"current_password":"Sup3rSecret","new_password":"N3wSecret!"}`, token)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/login", `{"email":"jane@example.com","password":"N3wSecret!"}`, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
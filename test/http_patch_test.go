@@ -0,0 +1,101 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func patchHeaders(contentType string) map[string]string {
+	return map[string]string{"Content-Type": contentType}
+}
+
+func TestMergePatchUpdatesFields(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	path := fmt.Sprintf("/api/v1/users/%d", id)
+
+	w := app.doWithHeaders(http.MethodPatch, path, `{"name":"Ada Lovelace"}`, app.tokenFor(t, id),
+		patchHeaders("application/merge-patch+json"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"name":"Ada Lovelace"`)
+	require.Contains(t, w.Body.String(), `"gender":"female"`)
+	require.Equal(t, `"2"`, w.Header().Get("ETag"))
+}
+
+func TestMergePatchNullRemovesRequiredFieldAndFailsValidation(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+
+	w := app.doWithHeaders(http.MethodPatch, fmt.Sprintf("/api/v1/users/%d", id), `{"gender":null}`, app.tokenFor(t, id),
+		patchHeaders("application/merge-patch+json"))
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), "Gender")
+}
+
+func TestJSONPatchWithPassingTestOperation(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	patch := `[{"op":"test","path":"/name","value":"Ada"},{"op":"replace","path":"/name","value":"Countess"}]`
+
+	w := app.doWithHeaders(http.MethodPatch, fmt.Sprintf("/api/v1/users/%d", id), patch, app.tokenFor(t, id),
+		patchHeaders("application/json-patch+json"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"name":"Countess"`)
+}
+
+func TestJSONPatchFailingTestOperationLeavesUserUnchanged(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	token := app.tokenFor(t, id)
+	path := fmt.Sprintf("/api/v1/users/%d", id)
+	patch := `[{"op":"replace","path":"/gender","value":"male"},{"op":"test","path":"/name","value":"Grace"}]`
+
+	w := app.doWithHeaders(http.MethodPatch, path, patch, token, patchHeaders("application/json-patch+json"))
+	require.Equal(t, http.StatusPreconditionFailed, w.Code, w.Body.String())
+
+	w = app.do(http.MethodGet, path, "", token)
+	require.Contains(t, w.Body.String(), `"gender":"female"`)
+	require.Equal(t, `"1"`, w.Header().Get("ETag"))
+}
+
+func TestPatchRejectsReadOnlyAndUnknownFields(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	token := app.tokenFor(t, id)
+	path := fmt.Sprintf("/api/v1/users/%d", id)
+
+	w := app.doWithHeaders(http.MethodPatch, path, `{"email":"other@example.com"}`, token, patchHeaders("application/merge-patch+json"))
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), "read-only")
+
+	w = app.doWithHeaders(http.MethodPatch, path, `[{"op":"add","path":"/password","value":"x"}]`, token, patchHeaders("application/json-patch+json"))
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestPatchRoleChangeStillRequiresAdmin(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+
+	w := app.doWithHeaders(http.MethodPatch, fmt.Sprintf("/api/v1/users/%d", id), `{"role":"admin"}`, app.tokenFor(t, id),
+		patchHeaders("application/merge-patch+json"))
+	require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	admin := app.tokenForRole(t, 999, domain.RoleAdmin)
+	w = app.doWithHeaders(http.MethodPatch, fmt.Sprintf("/api/v1/users/%d", id), `{"role":"manager"}`, admin,
+		patchHeaders("application/merge-patch+json"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestPatchRejectsPlainJSON(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+
+	w := app.do(http.MethodPatch, fmt.Sprintf("/api/v1/users/%d", id), `{"name":"Ada L"}`, app.tokenFor(t, id))
+	require.Equal(t, http.StatusUnsupportedMediaType, w.Code, w.Body.String())
+	require.Contains(t, w.Header().Get("Accept-Patch"), "application/merge-patch+json")
+}
@@ -0,0 +1,89 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func seedUser(t *testing.T, app *testApp, email string, role domain.Role) *domain.User {
+	t.Helper()
+	u, err := app.users.Create(context.Background(), &domain.User{Name: "Seed", Email: email, Gender: "male", Role: role})
+	require.NoError(t, err)
+	return u
+}
+
+func TestMemberCanOnlyAccessOwnRecord(t *testing.T) {
+	app := newTestApp(t)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+	token := app.tokenFor(t, me.ID)
+
+	w := app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", me.ID), "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", me.ID), `{"name":"Renamed"}`, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", other.ID), "", token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), string(domain.CodeForbidden))
+
+	w = app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", other.ID), `{"name":"Hacked"}`, token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", other.ID), "", token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = app.do(http.MethodGet, "/api/v1/users", "", token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMemberCannotEscalateRole(t *testing.T) {
+	app := newTestApp(t)
+	me := seedUser(t, app, "me@example.com", domain.RoleMember)
+
+	w := app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", me.ID), `{"role":"admin"}`, app.tokenFor(t, me.ID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Sign-up has no say in the role.
+	w = app.do(http.MethodPost, "/api/v1/users", `{"name":"Eve","email":"eve@example.com","gender":"female","role":"admin"}`, "")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"role":"member"`)
+}
+
+func TestManagerCanListButNotDelete(t *testing.T) {
+	app := newTestApp(t)
+	manager := seedUser(t, app, "boss@example.com", domain.RoleManager)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	token := app.tokenForRole(t, manager.ID, domain.RoleManager)
+
+	w := app.do(http.MethodGet, "/api/v1/users", "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", member.ID), "", token)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminHasFullAccess(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	w := app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", member.ID), `{"role":"manager"}`, token)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"role":"manager"`)
+
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d/sessions", member.ID), "", token)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", member.ID), "", token)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
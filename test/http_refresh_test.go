@@ -0,0 +1,69 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func refreshBody(token string) string {
+	return fmt.Sprintf(`{"refresh_token":%q}`, token)
+}
+
+func TestRefreshRotatesAndDetectsReuse(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Jane", "jane@example.com", "Sup3rSecret")
+	first := app.login(t, "jane@example.com", "Sup3rSecret")
+	require.NotEmpty(t, first.RefreshToken)
+
+	w := app.do(http.MethodPost, "/api/v1/auth/refresh", refreshBody(first.RefreshToken), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var second tokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	// Replaying the rotated token revokes the whole family...
+	w = app.do(http.MethodPost, "/api/v1/auth/refresh", refreshBody(first.RefreshToken), "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "reuse")
+
+	// ...including the token that was legitimately issued after it.
+	w = app.do(http.MethodPost, "/api/v1/auth/refresh", refreshBody(second.RefreshToken), "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	app := newTestApp(t)
+	app.signUp(t, "Jane", "jane@example.com", "Sup3rSecret")
+	session := app.login(t, "jane@example.com", "Sup3rSecret")
+	other := app.login(t, "jane@example.com", "Sup3rSecret")
+
+	w := app.do(http.MethodPost, "/api/v1/auth/logout", refreshBody(session.RefreshToken), "")
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = app.do(http.MethodPost, "/api/v1/auth/refresh", refreshBody(session.RefreshToken), "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Other sessions are unaffected.
+	w = app.do(http.MethodPost, "/api/v1/auth/refresh", refreshBody(other.RefreshToken), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Jane", "jane@example.com", "Sup3rSecret")
+	a := app.login(t, "jane@example.com", "Sup3rSecret")
+	b := app.login(t, "jane@example.com", "Sup3rSecret")
+
+	w := app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d/sessions", id), "", a.AccessToken)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	for _, s := range []tokenPair{a, b} {
+		w = app.do(http.MethodPost, "/api/v1/auth/refresh", refreshBody(s.RefreshToken), "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,327 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type scimList struct {
+	TotalResults int               `json:"totalResults"`
+	StartIndex   int               `json:"startIndex"`
+	ItemsPerPage int               `json:"itemsPerPage"`
+	Resources    []json.RawMessage `json:"Resources"`
+}
+
+type scimResource struct {
+	ID          string `json:"id"`
+	UserName    string `json:"userName"`
+	DisplayName string `json:"displayName"`
+	Active      bool   `json:"active"`
+	Members     []struct {
+		Value string `json:"value"`
+	} `json:"members"`
+	Meta struct {
+		Location string `json:"location"`
+	} `json:"meta"`
+}
+
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType"`
+	Detail   string   `json:"detail"`
+}
+
+func (a *testApp) scim(method, path, body, token string) *httptest.ResponseRecorder {
+	return a.doWithHeaders(method, "/scim/v2"+path, body, token, map[string]string{"Content-Type": "application/scim+json"})
+}
+
+func decodeSCIM[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &v), w.Body.String())
+	return v
+}
+
+func scimPatch(ops string) string {
+	return `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":` + ops + `}`
+}
+
+func scimAdmin(t *testing.T, app *testApp) string {
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	return app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+}
+
+func TestSCIM_Discovery(t *testing.T) {
+	app := newTestApp(t)
+
+	w := app.scim(http.MethodGet, "/ServiceProviderConfig", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/scim+json")
+	var cfg map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cfg))
+	assert.Equal(t, map[string]any{"supported": true}, cfg["patch"])
+	assert.Equal(t, map[string]any{"supported": true, "maxResults": float64(100)}, cfg["filter"])
+
+	w = app.scim(http.MethodGet, "/ResourceTypes", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, decodeSCIM[scimList](t, w).TotalResults)
+	w = app.scim(http.MethodGet, "/ResourceTypes/Group", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"endpoint":"/Groups"`)
+
+	w = app.scim(http.MethodGet, "/Schemas", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, decodeSCIM[scimList](t, w).TotalResults)
+	w = app.scim(http.MethodGet, "/Schemas/urn:ietf:params:scim:schemas:core:2.0:User", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"userName"`)
+
+	w = app.scim(http.MethodGet, "/Schemas/urn:unknown", "", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "404", decodeSCIM[scimError](t, w).Status)
+}
+
+func TestSCIM_UserLifecycle(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	session := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	key := app.createAPIKey(t, session, admin.ID, "users:read", "users:write").Key
+
+	w := app.scim(http.MethodPost, "/Users", `{
+		"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName":"bjensen@example.com",
+		"name":{"givenName":"Barbara","familyName":"Jensen"},
+		"emails":[{"value":"bjensen@example.com","primary":true}],
+		"urn:userhub:params:scim:schemas:extension:2.0:User":{"gender":"female"}
+	}`, key)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	created := decodeSCIM[scimResource](t, w)
+	assert.Equal(t, "bjensen@example.com", created.UserName)
+	assert.Equal(t, "Barbara Jensen", created.DisplayName)
+	assert.True(t, created.Active)
+	assert.Equal(t, created.Meta.Location, w.Header().Get("Location"))
+	assert.Contains(t, created.Meta.Location, "/scim/v2/Users/"+created.ID)
+
+	w = app.scim(http.MethodGet, "/Users?filter="+url.QueryEscape(`userName eq "BJENSEN@example.com"`), "", key)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	list := decodeSCIM[scimList](t, w)
+	require.Equal(t, 1, list.TotalResults)
+	assert.Contains(t, string(list.Resources[0]), `"id":"`+created.ID+`"`)
+
+	w = app.scim(http.MethodPatch, "/Users/"+created.ID, scimPatch(`[{"op":"Replace","path":"displayName","value":"Babs Jensen"}]`), key)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Babs Jensen", decodeSCIM[scimResource](t, w).DisplayName)
+
+	w = app.scim(http.MethodPut, "/Users/"+created.ID, `{"userName":"bjensen@example.com","name":{"formatted":"Barbara J"},"urn:userhub:params:scim:schemas:extension:2.0:User":{"role":"manager"}}`, key)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"role":"manager"`)
+	assert.Contains(t, w.Body.String(), `"gender":"female"`)
+
+	// Deprovisioning deactivates the user.
+	w = app.scim(http.MethodPatch, "/Users/"+created.ID, scimPatch(`[{"op":"replace","value":{"active":false}}]`), key)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, decodeSCIM[scimResource](t, w).Active)
+	w = app.scim(http.MethodGet, "/Users/"+created.ID, "", key)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+	w = app.scim(http.MethodDelete, "/Users/"+fmt.Sprint(other.ID), "", key)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = app.scim(http.MethodDelete, "/Users/"+fmt.Sprint(other.ID), "", key)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSCIM_FilterAndPagination(t *testing.T) {
+	app := newTestApp(t)
+	token := scimAdmin(t, app)
+	for _, email := range []string{"ann@example.com", "bob@example.com", "bea@example.org", "cid@example.com"} {
+		seedUser(t, app, email, domain.RoleMember)
+	}
+
+	w := app.scim(http.MethodGet, "/Users?startIndex=2&count=2", "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	list := decodeSCIM[scimList](t, w)
+	assert.Equal(t, 5, list.TotalResults)
+	assert.Equal(t, 2, list.StartIndex)
+	require.Equal(t, 2, list.ItemsPerPage)
+	assert.Contains(t, string(list.Resources[0]), "ann@example.com")
+	assert.Contains(t, string(list.Resources[1]), "bob@example.com")
+
+	w = app.scim(http.MethodGet, "/Users?count=0", "", token)
+	list = decodeSCIM[scimList](t, w)
+	assert.Equal(t, 5, list.TotalResults)
+	assert.Empty(t, list.Resources)
+
+	filter := `(userName sw "b" or userName eq "cid@example.com") and not (emails.value ew ".org")`
+	w = app.scim(http.MethodGet, "/Users?filter="+url.QueryEscape(filter), "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	list = decodeSCIM[scimList](t, w)
+	require.Equal(t, 2, list.TotalResults)
+	assert.Contains(t, string(list.Resources[0]), "bob@example.com")
+	assert.Contains(t, string(list.Resources[1]), "cid@example.com")
+
+	w = app.scim(http.MethodGet, "/Users?filter="+url.QueryEscape(`emails[value co "example.org"]`)+"&startIndex=1&count=5", "", token)
+	assert.Equal(t, 1, decodeSCIM[scimList](t, w).TotalResults)
+
+	w = app.scim(http.MethodGet, "/Users?filter="+url.QueryEscape(`userName eq "ann@example.com" and`), "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	e := decodeSCIM[scimError](t, w)
+	assert.Equal(t, []string{"urn:ietf:params:scim:api:messages:2.0:Error"}, e.Schemas)
+	assert.Equal(t, "invalidFilter", e.ScimType)
+	assert.Contains(t, e.Detail, "position 34")
+}
+
+func TestSCIM_FilterOnLargeDirectory(t *testing.T) {
+	app := newTestApp(t)
+	token := scimAdmin(t, app)
+	var last *domain.User
+	for i := 0; i < 1000; i++ {
+		last = seedUser(t, app, fmt.Sprintf("user%04d@example.com", i), domain.RoleMember)
+	}
+
+	// Lookups by userName, id or displayName are answered by the repository.
+	for _, f := range []string{
+		`userName eq "USER0999@example.com"`,
+		fmt.Sprintf(`id eq "%d" and active eq true`, last.ID),
+		`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "user0999@example.com" and displayName eq "seed"`,
+	} {
+		w := app.scim(http.MethodGet, "/Users?filter="+url.QueryEscape(f), "", token)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		list := decodeSCIM[scimList](t, w)
+		require.Equal(t, 1, list.TotalResults, f)
+		assert.Contains(t, string(list.Resources[0]), "user0999@example.com")
+	}
+	w := app.scim(http.MethodGet, "/Users?filter="+url.QueryEscape(`id eq "x"`), "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 0, decodeSCIM[scimList](t, w).TotalResults)
+
+	// Anything else would be matched against every user.
+	w = app.scim(http.MethodGet, "/Users?filter="+url.QueryEscape(`userName sw "user09"`), "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "tooMany", decodeSCIM[scimError](t, w).ScimType)
+}
+
+func TestSCIM_Errors(t *testing.T) {
+	app := newTestApp(t)
+	token := scimAdmin(t, app)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+
+	w := app.scim(http.MethodPost, "/Users", `{"userName":"member@example.com","displayName":"Again"}`, token)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "uniqueness", decodeSCIM[scimError](t, w).ScimType)
+
+	w = app.scim(http.MethodPost, "/Users", `{"userName":"not-an-email","displayName":"X"}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	e := decodeSCIM[scimError](t, w)
+	assert.Equal(t, "invalidValue", e.ScimType)
+	assert.Contains(t, e.Detail, "Email")
+
+	id := fmt.Sprint(member.ID)
+	w = app.scim(http.MethodPatch, "/Users/"+id, scimPatch(`[{"op":"replace","path":"userName","value":"other@example.com"}]`), token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "mutability", decodeSCIM[scimError](t, w).ScimType)
+
+	w = app.scim(http.MethodPatch, "/Users/"+id, `{"Operations":[{"op":"replace","path":"displayName","value":"X Y"}]}`, token)
+	assert.Equal(t, "invalidSyntax", decodeSCIM[scimError](t, w).ScimType)
+	w = app.scim(http.MethodPatch, "/Users/"+id, scimPatch(`[{"op":"replace","path":"emails[type eq","value":"x"}]`), token)
+	assert.Equal(t, "invalidPath", decodeSCIM[scimError](t, w).ScimType)
+
+	w = app.scim(http.MethodGet, "/Users/999", "", token)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "404", decodeSCIM[scimError](t, w).Status)
+
+	// Provisioning follows the same rules as the REST API.
+	memberToken := app.tokenFor(t, member.ID)
+	w = app.scim(http.MethodGet, "/Users", "", memberToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = app.scim(http.MethodPost, "/Users", `{"userName":"boss@example.com","displayName":"Boss","urn:userhub:params:scim:schemas:extension:2.0:User":{"role":"admin"}}`, memberToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = app.scim(http.MethodGet, "/Users", "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSCIM_IfMatch(t *testing.T) {
+	app := newTestApp(t, withRequireIfMatch())
+	token := scimAdmin(t, app)
+	member := seedUser(t, app, "member@example.com", domain.RoleMember)
+	path := "/Users/" + fmt.Sprint(member.ID)
+	ifMatch := func(method, body, etag string) *httptest.ResponseRecorder {
+		return app.doWithHeaders(method, "/scim/v2"+path, body, token, map[string]string{"Content-Type": "application/scim+json", "If-Match": etag})
+	}
+	rename := scimPatch(`[{"op":"replace","path":"displayName","value":"Mem Ber"}]`)
+
+	w := app.scim(http.MethodPatch, path, rename, token)
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+	assert.Equal(t, "428", decodeSCIM[scimError](t, w).Status)
+	w = app.scim(http.MethodDelete, path, "", token)
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+	w = app.scim(http.MethodGet, path, "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+
+	w = ifMatch(http.MethodPatch, rename, etag)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	// The tag read before the PATCH is stale now.
+	w = ifMatch(http.MethodPut, `{"userName":"member@example.com","displayName":"Stale"}`, etag)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, "412", decodeSCIM[scimError](t, w).Status)
+	w = ifMatch(http.MethodDelete, "", etag)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	w = ifMatch(http.MethodDelete, "", "*")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestSCIM_Groups(t *testing.T) {
+	app := newTestApp(t)
+	token := scimAdmin(t, app)
+	ann := seedUser(t, app, "ann@example.com", domain.RoleMember)
+	bob := seedUser(t, app, "bob@example.com", domain.RoleMember)
+
+	w := app.scim(http.MethodPost, "/Groups", fmt.Sprintf(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:Group"],"displayName":"Engineering","members":[{"value":"%d"}]}`, ann.ID), token)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	group := decodeSCIM[scimResource](t, w)
+	require.Len(t, group.Members, 1)
+	assert.Equal(t, fmt.Sprint(ann.ID), group.Members[0].Value)
+
+	w = app.scim(http.MethodPatch, "/Groups/"+group.ID, scimPatch(fmt.Sprintf(`[{"op":"add","path":"members","value":[{"value":"%d"}]}]`, bob.ID)), token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, decodeSCIM[scimResource](t, w).Members, 2)
+
+	w = app.scim(http.MethodPatch, "/Groups/"+group.ID, scimPatch(fmt.Sprintf(`[{"op":"remove","path":"members[value eq \"%d\"]"},{"op":"replace","path":"displayName","value":"Platform"}]`, ann.ID)), token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	group = decodeSCIM[scimResource](t, w)
+	assert.Equal(t, "Platform", group.DisplayName)
+	require.Len(t, group.Members, 1)
+	assert.Equal(t, fmt.Sprint(bob.ID), group.Members[0].Value)
+
+	w = app.scim(http.MethodGet, "/Groups?filter="+url.QueryEscape(`displayName eq "platform"`), "", token)
+	assert.Equal(t, 1, decodeSCIM[scimList](t, w).TotalResults)
+	w = app.scim(http.MethodGet, "/Groups?filter="+url.QueryEscape(fmt.Sprintf(`members[value eq "%d"]`, ann.ID)), "", token)
+	assert.Equal(t, 0, decodeSCIM[scimList](t, w).TotalResults)
+
+	w = app.scim(http.MethodPut, "/Groups/"+group.ID, `{"displayName":"Platform","members":[{"value":"999"}]}`, token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "invalidValue", decodeSCIM[scimError](t, w).ScimType)
+
+	w = app.scim(http.MethodPost, "/Groups", `{"displayName":"platform"}`, token)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = app.scim(http.MethodDelete, "/Groups/"+group.ID, "", token)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = app.scim(http.MethodGet, "/Groups/"+group.ID, "", token)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
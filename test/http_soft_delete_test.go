@@ -0,0 +1,94 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func TestDeletedUsersAreHiddenAndRestorable(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	victim := seedUser(t, app, "victim@example.com", domain.RoleMember)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	w := app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", victim.ID), "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", victim.ID), "", token)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", victim.ID), "", token)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	_, err := app.users.GetByEmail(context.Background(), "victim@example.com")
+	assert.Error(t, err)
+
+	w = app.do(http.MethodGet, "/api/v1/users", "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "victim@example.com")
+
+	// The email stays reserved while the account can still be restored.
+	w = app.do(http.MethodPost, "/api/v1/users", `{"name":"Copycat","email":"victim@example.com","gender":"male"}`, "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/restore", victim.ID), "", token)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", victim.ID), "", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"), "a restore is a new version")
+
+	// Restoring a live user is a not-found.
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/restore", victim.ID), "", token)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPurgeHonoursRetentionAndRequiresAdmin(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		retention time.Duration
+		purged    int64
+	}{
+		{"within retention", time.Hour, 0},
+		{"past retention", 0, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newTestApp(t, withRetention(tc.retention))
+			admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+			member := seedUser(t, app, "member@example.com", domain.RoleMember)
+			token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+			w := app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", member.ID), "", token)
+			require.Equal(t, http.StatusNoContent, w.Code)
+
+			w = app.do(http.MethodPost, "/api/v1/users/purge", "", app.tokenFor(t, member.ID))
+			assert.Equal(t, http.StatusForbidden, w.Code)
+
+			w = app.do(http.MethodPost, "/api/v1/users/purge", "", token)
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp struct {
+				Purged int64 `json:"purged"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tc.purged, resp.Purged)
+
+			// Once purged the user can no longer be restored.
+			w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/restore", member.ID), "", token)
+			if tc.purged == 1 {
+				assert.Equal(t, http.StatusNotFound, w.Code)
+			} else {
+				assert.Equal(t, http.StatusOK, w.Code)
+			}
+		})
+	}
+}
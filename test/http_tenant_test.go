@@ -0,0 +1,156 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+	"userHub/internal/service"
+	"userHub/internal/store/memory"
+)
+
+// createOrg creates an organization with an owner as the platform admin and returns its ID.
+func createOrg(t *testing.T, app *testApp, slug, ownerEmail string) uint {
+	t.Helper()
+	admin := seedUser(t, app, "root-"+slug+"@example.com", domain.RoleAdmin)
+
+	body := fmt.Sprintf(`{"name":"Org %[1]s","slug":%[1]q,"owner":{"name":"Owner","email":%[2]q,"gender":"male","password":"Sup3rSecret"}}`, slug, ownerEmail)
+	w := app.do(http.MethodPost, "/api/v1/orgs", body, app.tokenForRole(t, admin.ID, domain.RoleAdmin))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp struct {
+		Organization struct {
+			ID uint `json:"id"`
+		} `json:"organization"`
+		Owner struct {
+			Role string `json:"role"`
+		} `json:"owner"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "admin", resp.Owner.Role)
+	return resp.Organization.ID
+}
+
+func tenantHeader(id uint) map[string]string {
+	return map[string]string{"X-Tenant-ID": strconv.FormatUint(uint64(id), 10)}
+}
+
+func TestTenantsAreIsolated(t *testing.T) {
+	app := newTestApp(t)
+	acme := createOrg(t, app, "acme", "owner@acme.test")
+	outsider := seedUser(t, app, "outsider@example.com", domain.RoleMember)
+
+	// The owner logs in against their own tenant.
+	w := app.doWithHeaders(http.MethodPost, "/api/v1/auth/login", `{"email":"owner@acme.test","password":"Sup3rSecret"}`, "", tenantHeader(acme))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tok tokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+
+	// Without the header the default tenant has no such user.
+	w = app.do(http.MethodPost, "/api/v1/auth/login", `{"email":"owner@acme.test","password":"Sup3rSecret"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = app.do(http.MethodGet, "/api/v1/users", "", tok.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Data []struct {
+			Email string `json:"email"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "owner@acme.test", list.Data[0].Email)
+
+	// Another tenant's user does not exist from acme's point of view.
+	w = app.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", outsider.ID), "", tok.AccessToken)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = app.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", outsider.ID), "", tok.AccessToken)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// A token cannot be replayed against another tenant.
+	w = app.doWithHeaders(http.MethodGet, "/api/v1/users", "", tok.AccessToken, tenantHeader(domain.DefaultTenantID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Tenant admins cannot create organizations.
+	w = app.do(http.MethodPost, "/api/v1/orgs", `{"name":"Evil","slug":"evil","owner":{"name":"Eve","email":"eve@evil.test","gender":"female"}}`, tok.AccessToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestOrganizationOwnerIsAuditedAndVerified(t *testing.T) {
+	app := newTestApp(t)
+	acme := createOrg(t, app, "acme", "owner@acme.test")
+
+	// The owner gets the same verification link as anyone signing up.
+	app.tokenMailedTo(t, "owner@acme.test")
+
+	w := app.doWithHeaders(http.MethodPost, "/api/v1/auth/login", `{"email":"owner@acme.test","password":"Sup3rSecret"}`, "", tenantHeader(acme))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var tok tokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+
+	w = app.do(http.MethodGet, "/api/v1/audit?action=user.created", "", tok.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var events auditList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+	require.Len(t, events.Data, 1)
+	assert.Equal(t, "admin", events.Data[0].Changes["role"].To)
+	assert.NotZero(t, events.Data[0].ActorID, "the platform admin is the actor")
+}
+
+func TestEmailIsUniquePerTenant(t *testing.T) {
+	app := newTestApp(t)
+	acme := createOrg(t, app, "acme", "owner@acme.test")
+
+	body := `{"name":"Jane","email":"jane@example.com","gender":"female"}`
+	w := app.do(http.MethodPost, "/api/v1/users", body, "")
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = app.doWithHeaders(http.MethodPost, "/api/v1/users", body, "", tenantHeader(acme))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = app.doWithHeaders(http.MethodPost, "/api/v1/users", body, "", tenantHeader(acme))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUnknownTenantIsRejected(t *testing.T) {
+	app := newTestApp(t)
+	w := app.doWithHeaders(http.MethodPost, "/api/v1/users", `{"name":"Jane","email":"jane@example.com","gender":"female"}`, "", tenantHeader(999))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// failingUsers is a UserRepository whose Create always fails.
+type failingUsers struct {
+	domain.UserRepository
+}
+
+func (failingUsers) Create(context.Context, *domain.User) (*domain.User, error) {
+	return nil, errors.New("database is unavailable")
+}
+
+func TestFailedOwnerCreationRemovesOrganization(t *testing.T) {
+	orgs := memory.NewOrganizationStore()
+	require.NoError(t, service.BootstrapOrganization(context.Background(), orgs))
+	authz := service.NewAuthorizer(memory.NewGroupStore())
+	users := service.NewUserService(failingUsers{memory.NewUserStore()}, authz, memory.NewAuditStore(), nil, time.Hour, []byte(testSecret))
+	svc := service.NewOrganizationService(orgs, users, authz)
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: 1, TenantID: domain.DefaultTenantID, Role: domain.RoleAdmin})
+
+	_, _, err := svc.Create(ctx, &domain.Organization{Name: "Acme", Slug: "acme"}, &domain.User{Name: "Owner", Email: "owner@acme.test"})
+	require.Error(t, err)
+
+	// The organization is not left behind without an admin.
+	_, err = orgs.GetBySlug(ctx, "acme")
+	assert.Error(t, err)
+	all, err := orgs.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
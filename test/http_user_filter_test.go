@@ -0,0 +1,66 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func TestUserFilter_Expression(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	jane := seedNamedUser(t, app, "Jane Smith", "jane@example.com", "female")
+	john := seedNamedUser(t, app, "John Smith", "john@example.com", "male")
+	seedNamedUser(t, app, "Mary Jones", "mary@example.com", "female")
+	amos := seedNamedUser(t, app, "Amos Smithers", "amos@other.test", "male")
+
+	list := func(expr string, extra string) []uint {
+		t.Helper()
+		return app.listByCursor(t, token, "filter="+url.QueryEscape(expr)+extra).ids()
+	}
+
+	assert.Equal(t, []uint{jane.ID, john.ID, amos.ID}, list(`name co "SMITH"`, ""))
+	assert.Equal(t, []uint{jane.ID, amos.ID}, list(`name co "smith" and (gender eq "female" or email ew "other.test")`, ""))
+	assert.Equal(t, []uint{john.ID}, list(`name sw "j" and not (gender eq "female")`, ""))
+	assert.Equal(t, []uint{amos.ID, john.ID}, list(`id ge 3 and name co "smith"`, "&sort=name"))
+	assert.Equal(t, []uint{admin.ID}, list(`role eq "admin" and verified eq false`, ""))
+
+	// Filters combine with the other parameters.
+	assert.Equal(t, []uint{jane.ID}, list(`name co "smith"`, "&gender=female"))
+}
+
+func TestUserFilter_ReportsPosition(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	for expr, want := range map[string]string{
+		`name co "smith" and (gender eq "female"`: `unexpected end of expression at position 40`,
+		`name co "smith" and password eq "x"`:     `unknown field "password" at position 21`,
+		`name zz "smith"`:                         `unexpected "zz" at position 6`,
+		`id gt "3"`:                               `expected a number value at position 7`,
+		`verified co true`:                        `operator co does not apply to boolean field "verified" at position 10`,
+		`name eq "smith`:                          `unterminated string at position 9`,
+		`name eq "a" or`:                          `unexpected end of expression at position 15`,
+	} {
+		w := app.do(http.MethodGet, "/api/v1/users?filter="+url.QueryEscape(expr), "", token)
+		require.Equal(t, http.StatusBadRequest, w.Code, expr)
+
+		var body struct {
+			Error struct {
+				Code   string            `json:"code"`
+				Fields map[string]string `json:"fields"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, string(domain.CodeValidation), body.Error.Code)
+		assert.Equal(t, want, body.Error.Fields["filter"], expr)
+	}
+}
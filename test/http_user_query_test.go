@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func seedNamedUser(t *testing.T, app *testApp, name, email, gender string) *domain.User {
+	t.Helper()
+	u, err := app.users.Create(context.Background(), &domain.User{Name: name, Email: email, Gender: gender, Role: domain.RoleMember})
+	require.NoError(t, err)
+	return u
+}
+
+func TestUserQuery_FiltersAndSorts(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	carol := seedNamedUser(t, app, "Carol", "carol@example.com", "female")
+	alice := seedNamedUser(t, app, "Alice", "alice@example.com", "female")
+	bob := seedNamedUser(t, app, "Bob", "bob@example.com", "male")
+	dana := seedNamedUser(t, app, "Dana", "dana@other.test", "female")
+
+	page := app.listByCursor(t, token, "gender=female&sort=name")
+	assert.Equal(t, []uint{alice.ID, carol.ID, dana.ID}, page.ids())
+	assert.EqualValues(t, 3, *page.Meta.Total)
+
+	page = app.listByCursor(t, token, "email_domain=example.com&sort=-name")
+	assert.Equal(t, []uint{carol.ID, bob.ID, alice.ID}, page.ids())
+
+	page = app.listByCursor(t, token, "id=in:"+uintList(bob.ID, dana.ID, 999)+"&sort=-id")
+	assert.Equal(t, []uint{dana.ID, bob.ID}, page.ids())
+
+	// Ties on the sort fields fall back to ID order.
+	page = app.listByCursor(t, token, "sort=gender,-email&id=in:"+uintList(carol.ID, alice.ID, bob.ID, dana.ID))
+	assert.Equal(t, []uint{dana.ID, carol.ID, alice.ID, bob.ID}, page.ids())
+}
+
+func TestUserQuery_CursorFollowsSort(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	var want []uint
+	for _, name := range []string{"Zed", "Yan", "Xia", "Wes", "Vic"} {
+		want = append(want, seedNamedUser(t, app, name, name+"@example.com", "male").ID)
+	}
+
+	first := app.listByCursor(t, token, "cursor=&limit=2&sort=-name&email_domain=example.com")
+	assert.Equal(t, want[0:2], first.ids())
+	second := app.listByCursor(t, token, "limit=2&sort=-name&email_domain=example.com&cursor="+url.QueryEscape(first.Meta.Next))
+	assert.Equal(t, want[2:4], second.ids())
+	back := app.listByCursor(t, token, "limit=2&sort=-name&email_domain=example.com&cursor="+url.QueryEscape(second.Meta.Prev))
+	assert.Equal(t, want[0:2], back.ids())
+
+	w := app.do(http.MethodGet, "/api/v1/users?limit=2&sort=name&email_domain=example.com&cursor="+url.QueryEscape(first.Meta.Next), "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "cursors are bound to their sort")
+}
+
+func TestUserQuery_SortIgnoresCaseAndAccents(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	emile := seedNamedUser(t, app, "Émile", "emile@example.com", "male")
+	bob := seedNamedUser(t, app, "bob", "bob@example.com", "male")
+	alice := seedNamedUser(t, app, "Alice", "alice@example.com", "female")
+	carl := seedNamedUser(t, app, "Carl", "carl@example.com", "male")
+	alice2 := seedNamedUser(t, app, "alice", "alice2@example.com", "female")
+
+	// Names equal but for case fall back to ID order, as in the database.
+	want := []uint{alice.ID, alice2.ID, bob.ID, carl.ID, emile.ID}
+	page := app.listByCursor(t, token, "sort=name&email_domain=example.com")
+	assert.Equal(t, want, page.ids())
+
+	first := app.listByCursor(t, token, "cursor=&limit=2&sort=name&email_domain=example.com")
+	assert.Equal(t, want[0:2], first.ids())
+	second := app.listByCursor(t, token, "limit=2&sort=name&email_domain=example.com&cursor="+url.QueryEscape(first.Meta.Next))
+	assert.Equal(t, want[2:4], second.ids())
+	back := app.listByCursor(t, token, "limit=2&sort=name&email_domain=example.com&cursor="+url.QueryEscape(second.Meta.Prev))
+	assert.Equal(t, want[0:2], back.ids())
+}
+
+func TestUserQuery_RejectsInvalidParameters(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+
+	for param, query := range map[string]string{
+		"sort":         "sort=password_hash",
+		"gender":       "gender=robot",
+		"email_domain": "email_domain=a@b",
+		"id":           "id=between:1,2",
+		"verified":     "verified=maybe",
+	} {
+		w := app.do(http.MethodGet, "/api/v1/users?"+query, "", token)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Contains(t, w.Body.String(), `"`+param+`"`, query)
+	}
+
+	w := app.do(http.MethodGet, "/api/v1/users?sort=name,-name", "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func uintList(ids ...uint) string {
+	s := ""
+	for i, id := range ids {
+		if i > 0 {
+			s += ","
+		}
+		s += strconv.FormatUint(uint64(id), 10)
+	}
+	return s
+}
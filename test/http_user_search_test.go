@@ -0,0 +1,116 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+	"userHub/internal/store/memory"
+)
+
+type searchHit struct {
+	User struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	} `json:"user"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+func (a *testApp) search(t *testing.T, token, q string) []searchHit {
+	t.Helper()
+	w := a.do(http.MethodGet, "/api/v1/users/search?q="+url.QueryEscape(q), "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body struct {
+		Data []searchHit `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.Data
+}
+
+func hitIDs(hits []searchHit) []uint {
+	ids := make([]uint, len(hits))
+	for i, h := range hits {
+		ids[i] = h.User.ID
+	}
+	return ids
+}
+
+func TestUserSearch_RanksFuzzyMatches(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	jose := seedNamedUser(t, app, "José Álvarez", "jose.alvarez@example.com", "male")
+	john := seedNamedUser(t, app, "John Smith", "john@example.com", "male")
+	johanna := seedNamedUser(t, app, "Johanna Schmidt", "jo@example.com", "female")
+	seedNamedUser(t, app, "Mary Jones", "mary@example.com", "female")
+
+	// Accents and case are ignored.
+	hits := app.search(t, token, "JOSE alvarez")
+	require.NotEmpty(t, hits)
+	assert.Equal(t, jose.ID, hits[0].User.ID)
+	assert.Equal(t, 1.0, hits[0].Score)
+	assert.Equal(t, "<em>José</em> <em>Álvarez</em>", hits[0].Highlights["name"])
+	assert.Equal(t, "<em>jose</em>.<em>alvarez</em>@example.com", hits[0].Highlights["email"])
+
+	// Typos still match, closest first.
+	hits = app.search(t, token, "johm smiht")
+	require.NotEmpty(t, hits)
+	assert.Equal(t, john.ID, hits[0].User.ID)
+	assert.Equal(t, "<em>John</em> <em>Smith</em>", hits[0].Highlights["name"])
+	assert.Equal(t, "<em>john</em>@example.com", hits[0].Highlights["email"])
+
+	// A prefix finds every word it starts, shorter words first.
+	hits = app.search(t, token, "joh")
+	assert.Equal(t, []uint{john.ID, johanna.ID}, hitIDs(hits))
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+
+	assert.Empty(t, app.search(t, token, "zebra"))
+}
+
+func TestUserSearch_FollowsWrites(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	user := seedNamedUser(t, app, "Jane Smith", "jane@example.com", "female")
+
+	w := app.do(http.MethodPut, "/api/v1/users/"+uintList(user.ID), `{"name":"Jane Doe"}`, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, app.search(t, token, "smith"))
+	assert.Equal(t, []uint{user.ID}, hitIDs(app.search(t, token, "doe")))
+
+	w = app.do(http.MethodDelete, "/api/v1/users/"+uintList(user.ID), "", token)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Empty(t, app.search(t, token, "doe"))
+
+	w = app.do(http.MethodPost, "/api/v1/users/"+uintList(user.ID)+"/restore", "", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []uint{user.ID}, hitIDs(app.search(t, token, "doe")))
+}
+
+func TestUserSearch_Validation(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@corp.test", domain.RoleAdmin)
+	member := seedUser(t, app, "member@corp.test", domain.RoleMember)
+
+	adminToken := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	for _, q := range []string{"", "   ", "?!"} {
+		w := app.do(http.MethodGet, "/api/v1/users/search?q="+url.QueryEscape(q), "", adminToken)
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+		assert.Contains(t, w.Body.String(), `"q"`, q)
+	}
+
+	w := app.do(http.MethodGet, "/api/v1/users/search?q=seed", "", app.tokenFor(t, member.ID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUserSearch_MemorySearcherNeedsMemoryStore(t *testing.T) {
+	_, err := memory.NewUserSearcher(failingUsers{memory.NewUserStore()})
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	apphttp "userHub/internal/web"
+	"userHub/internal/auth"
 	"userHub/internal/service"
 	"userHub/internal/store/memory"
 )
@@ -17,10 +18,14 @@ import (
 func TestCreateUser(t *testing.T) {
 	// Use an in-memory store for fast + reliable unit testing
 	userStore := memory.NewUserStore()
-	userService := service.NewUserService(userStore)
+	userService := service.NewUserService(userStore, service.NewAuthorizer(nil), memory.NewAuditStore(), nil, 0, []byte(testSecret))
 
 	// Build router (this should accept the service OR build handlers using it internally)
-	r := apphttp.SetupRouter(userService)
+	tokens, _ := auth.NewJWTManager(auth.Config{Secret: []byte(testSecret)})
+	r := apphttp.SetupRouter(apphttp.Config{
+		Users: userService,
+		Auth:  service.NewAuthService(userStore, service.NewAuthorizer(nil), tokens, nil, memory.NewRefreshTokenStore(), nil, nil, 0),
+	})
 
 	body := `{"name":"John Doe","email":"john@example.com","gender":"male"}`
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(body))
@@ -0,0 +1,106 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+type userMetadata struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedBy uint      `json:"created_by"`
+	UpdatedBy uint      `json:"updated_by"`
+}
+
+func decodeUserMetadata(t *testing.T, body []byte) userMetadata {
+	t.Helper()
+	var u userMetadata
+	require.NoError(t, json.Unmarshal(body, &u), string(body))
+	return u
+}
+
+func TestUserTimestamps_TrackChangesAndActors(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	other := seedUser(t, app, "other@example.com", domain.RoleAdmin)
+	session := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	key := app.createAPIKey(t, session, admin.ID, "users:read", "users:write").Key
+
+	// Sign-ups have nobody to attribute them to.
+	before := time.Now().UTC().Truncate(time.Millisecond)
+	w := app.do(http.MethodPost, "/api/v1/users", `{"name":"Jane Doe","email":"jane@example.com","gender":"female"}`, "")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "created_by")
+	jane := decodeUserMetadata(t, w.Body.Bytes())
+	assert.False(t, jane.CreatedAt.Before(before))
+	assert.Equal(t, jane.CreatedAt, jane.UpdatedAt)
+	assert.Contains(t, w.Body.String(), `"created_at":"`+jane.CreatedAt.Format(time.RFC3339Nano)+`"`)
+
+	// A provisioned user is created by the key's owner.
+	w = app.scim(http.MethodPost, "/Users", `{
+		"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName":"bjensen@example.com",
+		"displayName":"Barbara Jensen",
+		"urn:userhub:params:scim:schemas:extension:2.0:User":{"gender":"female"}
+	}`, key)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	barbara := decodeSCIM[scimResource](t, w)
+	w = app.do(http.MethodGet, "/api/v1/users/"+barbara.ID, "", session)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	provisioned := decodeUserMetadata(t, w.Body.Bytes())
+	assert.Equal(t, admin.ID, provisioned.CreatedBy)
+	assert.Equal(t, admin.ID, provisioned.UpdatedBy)
+
+	// Updates move UpdatedAt and UpdatedBy only.
+	time.Sleep(2 * time.Millisecond)
+	w = app.do(http.MethodPut, fmt.Sprintf("/api/v1/users/%d", jane.ID), `{"name":"Jane Smith"}`, app.tokenForRole(t, other.ID, domain.RoleAdmin))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	updated := decodeUserMetadata(t, w.Body.Bytes())
+	assert.Equal(t, jane.CreatedAt, updated.CreatedAt)
+	assert.Zero(t, updated.CreatedBy)
+	assert.True(t, updated.UpdatedAt.After(jane.UpdatedAt))
+	assert.Equal(t, other.ID, updated.UpdatedBy)
+
+	// The metadata cannot be patched.
+	w = app.doWithHeaders(http.MethodPatch, fmt.Sprintf("/api/v1/users/%d", jane.ID), `{"created_by":7}`, session, map[string]string{"Content-Type": "application/merge-patch+json"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "CreatedBy")
+}
+
+func TestUserTimestamps_FilterAndSort(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	var users []*domain.User
+	for i := 0; i < 3; i++ {
+		time.Sleep(2 * time.Millisecond)
+		users = append(users, seedUser(t, app, fmt.Sprintf("user%d@example.com", i), domain.RoleMember))
+	}
+	since := url.QueryEscape(users[0].CreatedAt.Format(time.RFC3339Nano))
+
+	assert.Equal(t, []uint{users[1].ID, users[2].ID}, app.listByCursor(t, token, "cursor=&created_after="+since).ids())
+	assert.Equal(t, []uint{admin.ID}, app.listByCursor(t, token, "cursor=&created_before="+since).ids())
+	assert.Equal(t, []uint{users[2].ID, users[1].ID}, app.listByCursor(t, token, "cursor=&sort=-created_at&filter="+url.QueryEscape(`created_at gt "`+users[0].CreatedAt.Format(time.RFC3339Nano)+`"`)).ids())
+	assert.Equal(t, []uint{admin.ID, users[0].ID, users[1].ID, users[2].ID}, app.listByCursor(t, token, "cursor=&filter="+url.QueryEscape(`created_at ge "2000-01-01" and updated_by eq 0`)).ids())
+
+	// Keyset pages follow a timestamp sort.
+	first := app.listByCursor(t, token, "cursor=&limit=2&sort=-created_at")
+	assert.Equal(t, []uint{users[2].ID, users[1].ID}, first.ids())
+	second := app.listByCursor(t, token, "limit=2&sort=-created_at&cursor="+url.QueryEscape(first.Meta.Next))
+	assert.Equal(t, []uint{users[0].ID, admin.ID}, second.ids())
+	assert.Empty(t, second.Meta.Next)
+
+	w := app.do(http.MethodGet, "/api/v1/users?created_after=yesterday", "", token)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"created_after"`)
+}
@@ -0,0 +1,104 @@
+package http_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/domain"
+)
+
+func TestSignUpSendsVerificationLink(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	token := app.tokenFor(t, id)
+
+	w := app.do(http.MethodGet, "/api/v1/users/me", "", token)
+	assert.Contains(t, w.Body.String(), `"email_verified":false`)
+
+	verify := app.tokenMailedTo(t, "ada@example.com")
+	w = app.do(http.MethodPost, "/api/v1/users/verification/confirm", fmt.Sprintf(`{"token":%q}`, verify), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"email_verified":true`)
+
+	// Tokens are single-use, and a verified address needs no new link.
+	w = app.do(http.MethodPost, "/api/v1/users/verification/confirm", fmt.Sprintf(`{"token":%q}`, verify), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/verification", id), "", token)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestResendInvalidatesEarlierLinks(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	first := app.tokenMailedTo(t, "ada@example.com")
+
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/verification", id), "", app.tokenFor(t, id))
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	second := app.tokenMailedTo(t, "ada@example.com")
+	require.NotEqual(t, first, second)
+
+	w = app.do(http.MethodPost, "/api/v1/users/verification/confirm", fmt.Sprintf(`{"token":%q}`, first), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = app.do(http.MethodPost, "/api/v1/users/verification/confirm", fmt.Sprintf(`{"token":%q}`, second), "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Only the user or someone allowed to update them may ask for a link.
+	other := seedUser(t, app, "other@example.com", domain.RoleMember)
+	w = app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/verification", id), "", app.tokenFor(t, other.ID))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestListUsersFiltersByVerification(t *testing.T) {
+	app := newTestApp(t)
+	admin := seedUser(t, app, "admin@example.com", domain.RoleAdmin)
+	token := app.tokenForRole(t, admin.ID, domain.RoleAdmin)
+	app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	app.signUp(t, "Bob", "bob@example.com", "Sup3rSecret")
+
+	verify := app.tokenMailedTo(t, "ada@example.com")
+	w := app.do(http.MethodPost, "/api/v1/users/verification/confirm", fmt.Sprintf(`{"token":%q}`, verify), "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	emails := func(query string) []string {
+		w := app.do(http.MethodGet, "/api/v1/users"+query, "", token)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp struct {
+			Data []struct {
+				Email string `json:"email"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		out := make([]string, 0, len(resp.Data))
+		for _, u := range resp.Data {
+			out = append(out, u.Email)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"ada@example.com"}, emails("?verified=true"))
+	assert.ElementsMatch(t, []string{"admin@example.com", "bob@example.com"}, emails("?verified=false"))
+
+	w = app.do(http.MethodGet, "/api/v1/users?verified=maybe", "", token)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConfirmedEmailChangeVerifiesNewAddress(t *testing.T) {
+	app := newTestApp(t)
+	id := app.signUp(t, "Ada", "ada@example.com", "Sup3rSecret")
+	stale := app.tokenMailedTo(t, "ada@example.com")
+
+	w := app.do(http.MethodPost, fmt.Sprintf("/api/v1/users/%d/email", id), `{"email":"ada@new.example"}`, app.tokenFor(t, id))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	w = app.do(http.MethodPost, "/api/v1/users/email/confirm", fmt.Sprintf(`{"token":%q}`, app.tokenMailedTo(t, "ada@new.example")), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"email_verified":true`)
+
+	// A link sent to the old address no longer verifies anything.
+	w = app.do(http.MethodPost, "/api/v1/users/verification/confirm", fmt.Sprintf(`{"token":%q}`, stale), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
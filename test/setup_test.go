@@ -0,0 +1,221 @@
+package http_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"userHub/internal/auth"
+	"userHub/internal/domain"
+	"userHub/internal/mail"
+	"userHub/internal/service"
+	"userHub/internal/store/memory"
+	apphttp "userHub/internal/web"
+	"userHub/pkg/password"
+	"userHub/pkg/validator"
+)
+
+const testSecret = "test-secret-key-that-is-at-least-32-bytes"
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	validator.Init()
+	os.Exit(m.Run())
+}
+
+// testHashParams keep Argon2id cheap so the suite stays fast.
+var testHashParams = password.Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// testApp wires the full HTTP stack on top of the in-memory stores.
+type testApp struct {
+	router    *gin.Engine
+	tokens    domain.TokenManager
+	users     domain.UserRepository
+	orgs      domain.OrganizationRepository
+	passwords domain.PasswordService
+	outbox    *mail.Outbox
+	keys      *auth.KeyRing
+}
+
+// testSigningKey is generated once; RSA key generation is too slow to repeat
+// for every app the suite builds.
+var (
+	testSigningKeyOnce sync.Once
+	testSigningKey     []byte
+)
+
+func signingKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	testSigningKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+		testSigningKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	})
+	return testSigningKey
+}
+
+// testOptions tweak the configuration newTestApp builds with.
+type testOptions struct {
+	retention      time.Duration
+	requireIfMatch bool
+}
+
+type testOption func(*testOptions)
+
+func withRetention(d time.Duration) testOption {
+	return func(o *testOptions) { o.retention = d }
+}
+
+func withRequireIfMatch() testOption {
+	return func(o *testOptions) { o.requireIfMatch = true }
+}
+
+func newTestApp(t *testing.T, opts ...testOption) *testApp {
+	t.Helper()
+
+	o := testOptions{retention: 24 * time.Hour}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	tokens, err := auth.NewJWTManager(auth.Config{Secret: []byte(testSecret), Issuer: "userhub-test"})
+	require.NoError(t, err)
+
+	users := memory.NewUserStore()
+	orgs := memory.NewOrganizationStore()
+	require.NoError(t, service.BootstrapOrganization(context.Background(), orgs))
+
+	audit := memory.NewAuditStore()
+	groups := memory.NewGroupStore()
+	authz := service.NewAuthorizer(groups)
+	passwords := service.NewPasswordService(users, password.NewHasher(testHashParams))
+	outbox := mail.NewOutbox()
+	verifications := service.NewEmailVerificationService(users, memory.NewEmailVerificationStore(), authz, audit, outbox, time.Hour, "https://app.test")
+	refresh := memory.NewRefreshTokenStore()
+	mfa := service.NewMFAService(users, memory.NewRecoveryCodeStore(), orgs, authz, audit, "UserHub")
+	keys, err := auth.NewKeyRing(time.Hour, signingKeyPEM(t))
+	require.NoError(t, err)
+	oauthTokens := memory.NewOAuthTokenStore()
+	apiKeys := memory.NewAPIKeyStore()
+	oidc := service.NewOIDCService(keys, oauthTokens, users, "https://id.test/", time.Hour)
+	searcher, err := memory.NewUserSearcher(users)
+	require.NoError(t, err)
+	userService := service.NewUserService(users, authz, audit, verifications, o.retention, []byte(testSecret))
+	impersonations := memory.NewImpersonationStore()
+	router := apphttp.SetupRouter(apphttp.Config{
+		Users:         userService,
+		Passwords:     passwords,
+		Auth:          service.NewAuthService(users, authz, tokens, passwords, refresh, mfa, impersonations, time.Hour),
+		Organizations: service.NewOrganizationService(orgs, userService, authz),
+		Audit:         service.NewAuditService(audit, authz),
+		EmailChanges:  service.NewEmailChangeService(users, memory.NewEmailChangeStore(), audit, outbox, time.Hour, "https://app.test"),
+		Verifications: verifications,
+		Resets:        service.NewPasswordResetService(users, memory.NewPasswordResetStore(), passwords, refresh, oauthTokens, apiKeys, audit, outbox, time.Hour, 3, "https://app.test"),
+		MFA:           mfa,
+		APIKeys:       service.NewAPIKeyService(apiKeys, users, authz, audit, 24*time.Hour),
+		OAuth:         service.NewOAuthService(memory.NewOAuthClientStore(), memory.NewOAuthCodeStore(), oauthTokens, users, authz, audit, oidc, time.Minute, time.Hour, 24*time.Hour),
+		OIDC:          oidc,
+		Groups:        service.NewGroupService(groups, users, authz, audit),
+		Invitations:   service.NewInvitationService(memory.NewInvitationStore(), users, userService, authz, audit, outbox, []byte(testSecret), time.Hour, "https://app.test"),
+		Impersonation: service.NewImpersonationService(impersonations, users, authz, audit, tokens, time.Hour),
+		Search:        service.NewUserSearchService(searcher, authz),
+
+		RequireIfMatch: o.requireIfMatch,
+	})
+
+	return &testApp{router: router, tokens: tokens, users: users, orgs: orgs, passwords: passwords, outbox: outbox, keys: keys}
+}
+
+func (a *testApp) do(method, path, body, token string) *httptest.ResponseRecorder {
+	return a.doWithHeaders(method, path, body, token, nil)
+}
+
+func (a *testApp) doWithHeaders(method, path, body, token string, headers map[string]string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, req)
+	return w
+}
+
+func (a *testApp) tokenFor(t *testing.T, userID uint) string {
+	t.Helper()
+	return a.tokenForRole(t, userID, domain.RoleMember)
+}
+
+func (a *testApp) tokenForRole(t *testing.T, userID uint, role domain.Role) string {
+	t.Helper()
+	tok, err := a.tokens.Issue(&domain.Principal{UserID: userID, TenantID: domain.DefaultTenantID, Role: role})
+	require.NoError(t, err)
+	return tok.AccessToken
+}
+
+type tokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// signUp creates a user with a password through the public API and returns its ID.
+func (a *testApp) signUp(t *testing.T, name, email, pass string) uint {
+	t.Helper()
+	body := fmt.Sprintf(`{"name":%q,"email":%q,"gender":"female","password":%q}`, name, email, pass)
+	w := a.do(http.MethodPost, "/api/v1/users", body, "")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created struct {
+		ID uint `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	return created.ID
+}
+
+func (a *testApp) login(t *testing.T, email, pass string) tokenPair {
+	t.Helper()
+	w := a.do(http.MethodPost, "/api/v1/auth/login", fmt.Sprintf(`{"email":%q,"password":%q}`, email, pass), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var tok tokenPair
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tok))
+	return tok
+}
+
+var mailedToken = regexp.MustCompile(`token=([A-Za-z0-9_.-]+)`)
+
+// tokenMailedTo returns the token in the last message sent to the given address.
+func (a *testApp) tokenMailedTo(t *testing.T, to string) string {
+	t.Helper()
+	msg, ok := a.outbox.Last(to)
+	require.True(t, ok, "no mail sent to %s", to)
+	m := mailedToken.FindStringSubmatch(msg.Body)
+	require.NotNil(t, m, "no token in mail to %s", to)
+	return m[1]
+}